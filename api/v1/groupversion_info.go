@@ -21,4 +21,6 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&Subnet{}, &SubnetList{})
+	SchemeBuilder.Register(&IP{}, &IPList{})
+	SchemeBuilder.Register(&LoadBalancerIPPool{}, &LoadBalancerIPPoolList{})
 }
@@ -0,0 +1,191 @@
+// Package v1 contains API Schema definitions for the network v1 API group.
+package v1
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// IP owner kinds
+const (
+	IPOwnerKindPod = "Pod"
+	IPOwnerKindVM  = "VirtualMachine"
+)
+
+// IP labels
+const (
+	// IPSubnetLabel is the label holding the subnet of an IP
+	// (kubectl get ips -l network.zstack.io/subnet=<name>)
+	IPSubnetLabel = "network.zstack.io/subnet"
+)
+
+// IP annotations
+const (
+	// IPReservedUntilAnnotation marks an IP whose owner was deleted but which
+	// is kept for a recreated owner with the same name (StatefulSet Pods).
+	// The value is an RFC 3339 timestamp; the IP is released after it.
+	IPReservedUntilAnnotation = "network.zstack.io/reserved-until"
+)
+
+// IPOwner references the object an IP is allocated to.
+//
+// IP is cluster-scoped, so a namespaced Pod cannot be set in
+// metadata.ownerReferences. The controller garbage-collects IPs whose
+// owner no longer exists (or was recreated with another UID) instead.
+type IPOwner struct {
+	// Kind is the owner kind (Pod or VirtualMachine).
+	// +kubebuilder:validation:Enum=Pod;VirtualMachine
+	Kind string `json:"kind"`
+
+	// Namespace is the owner namespace.
+	Namespace string `json:"namespace"`
+
+	// Name is the owner name.
+	Name string `json:"name"`
+
+	// UID is the owner UID.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// IPSpec defines an IP address allocation.
+type IPSpec struct {
+	// Subnet is the name of the Subnet the IP is allocated from.
+	// +kubebuilder:validation:Required
+	Subnet string `json:"subnet"`
+
+	// IPAddress is the allocated IP address (without prefix length).
+	// +kubebuilder:validation:Required
+	IPAddress string `json:"ipAddress"`
+
+	// MACAddress is the MAC address assigned together with the IP.
+	// +optional
+	MACAddress string `json:"macAddress,omitempty"`
+
+	// LogicalSwitchPort is the name of the OVN Logical Switch Port using the IP.
+	// +optional
+	LogicalSwitchPort string `json:"logicalSwitchPort,omitempty"`
+
+	// NodeName is the node the owner runs on.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Owner is the Pod or VM the IP is allocated to.
+	Owner IPOwner `json:"owner"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="IP",type=string,JSONPath=`.spec.ipAddress`
+// +kubebuilder:printcolumn:name="MAC",type=string,JSONPath=`.spec.macAddress`
+// +kubebuilder:printcolumn:name="Subnet",type=string,JSONPath=`.spec.subnet`
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.owner.namespace`
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner.name`
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IP is the Schema for the ips API.
+// Each IP object records one allocation and is named after its subnet and
+// address (see BuildIPName), so an address can only be allocated once.
+type IP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IPSpec `json:"spec,omitempty"`
+}
+
+// OwnerKey returns the namespace/name of the IP owner
+func (ip *IP) OwnerKey() string {
+	return types.NamespacedName{Namespace: ip.Spec.Owner.Namespace, Name: ip.Spec.Owner.Name}.String()
+}
+
+// ReservedUntil returns the end of the reservation of a released IP.
+// Returns false if the IP is not reserved.
+func (ip *IP) ReservedUntil() (time.Time, bool) {
+	value, ok := ip.Annotations[IPReservedUntilAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// BuildIPName builds the IP object name for an address in a subnet.
+// Format: <subnet>.<ip>, with IPv6 colons replaced by dashes
+func BuildIPName(subnet, ip string) string {
+	return subnet + "." + strings.ReplaceAll(ip, ":", "-")
+}
+
+// +kubebuilder:object:root=true
+
+// IPList contains a list of IP
+type IPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IP `json:"items"`
+}
+
+// DeepCopyInto copies the receiver into the given *IP.
+func (in *IP) DeepCopyInto(out *IP) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy creates a deep copy of the IP.
+func (in *IP) DeepCopy() *IP {
+	if in == nil {
+		return nil
+	}
+	out := new(IP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy as runtime.Object.
+func (in *IP) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into the given *IPList.
+func (in *IPList) DeepCopyInto(out *IPList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IP, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a deep copy of the IPList.
+func (in *IPList) DeepCopy() *IPList {
+	if in == nil {
+		return nil
+	}
+	out := new(IPList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy as runtime.Object.
+func (in *IPList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
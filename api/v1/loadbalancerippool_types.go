@@ -0,0 +1,137 @@
+// Package v1 contains API Schema definitions for the network v1 API group.
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// LoadBalancerIPPool annotations
+const (
+	// LoadBalancerIPPoolAnnotation selects the pool a LoadBalancer Service
+	// gets its external IPs from. Without it, pools are tried by name.
+	LoadBalancerIPPoolAnnotation = "network.zstack.io/load-balancer-ip-pool"
+)
+
+// LoadBalancerIPPoolSpec defines a range of external IPs for LoadBalancer
+// Services.
+type LoadBalancerIPPoolSpec struct {
+	// CIDR is the IP range of the pool in CIDR notation (e.g., a ZStack
+	// public network range). Dual-stack pools list one IPv4 and one IPv6
+	// CIDR separated by a comma.
+	// +kubebuilder:validation:Required
+	CIDR string `json:"cidr"`
+
+	// ExcludeIPs is a list of IPs or IP ranges not to allocate
+	// (e.g., "192.168.100.1" or "192.168.100.1-192.168.100.10").
+	// +optional
+	ExcludeIPs []string `json:"excludeIPs,omitempty"`
+
+	// Router is the OVN Logical Router answering ARP and neighbor discovery
+	// requests for the allocated IPs. It must be connected to the network
+	// the external clients are on and bound to a chassis: a gateway router
+	// (options:chassis) or a router with a distributed gateway port. The
+	// cluster router is neither.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Router string `json:"router"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="CIDR",type=string,JSONPath=`.spec.cidr`
+// +kubebuilder:printcolumn:name="Router",type=string,JSONPath=`.spec.router`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// LoadBalancerIPPool is the Schema for the loadbalancerippools API.
+// The Service controller allocates the external IPs of LoadBalancer
+// Services from these pools; the allocations are recorded in the Services'
+// status.loadBalancer.ingress.
+type LoadBalancerIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LoadBalancerIPPoolSpec `json:"spec,omitempty"`
+}
+
+// CIDRs returns the pool CIDRs.
+func (p *LoadBalancerIPPool) CIDRs() []string {
+	return splitList(p.Spec.CIDR)
+}
+
+// +kubebuilder:object:root=true
+
+// LoadBalancerIPPoolList contains a list of LoadBalancerIPPool
+type LoadBalancerIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoadBalancerIPPool `json:"items"`
+}
+
+// DeepCopyInto copies the receiver into the given *LoadBalancerIPPoolSpec.
+func (in *LoadBalancerIPPoolSpec) DeepCopyInto(out *LoadBalancerIPPoolSpec) {
+	*out = *in
+	if in.ExcludeIPs != nil {
+		in, out := &in.ExcludeIPs, &out.ExcludeIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopyInto copies the receiver into the given *LoadBalancerIPPool.
+func (in *LoadBalancerIPPool) DeepCopyInto(out *LoadBalancerIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy creates a deep copy of the LoadBalancerIPPool.
+func (in *LoadBalancerIPPool) DeepCopy() *LoadBalancerIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy as runtime.Object.
+func (in *LoadBalancerIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into the given *LoadBalancerIPPoolList.
+func (in *LoadBalancerIPPoolList) DeepCopyInto(out *LoadBalancerIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoadBalancerIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy creates a deep copy of the LoadBalancerIPPoolList.
+func (in *LoadBalancerIPPoolList) DeepCopy() *LoadBalancerIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject returns a deep copy as runtime.Object.
+func (in *LoadBalancerIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -2,6 +2,8 @@
 package v1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -27,10 +29,16 @@ SubnetProtocolDual SubnetProtocol = "Dual"
 // SubnetSpec defines the desired state of Subnet.
 type SubnetSpec struct {
 // CIDR is the IP range for this subnet in CIDR notation.
+// Dual-stack subnets list one IPv4 and one IPv6 CIDR separated by a comma
+// (e.g., "10.244.1.0/24,fd00:10:244:1::/64").
+// A subnet can grow by widening a CIDR (e.g., /24 to /23) or by appending
+// extra, non-overlapping ranges; the first CIDR of each family is the
+// primary range. Ranges with allocated IPs cannot be removed.
 // +kubebuilder:validation:Required
 CIDR string `json:"cidr"`
 
 // Gateway is the default gateway IP for this subnet.
+// Subnets with several CIDRs list one gateway per CIDR, in the same order.
 // +kubebuilder:validation:Required
 Gateway string `json:"gateway"`
 
@@ -68,9 +76,28 @@ Default bool `json:"default,omitempty"`
 Namespaces []string `json:"namespaces,omitempty"`
 
 // EnableDHCP enables DHCP for this subnet.
+// OVN answers DHCP requests of the Pods, and of other ports attached to the
+// same Logical Switch, with the gateway, lease time, DNS servers and MTU.
 // +optional
 // +kubebuilder:default=false
 EnableDHCP bool `json:"enableDHCP,omitempty"`
+
+// DNSServers is a list of DNS servers handed out by DHCP.
+// +optional
+DNSServers []string `json:"dnsServers,omitempty"`
+
+// DHCPLeaseTime is the DHCP lease time in seconds.
+// +optional
+// +kubebuilder:validation:Minimum=0
+// +kubebuilder:default=3600
+DHCPLeaseTime int `json:"dhcpLeaseTime,omitempty"`
+
+// HealthCheckIP is the source IP of OVN Load Balancer health checks to the
+// Pods of this subnet. It must be an unused IP of the subnet and is never
+// allocated; dual-stack subnets list one IP per family separated by a comma.
+// Without it, Pods of this subnet are not health-checked.
+// +optional
+HealthCheckIP string `json:"healthCheckIP,omitempty"`
 }
 
 // SubnetStatus defines the observed state of Subnet.
@@ -91,6 +118,9 @@ AvailableIPs int `json:"availableIPs,omitempty"`
 // UsedIPs is the number of IP addresses currently allocated to Pods.
 UsedIPs int `json:"usedIPs,omitempty"`
 
+// TotalIPs is the number of usable IP addresses across all CIDRs.
+TotalIPs int `json:"totalIPs,omitempty"`
+
 // LogicalSwitch is the name of the associated OVN Logical Switch.
 LogicalSwitch string `json:"logicalSwitch,omitempty"`
 
@@ -118,6 +148,7 @@ SubnetConditionIPPoolReady       = "IPPoolReady"
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableIPs`
 // +kubebuilder:printcolumn:name="Used",type=integer,JSONPath=`.status.usedIPs`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.totalIPs`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Subnet is the Schema for the subnets API.
@@ -134,6 +165,32 @@ func (s *Subnet) IsExternalMode() bool {
 return s.Spec.ExternalLogicalSwitch != ""
 }
 
+// CIDRs returns the subnet CIDRs.
+func (s *Subnet) CIDRs() []string {
+return splitList(s.Spec.CIDR)
+}
+
+// Gateways returns the gateway IPs, in the same order as CIDRs.
+func (s *Subnet) Gateways() []string {
+return splitList(s.Spec.Gateway)
+}
+
+// HealthCheckIPs returns the Load Balancer health check source IPs.
+func (s *Subnet) HealthCheckIPs() []string {
+return splitList(s.Spec.HealthCheckIP)
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(list string) []string {
+var items []string
+for _, item := range strings.Split(list, ",") {
+if item = strings.TrimSpace(item); item != "" {
+items = append(items, item)
+}
+}
+return items
+}
+
 // IsUnderlayMode returns true if the subnet uses underlay networking
 func (s *Subnet) IsUnderlayMode() bool {
 return s.Spec.VlanID > 0 || s.Spec.Provider != ""
@@ -196,6 +253,11 @@ func (in *SubnetSpec) DeepCopyInto(out *SubnetSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy creates a deep copy of the SubnetSpec.
@@ -56,6 +56,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
@@ -457,6 +458,21 @@ func registerControllers(
 		return fmt.Errorf("failed to setup Pod controller: %w", err)
 	}
 
+	// Rebuild IP allocators from existing Pods and OVN ports before the
+	// Pod controller allocates any new IP
+	klog.V(2).Info("Registering IP allocator syncer")
+	allocatorSyncer := ovn.NewAllocatorSyncer(
+		mgr.GetClient(),
+		mgr.GetCache(),
+		recorder,
+		ovnClient,
+		subnetReconciler,
+		podReconciler,
+	)
+	if err := mgr.Add(allocatorSyncer); err != nil {
+		return fmt.Errorf("failed to add IP allocator syncer: %w", err)
+	}
+
 	// 3. Register Service Controller
 	// The Service controller manages Service load balancing via OVN Load Balancers
 	klog.V(2).Info("Registering Service controller")
@@ -471,6 +487,30 @@ func registerControllers(
 		return fmt.Errorf("failed to setup Service controller: %w", err)
 	}
 
+	// Rebuild the Load Balancer index from OVN and delete the Load Balancers
+	// of Services removed while the controller was down
+	if err := mgr.Add(manager.RunnableFunc(serviceReconciler.SyncLoadBalancers)); err != nil {
+		return fmt.Errorf("failed to add Load Balancer syncer: %w", err)
+	}
+
+	// Create the cluster Load Balancer Group and move Service Load Balancers
+	// attached to switches by earlier versions into it
+	if err := mgr.Add(manager.RunnableFunc(serviceReconciler.SyncLoadBalancerGroup)); err != nil {
+		return fmt.Errorf("failed to add Load Balancer group syncer: %w", err)
+	}
+
+	// Record the Service hairpin SNAT IP in NB_Global
+	if err := mgr.Add(manager.RunnableFunc(serviceReconciler.SyncHairpinSNATIP)); err != nil {
+		return fmt.Errorf("failed to add hairpin SNAT IP syncer: %w", err)
+	}
+
+	// Write the node IP template variables used by NodePort Load Balancers
+	klog.V(2).Info("Registering node template controller")
+	nodeTemplateReconciler := ovn.NewNodeTemplateReconciler(mgr.GetClient(), ovnClient)
+	if err := nodeTemplateReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("failed to setup node template controller: %w", err)
+	}
+
 	// 4. Register NetworkPolicy Controller
 	// The Policy controller manages NetworkPolicy via OVN ACLs
 	klog.V(2).Info("Registering NetworkPolicy controller")
@@ -478,6 +518,8 @@ func registerControllers(
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		OVNClient: ovnClient,
+
+		HairpinSNATIP: cfg.Network.ServiceHairpinSNATIP,
 	}
 	if err := policyController.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed to setup NetworkPolicy controller: %w", err)
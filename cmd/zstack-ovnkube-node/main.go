@@ -12,6 +12,7 @@
 // 2. Configuring OVS br-int bridge for Pod networking
 // 3. Setting up VXLAN tunnels for cross-node communication
 // 4. Configuring gateway for external traffic
+// 5. Validating the DPDK environment (hugepages, OVS-DPDK) when DPDK is enabled
 //
 // Usage:
 //
@@ -65,6 +66,7 @@ import (
 	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/cni"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/config"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/dpdk"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/node"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
 )
@@ -321,9 +323,28 @@ func runNodeAgent(ctx context.Context, opts *Options, cfg *config.Config) error
 		return fmt.Errorf("failed to configure OVS: %w", err)
 	}
 
+	// Validate the DPDK environment before serving DPDK Pods
+	var dpdkPorts *dpdk.PortManager
+	if cfg.IsDPDKEnabled() {
+		klog.Info("Validating DPDK environment...")
+		dpdkConfig := &dpdk.DPDKConfig{
+			Enabled:             true,
+			SocketDir:           cfg.DPDK.SocketDir,
+			SocketMode:          cfg.DPDK.SocketMode,
+			Queues:              cfg.DPDK.Queues,
+			RxqDescriptors:      cfg.DPDK.RxqDescriptors,
+			CPUManagerStateFile: cfg.DPDK.CPUManagerStateFile,
+		}
+		if err := dpdk.NewDetector(dpdkConfig).ValidateDPDKEnvironment(cfg.DPDK.MinHugepagesMB); err != nil {
+			return fmt.Errorf("invalid DPDK environment: %w", err)
+		}
+		dpdkPorts = dpdk.NewPortManager(dpdkConfig)
+		klog.Infof("DPDK enabled, vhost-user sockets in %s", cfg.DPDK.SocketDir)
+	}
+
 	// Create and start CNI Server
 	klog.Info("Starting CNI Server...")
-	cniHandler := cni.NewHandler(k8sClient, ovnClient, cfg.Network.MTU)
+	cniHandler := cni.NewHandler(k8sClient, ovnClient, cfg.Network.MTU, dpdkPorts)
 	cniServer := cni.NewServer(opts.CNISocketPath, cniHandler)
 	if err := cniServer.Start(); err != nil {
 		return fmt.Errorf("failed to start CNI server: %w", err)
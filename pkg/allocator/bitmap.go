@@ -139,6 +139,20 @@ func (b *Bitmap) FindFirstClear() int {
 	return -1
 }
 
+// SetBits returns the indexes of all allocated bits, in ascending order
+func (b *Bitmap) SetBits() []int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	indexes := make([]int, 0, b.allocated)
+	for i := 0; i < b.size; i++ {
+		if b.bits[i/8]&(1<<uint(i%8)) != 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
 // Size returns the total number of bits
 func (b *Bitmap) Size() int {
 	return b.size
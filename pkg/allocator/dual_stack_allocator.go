@@ -0,0 +1,397 @@
+// Package allocator provides IP address allocation algorithms.
+//
+// DualStackAllocator combines one FamilyAllocator per IP family. A
+// single-stack subnet has one family; a dual-stack subnet has an IPv4 and
+// an IPv6 family, and every allocation takes one address from each.
+//
+// A subnet grows by resizing its allocator: Resize builds an allocator for
+// the new CIDRs carrying over every allocation, and the old allocator
+// forwards all calls to it from then on, so holders of the old pointer keep
+// working.
+package allocator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// DualStackAllocator manages IP allocation for a subnet with one or more
+// CIDRs per IP family.
+//
+// Thread Safety: All methods are thread-safe.
+type DualStackAllocator struct {
+	// mu guards next; allocations hold it for reading so that Resize
+	// copies a consistent state
+	mu sync.RWMutex
+
+	// next is the allocator replacing this one after Resize
+	next *DualStackAllocator
+
+	// families holds one allocator per family, in order of the first CIDR
+	// of each family
+	families []*FamilyAllocator
+
+	// cidrs and excludeIPs are the configuration the allocator was built from
+	cidrs      []string
+	excludeIPs []string
+}
+
+// NewDualStackAllocator creates a new allocator, grouping CIDRs by family.
+//
+// Parameters:
+//   - cidrs: Subnet CIDRs, the first one of each family being the primary
+//     range (e.g., ["10.244.1.0/24", "fd00:10:244:1::/64", "10.244.2.0/24"])
+//   - excludeIPs: IPs and ranges to exclude, applied to the matching range
+//
+// Returns:
+//   - *DualStackAllocator: Allocator instance
+//   - error: Error if a CIDR is invalid or two CIDRs overlap
+func NewDualStackAllocator(cidrs []string, excludeIPs []string) (*DualStackAllocator, error) {
+	if len(cidrs) == 0 {
+		return nil, fmt.Errorf("at least one CIDR is required")
+	}
+
+	a := &DualStackAllocator{
+		cidrs:      append([]string(nil), cidrs...),
+		excludeIPs: append([]string(nil), excludeIPs...),
+	}
+	byFamily := map[bool]*FamilyAllocator{}
+	for _, cidr := range cidrs {
+		r, err := NewSubnetAllocator(cidr, excludeIPs)
+		if err != nil {
+			return nil, err
+		}
+		family, ok := byFamily[r.IsIPv6()]
+		if !ok {
+			family = &FamilyAllocator{}
+			byFamily[r.IsIPv6()] = family
+			a.families = append(a.families, family)
+		}
+		for _, other := range family.ranges {
+			if other.Contains(r.Subnet().IP) || r.Contains(other.Subnet().IP) {
+				return nil, fmt.Errorf("CIDR %s overlaps %s", cidr, other.Subnet())
+			}
+		}
+		family.ranges = append(family.ranges, r)
+	}
+
+	return a, nil
+}
+
+// Resize creates an allocator for new CIDRs and exclude IPs, carrying over
+// every allocation of this one. From then on, this allocator forwards all
+// calls to the new one.
+//
+// Allocated IPs that become excluded stay allocated in the new allocator
+// until released.
+//
+// Parameters:
+//   - cidrs: New subnet CIDRs, see NewDualStackAllocator
+//   - excludeIPs: New IPs and ranges to exclude
+//
+// Returns:
+//   - *DualStackAllocator: Allocator for the new configuration
+//   - error: Error if the configuration is invalid or an allocated IP is in
+//     none of the new CIDRs
+func (a *DualStackAllocator) Resize(cidrs []string, excludeIPs []string) (*DualStackAllocator, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.next != nil {
+		return nil, fmt.Errorf("allocator for %s has already been resized", a.string())
+	}
+
+	next, err := NewDualStackAllocator(cidrs, excludeIPs)
+	if err != nil {
+		return nil, err
+	}
+	for _, family := range a.families {
+		for _, ip := range family.AllocatedIPs() {
+			err := next.Allocate(ip)
+			var allocated *IPAlreadyAllocatedError
+			if err != nil && !errors.As(err, &allocated) {
+				return nil, fmt.Errorf("allocated IP %s cannot be kept: %w", ip, err)
+			}
+		}
+	}
+
+	a.next = next
+	return next, nil
+}
+
+// HasConfig checks if the allocator was built from the given CIDRs and
+// exclude IPs.
+func (a *DualStackAllocator) HasConfig(cidrs []string, excludeIPs []string) bool {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+	return equalStrings(live.cidrs, cidrs) && equalStrings(live.excludeIPs, excludeIPs)
+}
+
+// AllocateNext allocates the next available IP of every family.
+//
+// Either one IP per family is allocated, or none: if a family is
+// exhausted, the IPs already taken from the other families are released.
+//
+// Returns:
+//   - []net.IP: Allocated IPs, in family order
+//   - error: SubnetExhaustedError if a family has no available IPs
+func (a *DualStackAllocator) AllocateNext() ([]net.IP, error) {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	ips := make([]net.IP, 0, len(live.families))
+	for _, family := range live.families {
+		ip, err := family.AllocateNext()
+		if err != nil {
+			for i, allocated := range ips {
+				_ = live.families[i].Release(allocated)
+			}
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// AllocateRequested allocates the requested IPs, and the next available IP
+// of every family without a requested IP.
+//
+// Either one IP per family is allocated, or none.
+//
+// Parameters:
+//   - requested: Requested IPs, at most one per family
+//
+// Returns:
+//   - []net.IP: Allocated IPs, in family order
+//   - error: IPAlreadyAllocatedError if a requested IP is taken,
+//     IPOutOfRangeError if a requested IP is in none of the subnets
+func (a *DualStackAllocator) AllocateRequested(requested []net.IP) ([]net.IP, error) {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	byFamily := make(map[*FamilyAllocator]net.IP, len(requested))
+	for _, ip := range requested {
+		family := live.familyFor(ip)
+		if family == nil {
+			return nil, &IPOutOfRangeError{IP: ip.String(), Subnet: live.string()}
+		}
+		if _, ok := byFamily[family]; ok {
+			return nil, fmt.Errorf("only one IP per IP family can be requested, got %v", requested)
+		}
+		byFamily[family] = ip
+	}
+
+	ips := make([]net.IP, 0, len(live.families))
+	for _, family := range live.families {
+		var err error
+		ip, ok := byFamily[family]
+		if ok {
+			err = family.Allocate(ip)
+		} else {
+			ip, err = family.AllocateNext()
+		}
+		if err != nil {
+			for i, allocated := range ips {
+				_ = live.families[i].Release(allocated)
+			}
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// AllocateMissing completes an allocation that lacks some families, e.g.
+// after the subnet gained a family: the held IPs are kept, and the next
+// available IP is allocated in every family without one.
+//
+// Either every missing IP is allocated, or none.
+//
+// Parameters:
+//   - held: IPs already allocated to the caller, at most one per family
+//
+// Returns:
+//   - []net.IP: Held and allocated IPs, in family order
+//   - error: SubnetExhaustedError if a missing family has no available IPs
+func (a *DualStackAllocator) AllocateMissing(held []net.IP) ([]net.IP, error) {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	byFamily := make(map[*FamilyAllocator]net.IP, len(held))
+	for _, ip := range held {
+		if family := live.familyFor(ip); family != nil {
+			byFamily[family] = ip
+		}
+	}
+
+	ips := make([]net.IP, 0, len(live.families))
+	var allocated []net.IP
+	for _, family := range live.families {
+		ip, ok := byFamily[family]
+		if !ok || !family.IsAllocated(ip) {
+			var err error
+			if ok {
+				err = family.Allocate(ip)
+			} else {
+				ip, err = family.AllocateNext()
+			}
+			if err != nil {
+				for _, ip := range allocated {
+					_ = live.familyFor(ip).Release(ip)
+				}
+				return nil, err
+			}
+			allocated = append(allocated, ip)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// Allocate allocates a specific IP address in its family.
+//
+// Returns:
+//   - error: IPAlreadyAllocatedError if IP is already allocated,
+//     IPOutOfRangeError if IP is in none of the subnets
+func (a *DualStackAllocator) Allocate(ip net.IP) error {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	family := live.familyFor(ip)
+	if family == nil {
+		return &IPOutOfRangeError{IP: ip.String(), Subnet: live.string()}
+	}
+	return family.Allocate(ip)
+}
+
+// Release releases an allocated IP address back to its family.
+func (a *DualStackAllocator) Release(ip net.IP) error {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	family := live.familyFor(ip)
+	if family == nil {
+		return &IPOutOfRangeError{IP: ip.String(), Subnet: live.string()}
+	}
+	return family.Release(ip)
+}
+
+// IsAllocated checks if an IP is currently allocated.
+func (a *DualStackAllocator) IsAllocated(ip net.IP) bool {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	family := live.familyFor(ip)
+	return family != nil && family.IsAllocated(ip)
+}
+
+// Contains checks if an IP belongs to one of the subnets.
+func (a *DualStackAllocator) Contains(ip net.IP) bool {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+	return live.familyFor(ip) != nil
+}
+
+// Available returns the number of allocations still possible, which is the
+// smallest number of available IPs across families.
+func (a *DualStackAllocator) Available() int {
+	return a.minOverFamilies((*FamilyAllocator).Available)
+}
+
+// Size returns the number of allocations the subnet can hold, which is the
+// smallest number of usable IPs across families.
+func (a *DualStackAllocator) Size() int {
+	return a.minOverFamilies((*FamilyAllocator).Size)
+}
+
+// Used returns the largest number of allocated IPs across families.
+func (a *DualStackAllocator) Used() int {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	used := 0
+	for _, family := range live.families {
+		if n := family.Used(); n > used {
+			used = n
+		}
+	}
+	return used
+}
+
+// Families returns the per-family allocators, in family order.
+func (a *DualStackAllocator) Families() []*FamilyAllocator {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+	return live.families
+}
+
+// String returns the subnet CIDRs separated by commas, grouped by family.
+func (a *DualStackAllocator) String() string {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+	return live.string()
+}
+
+func (a *DualStackAllocator) string() string {
+	cidrs := make([]string, 0, len(a.families))
+	for _, family := range a.families {
+		cidrs = append(cidrs, family.String())
+	}
+	return strings.Join(cidrs, ",")
+}
+
+// acquire follows the chain of resized allocators and returns the current
+// one with its read lock held. The caller must release the lock.
+func (a *DualStackAllocator) acquire() *DualStackAllocator {
+	for {
+		a.mu.RLock()
+		if a.next == nil {
+			return a
+		}
+		next := a.next
+		a.mu.RUnlock()
+		a = next
+	}
+}
+
+// minOverFamilies returns the smallest count across families
+func (a *DualStackAllocator) minOverFamilies(count func(*FamilyAllocator) int) int {
+	live := a.acquire()
+	defer live.mu.RUnlock()
+
+	smallest := count(live.families[0])
+	for _, family := range live.families[1:] {
+		if n := count(family); n < smallest {
+			smallest = n
+		}
+	}
+	return smallest
+}
+
+// familyFor returns the family allocator with a range containing ip
+func (a *DualStackAllocator) familyFor(ip net.IP) *FamilyAllocator {
+	if ip == nil {
+		return nil
+	}
+	for _, family := range a.families {
+		if family.Contains(ip) {
+			return family
+		}
+	}
+	return nil
+}
+
+// equalStrings checks if two string slices have the same elements in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
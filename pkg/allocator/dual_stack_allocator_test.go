@@ -0,0 +1,250 @@
+package allocator
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDualStackAllocatorAllocateNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidrs   []string
+		exclude []string
+		want    []string
+	}{
+		{
+			name:  "ipv4 only",
+			cidrs: []string{"10.244.1.0/24"},
+			want:  []string{"10.244.1.1"},
+		},
+		{
+			name:  "ipv6 /64",
+			cidrs: []string{"fd00:10:244:1::/64"},
+			want:  []string{"fd00:10:244:1::1"},
+		},
+		{
+			name:    "second range used when the first is full",
+			cidrs:   []string{"10.244.1.0/30", "10.244.2.0/24"},
+			exclude: []string{"10.244.1.1", "10.244.1.2", "10.244.2.1"},
+			want:    []string{"10.244.2.2"},
+		},
+		{
+			name:    "dual-stack with gateways excluded",
+			cidrs:   []string{"10.244.1.0/24", "fd00:10:244:1::/64"},
+			exclude: []string{"10.244.1.1", "fd00:10:244:1::1"},
+			want:    []string{"10.244.1.2", "fd00:10:244:1::2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc, err := NewDualStackAllocator(tt.cidrs, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewDualStackAllocator() error = %v", err)
+			}
+			ips, err := alloc.AllocateNext()
+			if err != nil {
+				t.Fatalf("AllocateNext() error = %v", err)
+			}
+			if len(ips) != len(tt.want) {
+				t.Fatalf("AllocateNext() = %v, want %v", ips, tt.want)
+			}
+			for i, ip := range ips {
+				if ip.String() != tt.want[i] {
+					t.Errorf("AllocateNext()[%d] = %s, want %s", i, ip, tt.want[i])
+				}
+				if !alloc.IsAllocated(ip) {
+					t.Errorf("IsAllocated(%s) = false after allocation", ip)
+				}
+			}
+		})
+	}
+}
+
+func TestDualStackAllocatorRollback(t *testing.T) {
+	// The IPv6 family has a single usable address, so the second
+	// allocation must fail without leaking an IPv4 address
+	alloc, err := NewDualStackAllocator([]string{"10.244.1.0/24", "fd00::/127"}, nil)
+	if err != nil {
+		t.Fatalf("NewDualStackAllocator() error = %v", err)
+	}
+	if _, err := alloc.AllocateNext(); err != nil {
+		t.Fatalf("first AllocateNext() error = %v", err)
+	}
+	if _, err := alloc.AllocateNext(); err == nil {
+		t.Fatal("second AllocateNext() succeeded, want SubnetExhaustedError")
+	}
+	if alloc.IsAllocated(net.ParseIP("10.244.1.2")) {
+		t.Error("IPv4 address leaked after failed dual-stack allocation")
+	}
+}
+
+func TestDualStackAllocatorAllocateMissing(t *testing.T) {
+	alloc, err := NewDualStackAllocator([]string{"10.244.1.0/24", "fd00:10:244:1::/64"}, nil)
+	if err != nil {
+		t.Fatalf("NewDualStackAllocator() error = %v", err)
+	}
+	held := net.ParseIP("10.244.1.7")
+	if err := alloc.Allocate(held); err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+
+	// The held IPv4 address is kept, only the IPv6 address is allocated
+	ips, err := alloc.AllocateMissing([]net.IP{held})
+	if err != nil {
+		t.Fatalf("AllocateMissing() error = %v", err)
+	}
+	if len(ips) != 2 || !ips[0].Equal(held) || ips[1].To4() != nil {
+		t.Fatalf("AllocateMissing() = %v, want [%s <ipv6>]", ips, held)
+	}
+	if alloc.Families()[0].Used() != 1 {
+		t.Errorf("IPv4 Used() = %d, want 1", alloc.Families()[0].Used())
+	}
+}
+
+func TestNewDualStackAllocatorRejectsOverlap(t *testing.T) {
+	if _, err := NewDualStackAllocator([]string{"10.244.0.0/23", "10.244.1.0/24"}, nil); err == nil {
+		t.Error("NewDualStackAllocator() with overlapping CIDRs succeeded, want error")
+	}
+}
+
+func TestDualStackAllocatorResize(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidrs    []string
+		wantSize int
+		wantErr  bool
+	}{
+		{
+			name:     "widen /24 to /23",
+			cidrs:    []string{"10.244.0.0/23"},
+			wantSize: 510,
+		},
+		{
+			name:     "add a range",
+			cidrs:    []string{"10.244.1.0/24", "10.244.7.0/24"},
+			wantSize: 508,
+		},
+		{
+			name:    "drop the range in use",
+			cidrs:   []string{"10.244.7.0/24"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc, err := NewDualStackAllocator([]string{"10.244.1.0/24"}, []string{"10.244.1.1"})
+			if err != nil {
+				t.Fatalf("NewDualStackAllocator() error = %v", err)
+			}
+			ips, err := alloc.AllocateNext()
+			if err != nil {
+				t.Fatalf("AllocateNext() error = %v", err)
+			}
+
+			resized, err := alloc.Resize(tt.cidrs, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Resize() succeeded, want error")
+				}
+				if !alloc.IsAllocated(ips[0]) {
+					t.Errorf("IsAllocated(%s) = false after failed Resize()", ips[0])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resize() error = %v", err)
+			}
+			if resized.Size() != tt.wantSize {
+				t.Errorf("Size() = %d, want %d", resized.Size(), tt.wantSize)
+			}
+			if !resized.IsAllocated(ips[0]) {
+				t.Errorf("IsAllocated(%s) = false after Resize()", ips[0])
+			}
+
+			// The old allocator forwards to the resized one
+			next, err := alloc.AllocateNext()
+			if err != nil {
+				t.Fatalf("AllocateNext() after Resize() error = %v", err)
+			}
+			if !resized.IsAllocated(next[0]) {
+				t.Errorf("IP %s allocated through the old allocator is not in the resized one", next[0])
+			}
+		})
+	}
+}
+
+func TestDualStackAllocatorAllocateRequested(t *testing.T) {
+	cidrs := []string{"10.244.1.0/24", "fd00:10:244:1::/64"}
+	exclude := []string{"10.244.1.1", "fd00:10:244:1::1"}
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "ipv4 requested, ipv6 next available",
+			requested: []string{"10.244.1.50"},
+			want:      []string{"10.244.1.50", "fd00:10:244:1::2"},
+		},
+		{
+			name:      "both families requested",
+			requested: []string{"fd00:10:244:1::50", "10.244.1.50"},
+			want:      []string{"10.244.1.50", "fd00:10:244:1::50"},
+		},
+		{
+			name:      "gateway requested",
+			requested: []string{"10.244.1.1"},
+			wantErr:   true,
+		},
+		{
+			name:      "out of range",
+			requested: []string{"10.244.2.50"},
+			wantErr:   true,
+		},
+		{
+			name:      "two IPs of one family",
+			requested: []string{"10.244.1.50", "10.244.1.51"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc, err := NewDualStackAllocator(cidrs, exclude)
+			if err != nil {
+				t.Fatalf("NewDualStackAllocator() error = %v", err)
+			}
+			requested := make([]net.IP, 0, len(tt.requested))
+			for _, ip := range tt.requested {
+				requested = append(requested, net.ParseIP(ip))
+			}
+
+			used := alloc.Used()
+			ips, err := alloc.AllocateRequested(requested)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("AllocateRequested() = %v, want error", ips)
+				}
+				if alloc.Used() != used {
+					t.Errorf("Used() = %d after failed allocation, want %d", alloc.Used(), used)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AllocateRequested() error = %v", err)
+			}
+			if len(ips) != len(tt.want) {
+				t.Fatalf("AllocateRequested() = %v, want %v", ips, tt.want)
+			}
+			for i, ip := range ips {
+				if ip.String() != tt.want[i] {
+					t.Errorf("AllocateRequested()[%d] = %s, want %s", i, ip, tt.want[i])
+				}
+			}
+		})
+	}
+}
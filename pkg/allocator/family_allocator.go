@@ -0,0 +1,146 @@
+// Package allocator provides IP address allocation algorithms.
+//
+// FamilyAllocator combines the SubnetAllocators of one IP family. A subnet
+// starts with a single range per family and grows by adding ranges, so an
+// allocation takes the first available IP of the first range with room.
+package allocator
+
+import (
+	"errors"
+	"math"
+	"net"
+	"strings"
+)
+
+// FamilyAllocator manages IP allocation for one IP family made of one or
+// more non-overlapping ranges.
+//
+// Thread Safety: All methods are thread-safe.
+type FamilyAllocator struct {
+	// ranges holds one allocator per CIDR, in CIDR order
+	ranges []*SubnetAllocator
+}
+
+// AllocateNext allocates the next available IP, trying each range in order.
+//
+// Returns:
+//   - net.IP: Allocated IP address
+//   - error: SubnetExhaustedError if every range is exhausted
+func (f *FamilyAllocator) AllocateNext() (net.IP, error) {
+	for _, r := range f.ranges {
+		ip, err := r.AllocateNext()
+		if err == nil {
+			return ip, nil
+		}
+		var exhausted *SubnetExhaustedError
+		if !errors.As(err, &exhausted) {
+			return nil, err
+		}
+	}
+	return nil, &SubnetExhaustedError{Subnet: f.String()}
+}
+
+// Allocate allocates a specific IP address in its range.
+//
+// Returns:
+//   - error: IPAlreadyAllocatedError if IP is already allocated,
+//     IPOutOfRangeError if IP is in none of the ranges
+func (f *FamilyAllocator) Allocate(ip net.IP) error {
+	r := f.rangeFor(ip)
+	if r == nil {
+		return &IPOutOfRangeError{IP: ip.String(), Subnet: f.String()}
+	}
+	return r.Allocate(ip)
+}
+
+// Release releases an allocated IP address back to its range.
+func (f *FamilyAllocator) Release(ip net.IP) error {
+	r := f.rangeFor(ip)
+	if r == nil {
+		return &IPOutOfRangeError{IP: ip.String(), Subnet: f.String()}
+	}
+	return r.Release(ip)
+}
+
+// IsAllocated checks if an IP is currently allocated.
+func (f *FamilyAllocator) IsAllocated(ip net.IP) bool {
+	r := f.rangeFor(ip)
+	return r != nil && r.IsAllocated(ip)
+}
+
+// Contains checks if an IP belongs to one of the ranges.
+func (f *FamilyAllocator) Contains(ip net.IP) bool {
+	return f.rangeFor(ip) != nil
+}
+
+// Available returns the number of available IPs across ranges.
+// The count saturates at math.MaxInt for very large (IPv6) subnets.
+func (f *FamilyAllocator) Available() int {
+	return saturatedSum(f.ranges, (*SubnetAllocator).Available)
+}
+
+// Used returns the number of allocated IPs across ranges.
+func (f *FamilyAllocator) Used() int {
+	return saturatedSum(f.ranges, (*SubnetAllocator).Used)
+}
+
+// Size returns the total number of usable IPs across ranges.
+// The count saturates at math.MaxInt for very large (IPv6) subnets.
+func (f *FamilyAllocator) Size() int {
+	return saturatedSum(f.ranges, (*SubnetAllocator).Size)
+}
+
+// AllocatedIPs returns the allocated IPs of every range, excluding the
+// excluded IPs.
+func (f *FamilyAllocator) AllocatedIPs() []net.IP {
+	var ips []net.IP
+	for _, r := range f.ranges {
+		ips = append(ips, r.AllocatedIPs()...)
+	}
+	return ips
+}
+
+// Ranges returns the per-range allocators, in CIDR order.
+func (f *FamilyAllocator) Ranges() []*SubnetAllocator {
+	return f.ranges
+}
+
+// IsIPv6 returns true if the family is IPv6.
+func (f *FamilyAllocator) IsIPv6() bool {
+	return f.ranges[0].IsIPv6()
+}
+
+// String returns the range CIDRs separated by commas.
+func (f *FamilyAllocator) String() string {
+	cidrs := make([]string, 0, len(f.ranges))
+	for _, r := range f.ranges {
+		cidrs = append(cidrs, r.Subnet().String())
+	}
+	return strings.Join(cidrs, ",")
+}
+
+// rangeFor returns the range allocator whose subnet contains ip
+func (f *FamilyAllocator) rangeFor(ip net.IP) *SubnetAllocator {
+	if ip == nil {
+		return nil
+	}
+	for _, r := range f.ranges {
+		if r.Contains(ip) {
+			return r
+		}
+	}
+	return nil
+}
+
+// saturatedSum sums count over the ranges, saturating at math.MaxInt
+func saturatedSum(ranges []*SubnetAllocator, count func(*SubnetAllocator) int) int {
+	sum := 0
+	for _, r := range ranges {
+		n := count(r)
+		if n > math.MaxInt-sum {
+			return math.MaxInt
+		}
+		sum += n
+	}
+	return sum
+}
@@ -0,0 +1,155 @@
+// Package allocator provides IP address allocation algorithms.
+//
+// This file implements the sets tracking allocated address offsets within a
+// subnet. Small ranges (all IPv4 subnets up to /8, small IPv6 subnets) use
+// a Bitmap. Larger ranges such as an IPv6 /64 cannot be backed by a bitmap,
+// so only the allocated offsets are stored.
+package allocator
+
+import (
+	"math/big"
+	"sync"
+)
+
+// maxBitmapSize is the largest range tracked with a Bitmap (2 MiB of bits)
+const maxBitmapSize = 1 << 24
+
+// offsetSet tracks allocated offsets within a range [0, size)
+type offsetSet interface {
+	// set marks an offset as allocated, returning false if already allocated
+	set(offset *big.Int) bool
+
+	// clear marks an offset as available
+	clear(offset *big.Int)
+
+	// isSet checks if an offset is allocated
+	isSet(offset *big.Int) bool
+
+	// findFirstClear returns the lowest available offset, or nil if none
+	findFirstClear() *big.Int
+
+	// allocated returns the number of allocated offsets
+	allocated() int
+
+	// list returns all allocated offsets
+	list() []*big.Int
+}
+
+// newOffsetSet creates the offset set suited for a range of the given size
+func newOffsetSet(size *big.Int) offsetSet {
+	if size.IsInt64() && size.Int64() <= maxBitmapSize {
+		return &bitmapOffsetSet{bitmap: NewBitmap(int(size.Int64()))}
+	}
+	return &sparseOffsetSet{
+		size:    new(big.Int).Set(size),
+		offsets: make(map[string]struct{}),
+	}
+}
+
+// bitmapOffsetSet is an offsetSet backed by a Bitmap
+type bitmapOffsetSet struct {
+	bitmap *Bitmap
+}
+
+func (s *bitmapOffsetSet) set(offset *big.Int) bool {
+	return s.bitmap.Set(int(offset.Int64())) == nil
+}
+
+func (s *bitmapOffsetSet) clear(offset *big.Int) {
+	_ = s.bitmap.Clear(int(offset.Int64()))
+}
+
+func (s *bitmapOffsetSet) isSet(offset *big.Int) bool {
+	return s.bitmap.IsSet(int(offset.Int64()))
+}
+
+func (s *bitmapOffsetSet) findFirstClear() *big.Int {
+	index := s.bitmap.FindFirstClear()
+	if index == -1 {
+		return nil
+	}
+	return big.NewInt(int64(index))
+}
+
+func (s *bitmapOffsetSet) allocated() int {
+	return s.bitmap.Allocated()
+}
+
+func (s *bitmapOffsetSet) list() []*big.Int {
+	indexes := s.bitmap.SetBits()
+	offsets := make([]*big.Int, 0, len(indexes))
+	for _, index := range indexes {
+		offsets = append(offsets, big.NewInt(int64(index)))
+	}
+	return offsets
+}
+
+// sparseOffsetSet is an offsetSet storing only the allocated offsets
+//
+// Finding the first available offset is O(n) in the number of allocated
+// offsets, which is bounded by the number of Pods in the subnet.
+type sparseOffsetSet struct {
+	mu      sync.RWMutex
+	size    *big.Int
+	offsets map[string]struct{}
+}
+
+func (s *sparseOffsetSet) set(offset *big.Int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := offset.String()
+	if _, ok := s.offsets[key]; ok {
+		return false
+	}
+	s.offsets[key] = struct{}{}
+	return true
+}
+
+func (s *sparseOffsetSet) clear(offset *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.offsets, offset.String())
+}
+
+func (s *sparseOffsetSet) isSet(offset *big.Int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.offsets[offset.String()]
+	return ok
+}
+
+func (s *sparseOffsetSet) findFirstClear() *big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// At most len(offsets) candidates are taken, so this terminates
+	// before reaching size unless the range is full
+	offset := big.NewInt(0)
+	one := big.NewInt(1)
+	for offset.Cmp(s.size) < 0 {
+		if _, ok := s.offsets[offset.String()]; !ok {
+			return offset
+		}
+		offset.Add(offset, one)
+	}
+	return nil
+}
+
+func (s *sparseOffsetSet) allocated() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.offsets)
+}
+
+func (s *sparseOffsetSet) list() []*big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	offsets := make([]*big.Int, 0, len(s.offsets))
+	for key := range s.offsets {
+		offset, _ := new(big.Int).SetString(key, 10)
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
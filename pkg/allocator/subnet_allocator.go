@@ -1,20 +1,24 @@
 // Package allocator provides IP address allocation algorithms.
 //
-// SubnetAllocator implements efficient IP allocation for a subnet using bitmap algorithm.
-// This is the same approach used by OVN-Kubernetes for IP address management.
+// SubnetAllocator implements efficient IP allocation for an IPv4 or IPv6 subnet.
+// Addresses are tracked as offsets from the network address, so IPv6 subnets
+// work the same way as IPv4 subnets. Ranges up to 2^24 addresses use a bitmap;
+// larger ranges (e.g. an IPv6 /64) only store the allocated offsets.
 //
 // Reference: OVN-Kubernetes pkg/allocator/ip/subnet/allocator.go
 package allocator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"net"
 	"strings"
 	"sync"
 )
 
-// SubnetAllocator manages IP allocation for a single subnet.
-// It uses a bitmap to track allocated IPs efficiently.
+// SubnetAllocator manages IP allocation for a single IPv4 or IPv6 subnet.
+// It tracks allocated IPs as offsets from the network address.
 //
 // Thread Safety: All methods are thread-safe.
 //
@@ -30,25 +34,26 @@ type SubnetAllocator struct {
 	// subnet is the CIDR network being managed
 	subnet *net.IPNet
 
-	// bitmap tracks allocated IPs
-	// Index 0 = first usable IP (network address + 1)
-	bitmap *Bitmap
+	// offsets tracks allocated IPs
+	// Offset 0 = first usable IP (network address + 1)
+	offsets offsetSet
 
 	// excludeIPs is a set of IPs that should not be allocated
 	// Key is the IP string representation
 	excludeIPs map[string]struct{}
 
 	// baseIP is the first IP in the subnet (network address)
+	// 4 bytes for IPv4, 16 bytes for IPv6
 	baseIP net.IP
 
 	// size is the total number of usable IPs
-	size int
+	size *big.Int
 }
 
 // NewSubnetAllocator creates a new subnet allocator.
 //
 // Parameters:
-//   - cidr: Subnet CIDR string (e.g., "10.244.1.0/24")
+//   - cidr: Subnet CIDR string (e.g., "10.244.1.0/24" or "fd00:10:244:1::/64")
 //   - excludeIPs: List of IPs to exclude from allocation (e.g., gateway)
 //     Supports single IPs ("10.244.1.1") and ranges ("10.244.1.100-10.244.1.110")
 //
@@ -67,20 +72,28 @@ func NewSubnetAllocator(cidr string, excludeIPs []string) (*SubnetAllocator, err
 
 	// Calculate subnet size (number of usable IPs)
 	// For IPv4: total IPs - network address - broadcast address
+	// For IPv6: total IPs - network address (there is no broadcast)
 	ones, bits := subnet.Mask.Size()
-	totalIPs := 1 << (bits - ones)
-
-	// Usable IPs exclude network and broadcast addresses
-	usableIPs := totalIPs - 2
-	if usableIPs <= 0 {
+	usableIPs := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	if subnet.IP.To4() != nil {
+		usableIPs.Sub(usableIPs, big.NewInt(2))
+	} else {
+		usableIPs.Sub(usableIPs, big.NewInt(1))
+	}
+	if usableIPs.Sign() <= 0 {
 		return nil, fmt.Errorf("subnet %s is too small for allocation", cidr)
 	}
 
+	baseIP := subnet.IP.Mask(subnet.Mask)
+	if ip4 := baseIP.To4(); ip4 != nil {
+		baseIP = ip4
+	}
+
 	allocator := &SubnetAllocator{
 		subnet:     subnet,
-		bitmap:     NewBitmap(usableIPs),
+		offsets:    newOffsetSet(usableIPs),
 		excludeIPs: make(map[string]struct{}),
-		baseIP:     subnet.IP.Mask(subnet.Mask),
+		baseIP:     baseIP,
 		size:       usableIPs,
 	}
 
@@ -110,6 +123,10 @@ func (a *SubnetAllocator) parseAndExclude(exclude string) error {
 			return fmt.Errorf("invalid IP in range")
 		}
 
+		if (startIP.To4() == nil) != (endIP.To4() == nil) {
+			return fmt.Errorf("IP range mixes address families")
+		}
+
 		// Mark all IPs in range as excluded
 		for ip := startIP; !ip.Equal(incrementIP(endIP)); ip = incrementIP(ip) {
 			if err := a.excludeIP(ip); err != nil {
@@ -133,14 +150,14 @@ func (a *SubnetAllocator) parseAndExclude(exclude string) error {
 
 // excludeIP marks a single IP as excluded (pre-allocated).
 func (a *SubnetAllocator) excludeIP(ip net.IP) error {
-	index, err := a.ipToIndex(ip)
+	offset, err := a.ipToOffset(ip)
 	if err != nil {
 		return err
 	}
 
 	a.excludeIPs[ip.String()] = struct{}{}
-	// Ignore error if already set
-	_ = a.bitmap.Set(index)
+	// Ignore if already set
+	a.offsets.set(offset)
 	return nil
 }
 
@@ -160,16 +177,16 @@ func (a *SubnetAllocator) AllocateNext() (net.IP, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	index := a.bitmap.FindFirstClear()
-	if index == -1 {
+	offset := a.offsets.findFirstClear()
+	if offset == nil {
 		return nil, &SubnetExhaustedError{Subnet: a.subnet.String()}
 	}
 
-	if err := a.bitmap.Set(index); err != nil {
-		return nil, fmt.Errorf("failed to allocate IP: %w", err)
+	if !a.offsets.set(offset) {
+		return nil, fmt.Errorf("failed to allocate IP: offset %s is already set", offset)
 	}
 
-	return a.indexToIP(index), nil
+	return a.offsetToIP(offset), nil
 }
 
 // Allocate allocates a specific IP address.
@@ -188,19 +205,15 @@ func (a *SubnetAllocator) Allocate(ip net.IP) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	index, err := a.ipToIndex(ip)
+	offset, err := a.ipToOffset(ip)
 	if err != nil {
 		return err
 	}
 
-	if a.bitmap.IsSet(index) {
+	if !a.offsets.set(offset) {
 		return &IPAlreadyAllocatedError{IP: ip.String()}
 	}
 
-	if err := a.bitmap.Set(index); err != nil {
-		return fmt.Errorf("failed to allocate IP %s: %w", ip, err)
-	}
-
 	return nil
 }
 
@@ -224,12 +237,13 @@ func (a *SubnetAllocator) Release(ip net.IP) error {
 		return fmt.Errorf("cannot release excluded IP %s", ip)
 	}
 
-	index, err := a.ipToIndex(ip)
+	offset, err := a.ipToOffset(ip)
 	if err != nil {
 		return err
 	}
 
-	return a.bitmap.Clear(index)
+	a.offsets.clear(offset)
+	return nil
 }
 
 // IsAllocated checks if an IP is currently allocated.
@@ -243,27 +257,46 @@ func (a *SubnetAllocator) IsAllocated(ip net.IP) bool {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	index, err := a.ipToIndex(ip)
+	offset, err := a.ipToOffset(ip)
 	if err != nil {
 		return false
 	}
 
-	return a.bitmap.IsSet(index)
+	return a.offsets.isSet(offset)
+}
+
+// AllocatedIPs returns the allocated IPs, excluding the excluded IPs.
+func (a *SubnetAllocator) AllocatedIPs() []net.IP {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var ips []net.IP
+	for _, offset := range a.offsets.list() {
+		ip := a.offsetToIP(offset)
+		if _, excluded := a.excludeIPs[ip.String()]; excluded {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
 }
 
 // Available returns the number of available IPs.
+// The count saturates at math.MaxInt for very large (IPv6) subnets.
 func (a *SubnetAllocator) Available() int {
-	return a.bitmap.Available()
+	available := new(big.Int).Sub(a.size, big.NewInt(int64(a.offsets.allocated())))
+	return saturatedInt(available)
 }
 
 // Used returns the number of allocated IPs.
 func (a *SubnetAllocator) Used() int {
-	return a.bitmap.Allocated()
+	return a.offsets.allocated()
 }
 
 // Size returns the total number of usable IPs in the subnet.
+// The count saturates at math.MaxInt for very large (IPv6) subnets.
 func (a *SubnetAllocator) Size() int {
-	return a.size
+	return saturatedInt(a.size)
 }
 
 // Subnet returns the subnet CIDR.
@@ -271,69 +304,84 @@ func (a *SubnetAllocator) Subnet() *net.IPNet {
 	return a.subnet
 }
 
-// ipToIndex converts an IP address to a bitmap index.
-// Index 0 corresponds to the first usable IP (network address + 1).
-func (a *SubnetAllocator) ipToIndex(ip net.IP) (int, error) {
-	// Normalize IP to 4-byte representation for IPv4
-	ip = ip.To4()
-	if ip == nil {
-		ip = ip.To16()
-	}
+// IsIPv6 returns true if the allocator manages an IPv6 subnet.
+func (a *SubnetAllocator) IsIPv6() bool {
+	return a.baseIP.To4() == nil
+}
 
-	if !a.subnet.Contains(ip) {
-		return -1, &IPOutOfRangeError{IP: ip.String(), Subnet: a.subnet.String()}
-	}
+// Contains checks if an IP belongs to the allocator's subnet.
+func (a *SubnetAllocator) Contains(ip net.IP) bool {
+	return a.subnet.Contains(ip)
+}
 
-	// Calculate offset from base IP
-	// Skip network address (index 0 = baseIP + 1)
-	baseIP := a.baseIP.To4()
-	if baseIP == nil {
-		baseIP = a.baseIP.To16()
+// ipToOffset converts an IP address to an offset.
+// Offset 0 corresponds to the first usable IP (network address + 1).
+func (a *SubnetAllocator) ipToOffset(ip net.IP) (*big.Int, error) {
+	if ip == nil || !a.subnet.Contains(ip) {
+		return nil, &IPOutOfRangeError{IP: ip.String(), Subnet: a.subnet.String()}
 	}
 
-	ipInt := ipToInt(ip)
-	baseInt := ipToInt(baseIP)
-	// Ensure we don't underflow
-	if ipInt <= baseInt {
-		return -1, &IPOutOfRangeError{IP: ip.String(), Subnet: a.subnet.String()}
-	}
-	offset := int(ipInt - baseInt - 1)
-	if offset < 0 || offset >= a.size {
-		return -1, &IPOutOfRangeError{IP: ip.String(), Subnet: a.subnet.String()}
+	// Calculate offset from base IP
+	// Skip network address (offset 0 = baseIP + 1)
+	offset := new(big.Int).Sub(ipToBigInt(ip), ipToBigInt(a.baseIP))
+	offset.Sub(offset, big.NewInt(1))
+	if offset.Sign() < 0 || offset.Cmp(a.size) >= 0 {
+		return nil, &IPOutOfRangeError{IP: ip.String(), Subnet: a.subnet.String()}
 	}
 
 	return offset, nil
 }
 
-// indexToIP converts a bitmap index to an IP address.
-func (a *SubnetAllocator) indexToIP(index int) net.IP {
-	// Index 0 = baseIP + 1 (skip network address)
-	baseInt := ipToInt(a.baseIP.To4())
-	ipInt := baseInt + uint32(index) + 1
-	return intToIP(ipInt)
+// offsetToIP converts an offset to an IP address.
+func (a *SubnetAllocator) offsetToIP(offset *big.Int) net.IP {
+	// Offset 0 = baseIP + 1 (skip network address)
+	n := new(big.Int).Add(ipToBigInt(a.baseIP), offset)
+	n.Add(n, big.NewInt(1))
+	return bigIntToIP(n, len(a.baseIP))
 }
 
-// ipToInt converts an IPv4 address to a uint32.
-func ipToInt(ip net.IP) uint32 {
-	ip = ip.To4()
-	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+// ipToBigInt converts an IP address to a big.Int.
+// IPv4 addresses are converted from their 4-byte form.
+func ipToBigInt(ip net.IP) *big.Int {
+	if ip4 := ip.To4(); ip4 != nil {
+		return new(big.Int).SetBytes(ip4)
+	}
+	return new(big.Int).SetBytes(ip.To16())
+}
+
+// bigIntToIP converts a big.Int to an IP address of the given length
+// (net.IPv4len or net.IPv6len).
+func bigIntToIP(n *big.Int, length int) net.IP {
+	ip := make(net.IP, length)
+	n.FillBytes(ip)
+	if length == net.IPv4len {
+		return net.IPv4(ip[0], ip[1], ip[2], ip[3])
+	}
+	return ip
 }
 
-// intToIP converts a uint32 to an IPv4 address.
-func intToIP(n uint32) net.IP {
-	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+// saturatedInt converts a non-negative big.Int to int, saturating at math.MaxInt.
+func saturatedInt(n *big.Int) int {
+	if !n.IsInt64() || n.Int64() > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(n.Int64())
 }
 
 // incrementIP returns the next IP address.
 func incrementIP(ip net.IP) net.IP {
-	ip = ip.To4()
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	} else {
+		ip = ip.To16()
+	}
 	if ip == nil {
 		return nil
 	}
-	result := make(net.IP, 4)
+	result := make(net.IP, len(ip))
 	copy(result, ip)
 
-	for i := 3; i >= 0; i-- {
+	for i := len(result) - 1; i >= 0; i-- {
 		result[i]++
 		if result[i] != 0 {
 			break
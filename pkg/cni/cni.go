@@ -214,6 +214,16 @@ func CmdAdd(args *skel.CmdArgs) error {
 		return fmt.Errorf("failed to parse CNI result: %w", err)
 	}
 
+	// The CNI result types predate the socketPath interface field, so the
+	// result of a DPDK Pod is printed as built by the server
+	if hasSocketPath(resp.Result) {
+		if err := checkSocketPathVersion(cniConfig.CNIVersion); err != nil {
+			return err
+		}
+		_, err := os.Stdout.Write(resp.Result)
+		return err
+	}
+
 	return types.PrintResult(result, cniConfig.CNIVersion)
 }
 
@@ -395,3 +405,33 @@ func parseCNIResult(data []byte) (types.Result, error) {
 
 	return result, nil
 }
+
+// checkSocketPathVersion checks that a CNI configuration version can carry
+// the vhost-user socketPath of a DPDK Pod. Only results of the implemented
+// spec version are printed without conversion, which would drop socketPath.
+func checkSocketPathVersion(cniVersion string) error {
+	if cniVersion != current.ImplementedSpecVersion {
+		return fmt.Errorf("DPDK Pods require CNI version %s to return the vhost-user socketPath, got %q",
+			current.ImplementedSpecVersion, cniVersion)
+	}
+	return nil
+}
+
+// hasSocketPath checks if a CNI result has an interface with a vhost-user
+// socket path
+func hasSocketPath(data []byte) bool {
+	var result struct {
+		Interfaces []struct {
+			SocketPath string `json:"socketPath"`
+		} `json:"interfaces"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return false
+	}
+	for _, iface := range result.Interfaces {
+		if iface.SocketPath != "" {
+			return true
+		}
+	}
+	return false
+}
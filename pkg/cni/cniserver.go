@@ -109,18 +109,28 @@ type Response struct {
 // PodNetworkInfo contains the network configuration for a Pod
 // This is populated by the controller and used by CNI Server
 type PodNetworkInfo struct {
-	// IPAddress is the Pod's IP address with prefix length
+	// IPAddress is the Pod's primary IP address with prefix length
 	// Example: "10.244.1.5/24"
 	IPAddress string
 
+	// IPAddresses are all Pod IP addresses with prefix length, one per IP
+	// family. If empty, IPAddress is the only address.
+	// Example: ["10.244.1.5/24", "fd00:10:244:1::5/64"]
+	IPAddresses []string
+
 	// MACAddress is the Pod's MAC address
 	// Example: "0a:58:0a:f4:01:05"
 	MACAddress string
 
-	// Gateway is the default gateway IP
+	// Gateway is the default gateway IP of the primary IP family
 	// Example: "10.244.1.1"
 	Gateway string
 
+	// Gateways are the default gateway IPs, one per IP family.
+	// If empty, Gateway is the only gateway.
+	// Example: ["10.244.1.1", "fd00:10:244:1::1"]
+	Gateways []string
+
 	// Routes are additional routes for the Pod
 	Routes []Route
 
@@ -132,6 +142,10 @@ type PodNetworkInfo struct {
 
 	// LogicalSwitchPort is the OVN LSP name
 	LogicalSwitchPort string
+
+	// SocketPath is the vhost-user socket path of a DPDK Pod, empty for a
+	// veth interface
+	SocketPath string
 }
 
 // Route represents a network route
@@ -341,8 +355,8 @@ func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	klog.V(4).Infof("CNI ADD success: pod=%s/%s, ip=%s",
-		req.PodNamespace, req.PodName, info.IPAddress)
+	klog.V(4).Infof("CNI ADD success: pod=%s/%s, ips=%v",
+		req.PodNamespace, req.PodName, info.IPAddresses)
 
 	s.sendResponse(w, &Response{Result: result})
 }
@@ -461,37 +475,60 @@ func buildCNIResult(info *PodNetworkInfo) ([]byte, error) {
 		return nil, fmt.Errorf("PodNetworkInfo is nil")
 	}
 
-	// Parse IP address and prefix
-	ip, ipNet, err := net.ParseCIDR(info.IPAddress)
-	if err != nil {
-		return nil, fmt.Errorf("invalid IP address %s: %w", info.IPAddress, err)
+	ipAddresses := info.IPAddresses
+	if len(ipAddresses) == 0 {
+		ipAddresses = []string{info.IPAddress}
+	}
+	gateways := info.Gateways
+	if len(gateways) == 0 {
+		gateways = []string{info.Gateway}
 	}
 
-	// Determine IP version
-	ipVersion := "4"
-	if ip.To4() == nil {
-		ipVersion = "6"
+	// Build one IP entry and default route per address
+	ips := make([]map[string]interface{}, 0, len(ipAddresses))
+	defaultRoutes := make([]map[string]interface{}, 0, len(ipAddresses))
+	for _, ipAddress := range ipAddresses {
+		// Parse IP address and prefix
+		ip, _, err := net.ParseCIDR(ipAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %s: %w", ipAddress, err)
+		}
+
+		// Determine IP version
+		ipVersion := "4"
+		defaultDst := "0.0.0.0/0"
+		if ip.To4() == nil {
+			ipVersion = "6"
+			defaultDst = "::/0"
+		}
+
+		gateway := gatewayForIP(ip, gateways)
+		ips = append(ips, map[string]interface{}{
+			"version":   ipVersion,
+			"address":   ipAddress,
+			"gateway":   gateway,
+			"interface": 0,
+		})
+		defaultRoutes = append(defaultRoutes, map[string]interface{}{
+			"dst": defaultDst,
+			"gw":  gateway,
+		})
 	}
 
 	// Build CNI result structure
 	// Reference: https://www.cni.dev/docs/spec/#success
+	iface := map[string]interface{}{
+		"name":    "eth0",
+		"mac":     info.MACAddress,
+		"sandbox": info.SandboxID,
+	}
+	if info.SocketPath != "" {
+		iface["socketPath"] = info.SocketPath
+	}
 	result := map[string]interface{}{
 		"cniVersion": "1.0.0",
-		"interfaces": []map[string]interface{}{
-			{
-				"name":    "eth0",
-				"mac":     info.MACAddress,
-				"sandbox": info.SandboxID,
-			},
-		},
-		"ips": []map[string]interface{}{
-			{
-				"version":   ipVersion,
-				"address":   info.IPAddress,
-				"gateway":   info.Gateway,
-				"interface": 0,
-			},
-		},
+		"interfaces": []map[string]interface{}{iface},
+		"ips":        ips,
 	}
 
 	// Add routes if present
@@ -508,29 +545,32 @@ func buildCNIResult(info *PodNetworkInfo) ([]byte, error) {
 		}
 		result["routes"] = routes
 	} else {
-		// Add default route if no routes specified
-		defaultDst := "0.0.0.0/0"
-		if ipVersion == "6" {
-			defaultDst = "::/0"
-		}
-		result["routes"] = []map[string]interface{}{
-			{
-				"dst": defaultDst,
-				"gw":  info.Gateway,
-			},
-		}
+		// Add default routes if no routes specified
+		result["routes"] = defaultRoutes
 	}
 
 	// Add DNS configuration (empty for now, can be extended)
 	result["dns"] = map[string]interface{}{}
 
-	// Calculate prefix length from IPNet
-	ones, _ := ipNet.Mask.Size()
-	_ = ones // Used in address field above
-
 	return json.Marshal(result)
 }
 
+// gatewayForIP returns the gateway of the same IP family as ip
+// Falls back to the first gateway if no gateway matches.
+func gatewayForIP(ip net.IP, gateways []string) string {
+	isIPv4 := ip.To4() != nil
+	for _, gateway := range gateways {
+		gwIP := net.ParseIP(gateway)
+		if gwIP != nil && (gwIP.To4() != nil) == isIPv4 {
+			return gateway
+		}
+	}
+	if len(gateways) > 0 {
+		return gateways[0]
+	}
+	return ""
+}
+
 // IsRunning returns whether the server is running
 func (s *Server) IsRunning() bool {
 	s.mu.Lock()
@@ -0,0 +1,145 @@
+package cni
+
+import (
+	"encoding/json"
+	"testing"
+
+	current "github.com/containernetworking/cni/pkg/types/100"
+)
+
+func TestBuildCNIResultDualStack(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       *PodNetworkInfo
+		wantIPs    []string
+		wantGWs    []string
+		wantRoutes []string
+	}{
+		{
+			name: "single-stack primary fields only",
+			info: &PodNetworkInfo{
+				IPAddress: "10.244.1.5/24",
+				Gateway:   "10.244.1.1",
+			},
+			wantIPs:    []string{"10.244.1.5/24"},
+			wantGWs:    []string{"10.244.1.1"},
+			wantRoutes: []string{"0.0.0.0/0"},
+		},
+		{
+			name: "dual-stack",
+			info: &PodNetworkInfo{
+				IPAddress:   "10.244.1.5/24",
+				IPAddresses: []string{"10.244.1.5/24", "fd00:10:244:1::5/64"},
+				Gateway:     "10.244.1.1",
+				Gateways:    []string{"10.244.1.1", "fd00:10:244:1::1"},
+			},
+			wantIPs:    []string{"10.244.1.5/24", "fd00:10:244:1::5/64"},
+			wantGWs:    []string{"10.244.1.1", "fd00:10:244:1::1"},
+			wantRoutes: []string{"0.0.0.0/0", "::/0"},
+		},
+		{
+			name: "gateways matched by family",
+			info: &PodNetworkInfo{
+				IPAddresses: []string{"fd00:10:244:1::5/64", "10.244.1.5/24"},
+				Gateways:    []string{"10.244.1.1", "fd00:10:244:1::1"},
+			},
+			wantIPs:    []string{"fd00:10:244:1::5/64", "10.244.1.5/24"},
+			wantGWs:    []string{"fd00:10:244:1::1", "10.244.1.1"},
+			wantRoutes: []string{"::/0", "0.0.0.0/0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := buildCNIResult(tt.info)
+			if err != nil {
+				t.Fatalf("buildCNIResult() error = %v", err)
+			}
+
+			result := &current.Result{}
+			if err := json.Unmarshal(data, result); err != nil {
+				t.Fatalf("failed to parse result: %v", err)
+			}
+
+			if len(result.IPs) != len(tt.wantIPs) {
+				t.Fatalf("got %d IPs, want %d", len(result.IPs), len(tt.wantIPs))
+			}
+			for i, ip := range result.IPs {
+				if ip.Address.String() != tt.wantIPs[i] {
+					t.Errorf("IPs[%d].Address = %s, want %s", i, ip.Address.String(), tt.wantIPs[i])
+				}
+				if ip.Gateway.String() != tt.wantGWs[i] {
+					t.Errorf("IPs[%d].Gateway = %s, want %s", i, ip.Gateway, tt.wantGWs[i])
+				}
+			}
+
+			if len(result.Routes) != len(tt.wantRoutes) {
+				t.Fatalf("got %d routes, want %d", len(result.Routes), len(tt.wantRoutes))
+			}
+			for i, route := range result.Routes {
+				if route.Dst.String() != tt.wantRoutes[i] {
+					t.Errorf("Routes[%d].Dst = %s, want %s", i, route.Dst.String(), tt.wantRoutes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildCNIResultSocketPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		socketPath string
+	}{
+		{"veth interface", ""},
+		{"vhost-user port", "/var/run/openvswitch/vhost-user-default-dpdk-app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := buildCNIResult(&PodNetworkInfo{
+				IPAddress:  "10.244.1.5/24",
+				Gateway:    "10.244.1.1",
+				SocketPath: tt.socketPath,
+			})
+			if err != nil {
+				t.Fatalf("buildCNIResult() error = %v", err)
+			}
+
+			var result struct {
+				Interfaces []struct {
+					SocketPath string `json:"socketPath"`
+				} `json:"interfaces"`
+			}
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("failed to parse result: %v", err)
+			}
+			if len(result.Interfaces) != 1 || result.Interfaces[0].SocketPath != tt.socketPath {
+				t.Errorf("interfaces = %+v, want socketPath %q", result.Interfaces, tt.socketPath)
+			}
+			if got := hasSocketPath(data); got != (tt.socketPath != "") {
+				t.Errorf("hasSocketPath() = %v, want %v", got, tt.socketPath != "")
+			}
+		})
+	}
+}
+
+func TestCheckSocketPathVersion(t *testing.T) {
+	tests := []struct {
+		cniVersion string
+		wantErr    bool
+	}{
+		{current.ImplementedSpecVersion, false},
+		{"0.4.0", true},
+		{"0.3.1", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cniVersion, func(t *testing.T) {
+			err := checkSocketPathVersion(tt.cniVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSocketPathVersion(%q) error = %v, wantErr %v", tt.cniVersion, err, tt.wantErr)
+			}
+		})
+	}
+}
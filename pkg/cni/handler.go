@@ -6,6 +6,7 @@
 // - OVN NB DB (to create/delete Logical Switch Ports)
 // - OVS (to configure br-int ports)
 // - Linux networking (to create veth pairs and configure IPs)
+// - OVS-DPDK (to create vhost-user ports for Pods requesting DPDK)
 //
 // Request Flow for CNI ADD:
 //
@@ -38,6 +39,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/dpdk"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
 )
@@ -57,16 +59,17 @@ const (
 // PodNetworkAnnotation is the structure stored in Pod annotation
 // This is set by the controller and read by the CNI handler
 type PodNetworkAnnotation struct {
-	// IPAddresses contains the allocated IP addresses with prefix
-	// Example: ["10.244.1.5/24"]
+	// IPAddresses contains the allocated IP addresses with prefix,
+	// one per IP family
+	// Example: ["10.244.1.5/24", "fd00:10:244:1::5/64"]
 	IPAddresses []string `json:"ip_addresses"`
 
 	// MACAddress is the allocated MAC address
 	// Example: "0a:58:0a:f4:01:05"
 	MACAddress string `json:"mac_address"`
 
-	// GatewayIPs contains the gateway IP addresses
-	// Example: ["10.244.1.1"]
+	// GatewayIPs contains the gateway IP addresses, one per IP family
+	// Example: ["10.244.1.1", "fd00:10:244:1::1"]
 	GatewayIPs []string `json:"gateway_ips"`
 
 	// Routes contains additional routes
@@ -90,6 +93,9 @@ type Handler struct {
 
 	// mtu is the MTU for Pod interfaces
 	mtu int
+
+	// dpdkPorts manages vhost-user ports, nil if DPDK is disabled on the node
+	dpdkPorts *dpdk.PortManager
 }
 
 // NewHandler creates a new CNI request handler
@@ -98,10 +104,11 @@ type Handler struct {
 //   - k8sClient: Kubernetes client
 //   - ovnClient: OVN database client
 //   - mtu: MTU for Pod interfaces
+//   - dpdkPorts: vhost-user port manager, nil if DPDK is disabled on the node
 //
 // Returns:
 //   - *Handler: Handler instance
-func NewHandler(k8sClient client.Client, ovnClient *ovndb.Client, mtu int) *Handler {
+func NewHandler(k8sClient client.Client, ovnClient *ovndb.Client, mtu int, dpdkPorts *dpdk.PortManager) *Handler {
 	if mtu == 0 {
 		mtu = DefaultMTU
 	}
@@ -109,6 +116,7 @@ func NewHandler(k8sClient client.Client, ovnClient *ovndb.Client, mtu int) *Hand
 		k8sClient: k8sClient,
 		ovnClient: ovnClient,
 		mtu:       mtu,
+		dpdkPorts: dpdkPorts,
 	}
 }
 
@@ -120,6 +128,9 @@ func NewHandler(k8sClient client.Client, ovnClient *ovndb.Client, mtu int) *Hand
 // 3. Configures network interface (veth, IP, routes)
 // 4. Adds OVS port to br-int
 //
+// Pods requesting DPDK get a vhost-user port on br-int instead of a veth
+// pair; the DPDK application in the Pod configures the addresses itself.
+//
 // Parameters:
 //   - ctx: Context for cancellation
 //   - req: CNI request
@@ -148,20 +159,24 @@ func (h *Handler) HandleAdd(ctx context.Context, req *Request) (*PodNetworkInfo,
 		return nil, fmt.Errorf("no gateway IPs in Pod annotation")
 	}
 
-	// Use first IP and gateway for now (single-stack)
-	ipAddress := annotation.IPAddresses[0]
-	gateway := annotation.GatewayIPs[0]
+	pod, err := h.getPod(ctx, req.PodNamespace, req.PodName)
+	if err != nil {
+		return nil, err
+	}
+	if util.PodRequestsDPDK(pod) {
+		return h.addDPDKPort(ctx, req, pod, annotation)
+	}
 
-	// Configure network interface
+	// Configure network interface with every IP family
 	cfg := &InterfaceConfig{
 		PodNamespace: req.PodNamespace,
 		PodName:      req.PodName,
 		ContainerID:  req.ContainerID,
 		NetNS:        req.Netns,
 		IfName:       req.IfName,
-		IPAddress:    ipAddress,
+		IPAddresses:  annotation.IPAddresses,
 		MACAddress:   annotation.MACAddress,
-		Gateway:      gateway,
+		Gateways:     annotation.GatewayIPs,
 		MTU:          h.mtu,
 	}
 
@@ -172,17 +187,19 @@ func (h *Handler) HandleAdd(ctx context.Context, req *Request) (*PodNetworkInfo,
 
 	// Build response
 	info := &PodNetworkInfo{
-		IPAddress:         ipAddress,
+		IPAddress:         annotation.IPAddresses[0],
+		IPAddresses:       annotation.IPAddresses,
 		MACAddress:        ifInfo.MACAddress,
-		Gateway:           gateway,
+		Gateway:           annotation.GatewayIPs[0],
+		Gateways:          annotation.GatewayIPs,
 		Routes:            annotation.Routes,
 		MTU:               h.mtu,
 		SandboxID:         req.Netns,
 		LogicalSwitchPort: annotation.LogicalSwitchPort,
 	}
 
-	klog.V(2).Infof("HandleAdd success: pod=%s/%s, ip=%s, mac=%s",
-		req.PodNamespace, req.PodName, ipAddress, ifInfo.MACAddress)
+	klog.V(2).Infof("HandleAdd success: pod=%s/%s, ips=%v, mac=%s",
+		req.PodNamespace, req.PodName, annotation.IPAddresses, ifInfo.MACAddress)
 
 	return info, nil
 }
@@ -191,7 +208,7 @@ func (h *Handler) HandleAdd(ctx context.Context, req *Request) (*PodNetworkInfo,
 //
 // This function:
 // 1. Removes OVS port from br-int
-// 2. Deletes veth pair
+// 2. Deletes veth pair, or the vhost-user socket of a DPDK Pod
 // 3. Deletes OVN Logical Switch Port
 // 4. Releases IP address (done by controller)
 //
@@ -214,7 +231,13 @@ func (h *Handler) HandleDel(ctx context.Context, req *Request) error {
 		ContainerID:  req.ContainerID,
 	}
 
-	if err := TeardownInterface(cfg); err != nil {
+	// The Pod may already be gone, so DPDK Pods are recognized by their port
+	if h.dpdkPorts != nil && h.dpdkPorts.PortExists(req.PodNamespace, req.PodName) {
+		if err := h.dpdkPorts.DeletePort(req.PodNamespace, req.PodName); err != nil {
+			klog.Warningf("HandleDel: failed to delete DPDK port for pod %s/%s: %v",
+				req.PodNamespace, req.PodName, err)
+		}
+	} else if err := TeardownInterface(cfg); err != nil {
 		// Log but don't fail - DEL should be idempotent
 		klog.Warningf("HandleDel: failed to teardown interface for pod %s/%s: %v",
 			req.PodNamespace, req.PodName, err)
@@ -254,8 +277,8 @@ func (h *Handler) HandleDel(ctx context.Context, req *Request) error {
 //
 // This function verifies:
 // 1. OVS port exists in br-int
-// 2. Veth pair exists
-// 3. Container interface has correct IP configuration
+// 2. Veth pair exists, or the vhost-user port of a DPDK Pod
+// 3. Container interface has every IP address in the annotation
 // 4. OVN LSP exists
 //
 // Parameters:
@@ -269,7 +292,11 @@ func (h *Handler) HandleCheck(ctx context.Context, req *Request) error {
 		req.PodNamespace, req.PodName, req.ContainerID)
 
 	// Get Pod annotation to know expected configuration
-	annotation, err := h.getPodAnnotation(ctx, req.PodNamespace, req.PodName)
+	pod, err := h.getPod(ctx, req.PodNamespace, req.PodName)
+	if err != nil {
+		return fmt.Errorf("failed to get Pod annotation: %w", err)
+	}
+	annotation, err := parsePodAnnotation(pod)
 	if err != nil {
 		return fmt.Errorf("failed to get Pod annotation: %w", err)
 	}
@@ -290,11 +317,15 @@ func (h *Handler) HandleCheck(ctx context.Context, req *Request) error {
 		ContainerID:  req.ContainerID,
 		NetNS:        req.Netns,
 		IfName:       req.IfName,
-		IPAddress:    annotation.IPAddresses[0],
+		IPAddresses:  annotation.IPAddresses,
 		MACAddress:   annotation.MACAddress,
 	}
 
-	if err := CheckInterface(cfg); err != nil {
+	if util.PodRequestsDPDK(pod) {
+		if err := h.checkDPDKPort(req, pod.Annotations[util.PodDPDKSocketAnnotationKey], annotation.MACAddress); err != nil {
+			return fmt.Errorf("DPDK port check failed: %w", err)
+		}
+	} else if err := CheckInterface(cfg); err != nil {
 		return fmt.Errorf("interface check failed: %w", err)
 	}
 
@@ -356,12 +387,25 @@ func (h *Handler) waitForPodAnnotation(ctx context.Context, namespace, name stri
 
 // getPodAnnotation gets and parses the Pod network annotation
 func (h *Handler) getPodAnnotation(ctx context.Context, namespace, name string) (*PodNetworkAnnotation, error) {
-	// Get Pod from Kubernetes API
+	pod, err := h.getPod(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return parsePodAnnotation(pod)
+}
+
+// getPod gets a Pod from the Kubernetes API
+func (h *Handler) getPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
 	pod := &corev1.Pod{}
 	if err := h.k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
 		return nil, fmt.Errorf("failed to get Pod: %w", err)
 	}
+	return pod, nil
+}
 
+// parsePodAnnotation parses the network annotation of a Pod
+// Returns nil if the annotation is not set yet.
+func parsePodAnnotation(pod *corev1.Pod) (*PodNetworkAnnotation, error) {
 	// Check for annotation
 	annotationStr, ok := pod.Annotations[PodAnnotationKey]
 	if !ok || annotationStr == "" {
@@ -377,6 +421,94 @@ func (h *Handler) getPodAnnotation(ctx context.Context, namespace, name string)
 	return &annotation, nil
 }
 
+// addDPDKPort creates the vhost-user port of a DPDK Pod and records its
+// socket path in the Pod annotations
+func (h *Handler) addDPDKPort(ctx context.Context, req *Request, pod *corev1.Pod, annotation *PodNetworkAnnotation) (*PodNetworkInfo, error) {
+	if h.dpdkPorts == nil {
+		return nil, fmt.Errorf("pod requests DPDK but DPDK is not enabled on this node")
+	}
+
+	// Without a NUMA node, OVS places the queues on any PMD thread
+	numaNode, err := h.dpdkPorts.PodNUMANode(string(pod.UID), pod.Annotations)
+	if err != nil {
+		klog.Warningf("HandleAdd: failed to get NUMA node of pod %s/%s: %v",
+			req.PodNamespace, req.PodName, err)
+	}
+
+	port, err := h.dpdkPorts.CreatePort(&dpdk.DPDKPortConfig{
+		PodNamespace: req.PodNamespace,
+		PodName:      req.PodName,
+		ContainerID:  req.ContainerID,
+		NUMANode:     numaNode,
+		MACAddress:   annotation.MACAddress,
+		MTU:          h.mtu,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DPDK port: %w", err)
+	}
+
+	if err := h.setPodSocketAnnotation(ctx, req.PodNamespace, req.PodName, port.SocketPath); err != nil {
+		_ = h.dpdkPorts.DeletePort(req.PodNamespace, req.PodName)
+		return nil, err
+	}
+
+	klog.V(2).Infof("HandleAdd success: pod=%s/%s, ips=%v, mac=%s, socket=%s, rxqs=%+v",
+		req.PodNamespace, req.PodName, annotation.IPAddresses, annotation.MACAddress, port.SocketPath, port.RxqAssignments)
+
+	return &PodNetworkInfo{
+		IPAddress:         annotation.IPAddresses[0],
+		IPAddresses:       annotation.IPAddresses,
+		MACAddress:        annotation.MACAddress,
+		Gateway:           annotation.GatewayIPs[0],
+		Gateways:          annotation.GatewayIPs,
+		Routes:            annotation.Routes,
+		MTU:               h.mtu,
+		SandboxID:         req.Netns,
+		LogicalSwitchPort: annotation.LogicalSwitchPort,
+		SocketPath:        port.SocketPath,
+	}, nil
+}
+
+// checkDPDKPort verifies the vhost-user port of a DPDK Pod
+func (h *Handler) checkDPDKPort(req *Request, socketPath, macAddress string) error {
+	if h.dpdkPorts == nil {
+		return fmt.Errorf("pod requests DPDK but DPDK is not enabled on this node")
+	}
+
+	port, err := h.dpdkPorts.GetPortInfo(req.PodNamespace, req.PodName)
+	if err != nil {
+		return err
+	}
+	if socketPath != "" && port.SocketPath != socketPath {
+		return fmt.Errorf("socket path mismatch: expected %s, got %s", socketPath, port.SocketPath)
+	}
+	if macAddress != "" && !strings.EqualFold(port.MACAddress, macAddress) {
+		return fmt.Errorf("MAC address mismatch: expected %s, got %s", macAddress, port.MACAddress)
+	}
+	return nil
+}
+
+// setPodSocketAnnotation records the vhost-user socket path of a DPDK Pod
+func (h *Handler) setPodSocketAnnotation(ctx context.Context, namespace, name, socketPath string) error {
+	pod, err := h.getPod(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations[util.PodDPDKSocketAnnotationKey] == socketPath {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[util.PodDPDKSocketAnnotationKey] = socketPath
+	if err := h.k8sClient.Patch(ctx, pod, patch); err != nil {
+		return fmt.Errorf("failed to set DPDK socket annotation: %w", err)
+	}
+	return nil
+}
+
 // SetPodAnnotation sets the Pod network annotation
 // This is typically called by the controller, not the CNI handler
 func SetPodAnnotation(ctx context.Context, k8sClient client.Client, namespace, name string, annotation *PodNetworkAnnotation) error {
@@ -429,3 +561,13 @@ func GenerateMACFromIP(ipStr string) (string, error) {
 
 	return util.GenerateMAC(ip), nil
 }
+
+// stripPrefixes returns the addresses without their prefix length
+// Example: ["10.244.1.5/24"] -> ["10.244.1.5"]
+func stripPrefixes(ipAddresses []string) []string {
+	ips := make([]string, 0, len(ipAddresses))
+	for _, ipAddress := range ipAddresses {
+		ips = append(ips, strings.Split(ipAddress, "/")[0])
+	}
+	return ips
+}
@@ -42,9 +42,11 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"syscall"
 
 	"github.com/containernetworking/plugins/pkg/ip"
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/containernetworking/plugins/pkg/utils/sysctl"
 	"github.com/vishvananda/netlink"
 	"k8s.io/klog/v2"
 )
@@ -83,17 +85,17 @@ type InterfaceConfig struct {
 	// IfName is the interface name inside the container (usually "eth0")
 	IfName string
 
-	// IPAddress is the IP address with prefix length
-	// Example: "10.244.1.5/24"
-	IPAddress string
+	// IPAddresses are the IP addresses with prefix length, one per IP family
+	// Example: ["10.244.1.5/24", "fd00:10:244:1::5/64"]
+	IPAddresses []string
 
 	// MACAddress is the MAC address
 	// Example: "0a:58:0a:f4:01:05"
 	MACAddress string
 
-	// Gateway is the default gateway IP
-	// Example: "10.244.1.1"
-	Gateway string
+	// Gateways are the default gateway IPs, one per IP family
+	// Example: ["10.244.1.1", "fd00:10:244:1::1"]
+	Gateways []string
 
 	// MTU is the MTU for the interface
 	MTU int
@@ -140,10 +142,10 @@ func SetupInterface(cfg *InterfaceConfig) (*InterfaceInfo, error) {
 	if cfg.NetNS == "" {
 		return nil, fmt.Errorf("network namespace path is required")
 	}
-	if cfg.IPAddress == "" {
+	if len(cfg.IPAddresses) == 0 {
 		return nil, fmt.Errorf("IP address is required")
 	}
-	if cfg.Gateway == "" {
+	if len(cfg.Gateways) == 0 {
 		return nil, fmt.Errorf("gateway is required")
 	}
 
@@ -164,8 +166,8 @@ func SetupInterface(cfg *InterfaceConfig) (*InterfaceInfo, error) {
 	// Store OVS port name for later use
 	cfg.OVSPortName = hostIfName
 
-	klog.V(4).Infof("Setting up interface for pod %s/%s: hostIf=%s, containerIf=%s, ip=%s",
-		cfg.PodNamespace, cfg.PodName, hostIfName, cfg.IfName, cfg.IPAddress)
+	klog.V(4).Infof("Setting up interface for pod %s/%s: hostIf=%s, containerIf=%s, ips=%v",
+		cfg.PodNamespace, cfg.PodName, hostIfName, cfg.IfName, cfg.IPAddresses)
 
 	// Get container network namespace
 	containerNS, err := ns.GetNS(cfg.NetNS)
@@ -243,12 +245,12 @@ func SetupInterface(cfg *InterfaceConfig) (*InterfaceInfo, error) {
 	}, nil
 }
 
-// setupNetwork configures IP address and routes inside the container
+// setupNetwork configures IP addresses and routes inside the container
 //
 // This function runs inside the container's network namespace and:
-// 1. Parses the IP address and prefix
-// 2. Adds the IP address to the interface
-// 3. Adds the default route via the gateway
+// 1. Enables IPv6 on the interface if an IPv6 address is configured
+// 2. Adds every IP address to the interface
+// 3. Adds a default route via the gateway of each IP family
 //
 // Parameters:
 //   - cfg: Interface configuration
@@ -256,10 +258,28 @@ func SetupInterface(cfg *InterfaceConfig) (*InterfaceInfo, error) {
 // Returns:
 //   - error: Configuration error
 func setupNetwork(cfg *InterfaceConfig) error {
-	// Parse IP address
-	ipAddr, ipNet, err := net.ParseCIDR(cfg.IPAddress)
-	if err != nil {
-		return fmt.Errorf("invalid IP address %s: %w", cfg.IPAddress, err)
+	// Parse IP addresses
+	addrs := make([]*netlink.Addr, 0, len(cfg.IPAddresses))
+	hasIPv6 := false
+	for _, ipAddress := range cfg.IPAddresses {
+		ipAddr, ipNet, err := net.ParseCIDR(ipAddress)
+		if err != nil {
+			return fmt.Errorf("invalid IP address %s: %w", ipAddress, err)
+		}
+		addr := &netlink.Addr{
+			IPNet: &net.IPNet{
+				IP:   ipAddr,
+				Mask: ipNet.Mask,
+			},
+		}
+		if ipAddr.To4() == nil {
+			// The address is unique within the subnet (allocated by the
+			// controller), so skip duplicate address detection and make it
+			// usable immediately
+			addr.Flags = syscall.IFA_F_NODAD
+			hasIPv6 = true
+		}
+		addrs = append(addrs, addr)
 	}
 
 	// Get the container interface
@@ -268,15 +288,17 @@ func setupNetwork(cfg *InterfaceConfig) error {
 		return fmt.Errorf("failed to find interface %s: %w", cfg.IfName, err)
 	}
 
-	// Add IP address to interface
-	addr := &netlink.Addr{
-		IPNet: &net.IPNet{
-			IP:   ipAddr,
-			Mask: ipNet.Mask,
-		},
+	if hasIPv6 {
+		if err := setupIPv6Sysctls(cfg.IfName); err != nil {
+			return err
+		}
 	}
-	if err := netlink.AddrAdd(link, addr); err != nil {
-		return fmt.Errorf("failed to add IP address %s to %s: %w", cfg.IPAddress, cfg.IfName, err)
+
+	// Add IP addresses to interface
+	for _, addr := range addrs {
+		if err := netlink.AddrAdd(link, addr); err != nil {
+			return fmt.Errorf("failed to add IP address %s to %s: %w", addr.IPNet, cfg.IfName, err)
+		}
 	}
 
 	// Bring interface up
@@ -284,38 +306,60 @@ func setupNetwork(cfg *InterfaceConfig) error {
 		return fmt.Errorf("failed to bring up interface %s: %w", cfg.IfName, err)
 	}
 
-	// Parse gateway
-	gwIP := net.ParseIP(cfg.Gateway)
-	if gwIP == nil {
-		return fmt.Errorf("invalid gateway IP: %s", cfg.Gateway)
-	}
-
-	// Add default route via gateway
+	// Add default route via the gateway of each family
 	// For IPv4: 0.0.0.0/0 via gateway
 	// For IPv6: ::/0 via gateway
-	var defaultDst *net.IPNet
-	if gwIP.To4() != nil {
-		defaultDst = &net.IPNet{
-			IP:   net.IPv4zero,
-			Mask: net.CIDRMask(0, 32),
+	for _, gateway := range cfg.Gateways {
+		gwIP := net.ParseIP(gateway)
+		if gwIP == nil {
+			return fmt.Errorf("invalid gateway IP: %s", gateway)
 		}
-	} else {
-		defaultDst = &net.IPNet{
-			IP:   net.IPv6zero,
-			Mask: net.CIDRMask(0, 128),
+
+		var defaultDst *net.IPNet
+		if gwIP.To4() != nil {
+			defaultDst = &net.IPNet{
+				IP:   net.IPv4zero,
+				Mask: net.CIDRMask(0, 32),
+			}
+		} else {
+			defaultDst = &net.IPNet{
+				IP:   net.IPv6zero,
+				Mask: net.CIDRMask(0, 128),
+			}
 		}
-	}
 
-	route := &netlink.Route{
-		LinkIndex: link.Attrs().Index,
-		Dst:       defaultDst,
-		Gw:        gwIP,
-	}
-	if err := netlink.RouteAdd(route); err != nil {
-		return fmt.Errorf("failed to add default route via %s: %w", cfg.Gateway, err)
+		route := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       defaultDst,
+			Gw:        gwIP,
+		}
+		if err := netlink.RouteAdd(route); err != nil {
+			return fmt.Errorf("failed to add default route via %s: %w", gateway, err)
+		}
 	}
 
-	klog.V(4).Infof("Configured network for %s: ip=%s, gw=%s", cfg.IfName, cfg.IPAddress, cfg.Gateway)
+	klog.V(4).Infof("Configured network for %s: ips=%v, gws=%v", cfg.IfName, cfg.IPAddresses, cfg.Gateways)
+	return nil
+}
+
+// setupIPv6Sysctls enables IPv6 on the container interface
+//
+// Container runtimes may create the network namespace with IPv6 disabled.
+// Router advertisements are ignored because addresses and routes are
+// configured statically from the Pod annotation.
+func setupIPv6Sysctls(ifName string) error {
+	settings := []struct {
+		name  string
+		value string
+	}{
+		{fmt.Sprintf("net/ipv6/conf/%s/disable_ipv6", ifName), "0"},
+		{fmt.Sprintf("net/ipv6/conf/%s/accept_ra", ifName), "0"},
+	}
+	for _, s := range settings {
+		if _, err := sysctl.Sysctl(s.name, s.value); err != nil {
+			return fmt.Errorf("failed to set sysctl %s=%s: %w", s.name, s.value, err)
+		}
+	}
 	return nil
 }
 
@@ -348,7 +392,7 @@ func configureOVS(cfg *InterfaceConfig, hostIfName string) error {
 		"--", "set", "interface", hostIfName,
 		fmt.Sprintf("external_ids:iface-id=%s", ifaceID),
 		fmt.Sprintf("external_ids:attached-mac=%s", cfg.MACAddress),
-		fmt.Sprintf("external_ids:ip_addresses=%s", strings.Split(cfg.IPAddress, "/")[0]),
+		fmt.Sprintf("external_ids:ip_addresses=%s", strings.Join(stripPrefixes(cfg.IPAddresses), ",")),
 		fmt.Sprintf("external_ids:sandbox=%s", cfg.ContainerID),
 	}
 
@@ -484,7 +528,7 @@ func generateVethName() (string, error) {
 // This function:
 // 1. Checks that the OVS port exists
 // 2. Checks that the veth pair exists
-// 3. Checks that the container interface has the correct IP
+// 3. Checks that the container interface has every expected IP
 //
 // Parameters:
 //   - cfg: Interface configuration
@@ -525,27 +569,29 @@ func CheckInterface(cfg *InterfaceConfig) error {
 				return fmt.Errorf("container interface %s not found: %w", cfg.IfName, err)
 			}
 
-			// Check IP address if provided
-			if cfg.IPAddress != "" {
+			// Check IP addresses if provided
+			if len(cfg.IPAddresses) > 0 {
 				addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
 				if err != nil {
 					return fmt.Errorf("failed to list addresses on %s: %w", cfg.IfName, err)
 				}
 
-				expectedIP, _, err := net.ParseCIDR(cfg.IPAddress)
-				if err != nil {
-					return fmt.Errorf("invalid expected IP %s: %w", cfg.IPAddress, err)
-				}
+				for _, ipAddress := range cfg.IPAddresses {
+					expectedIP, _, err := net.ParseCIDR(ipAddress)
+					if err != nil {
+						return fmt.Errorf("invalid expected IP %s: %w", ipAddress, err)
+					}
 
-				found := false
-				for _, addr := range addrs {
-					if addr.IP.Equal(expectedIP) {
-						found = true
-						break
+					found := false
+					for _, addr := range addrs {
+						if addr.IP.Equal(expectedIP) {
+							found = true
+							break
+						}
+					}
+					if !found {
+						return fmt.Errorf("expected IP %s not found on interface %s", expectedIP, cfg.IfName)
 					}
-				}
-				if !found {
-					return fmt.Errorf("expected IP %s not found on interface %s", expectedIP, cfg.IfName)
 				}
 			}
 
@@ -36,9 +36,9 @@ type InterfaceConfig struct {
 	ContainerID  string
 	NetNS        string
 	IfName       string
-	IPAddress    string
+	IPAddresses  []string
 	MACAddress   string
-	Gateway      string
+	Gateways     []string
 	MTU          int
 	OVSPortName  string
 	PortUUID     string
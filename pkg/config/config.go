@@ -16,12 +16,15 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/types"
 )
 
 // Config is the global configuration structure
@@ -136,6 +139,20 @@ type NetworkConfig struct {
 	// MTU is the MTU for Pod interfaces
 	// Default: 1400 (accounting for VXLAN overhead)
 	MTU int `json:"mtu" yaml:"mtu"`
+
+	// StickyIPGracePeriod is how long the IPs of a deleted StatefulSet Pod
+	// stay reserved for a Pod recreated with the same name
+	// 0 releases the IPs immediately.
+	// Default: 10m
+	StickyIPGracePeriod time.Duration `json:"stickyIPGracePeriod" yaml:"stickyIPGracePeriod"`
+
+	// ServiceHairpinSNATIP is the source IP of Service traffic load balanced
+	// back to the Pod that sent it (hairpin), at most one IPv4 and one IPv6
+	// address separated by a space
+	// Empty uses the OVN default, the Service VIP.
+	// Example: "169.254.169.5 fd69::5"
+	// Default: "169.254.169.5"
+	ServiceHairpinSNATIP string `json:"serviceHairpinSNATIP" yaml:"serviceHairpinSNATIP"`
 }
 
 // GatewayConfig contains gateway configuration
@@ -156,6 +173,40 @@ type GatewayConfig struct {
 
 	// VLANID is the VLAN ID for external traffic (optional)
 	VLANID int `json:"vlanID" yaml:"vlanID"`
+
+	// ProviderNetworks maps the providers of underlay Subnets to OVS bridges.
+	// Each entry is added to ovn-bridge-mappings next to the default
+	// "physnet1:br-ex" mapping.
+	ProviderNetworks []ProviderNetworkConfig `json:"providerNetworks" yaml:"providerNetworks"`
+}
+
+// HasProvider returns true if a provider network is mapped on the nodes.
+// The default provider is always mapped to br-ex.
+func (g *GatewayConfig) HasProvider(name string) bool {
+	if name == types.DefaultProvider {
+		return true
+	}
+	for _, pn := range g.ProviderNetworks {
+		if pn.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderNetworkConfig describes a physical network used by underlay Subnets
+type ProviderNetworkConfig struct {
+	// Name is the provider name referenced by Subnet spec.provider
+	// Example: "provider-net1"
+	Name string `json:"name" yaml:"name"`
+
+	// Bridge is the OVS bridge connected to the physical network
+	// Example: "br-provider"
+	Bridge string `json:"bridge" yaml:"bridge"`
+
+	// Interface is the physical interface added to Bridge (optional)
+	// Example: "eth1"
+	Interface string `json:"interface" yaml:"interface"`
 }
 
 // TunnelConfig contains tunnel configuration
@@ -210,6 +261,17 @@ type DPDKConfig struct {
 	// Used for validation during DPDK environment checks.
 	// Default: 1024 (1GB)
 	MinHugepagesMB int64 `json:"minHugepagesMB" yaml:"minHugepagesMB"`
+
+	// RxqDescriptors is the number of descriptors per receive queue of Pod
+	// ports (options:n_rxq_desc), a power of 2 up to 4096
+	// Default: 0 (OVS default)
+	RxqDescriptors int `json:"rxqDescriptors" yaml:"rxqDescriptors"`
+
+	// CPUManagerStateFile is the kubelet CPU manager checkpoint
+	// The NUMA node of a Pod's pinned CPUs is read from it, so that the
+	// Pod's receive queues are polled by PMD threads of the same node.
+	// Default: "/var/lib/kubelet/cpu_manager_state"
+	CPUManagerStateFile string `json:"cpuManagerStateFile" yaml:"cpuManagerStateFile"`
 }
 
 // LoggingConfig contains logging configuration
@@ -240,10 +302,12 @@ func DefaultConfig() *Config {
 			ServiceAccount: "zstack-ovn-kubernetes",
 		},
 		Network: NetworkConfig{
-			ClusterCIDR:    "10.244.0.0/16",
-			ServiceCIDR:    "10.96.0.0/16",
-			NodeSubnetSize: 24,
-			MTU:            1400,
+			ClusterCIDR:          "10.244.0.0/16",
+			ServiceCIDR:          "10.96.0.0/16",
+			NodeSubnetSize:       24,
+			MTU:                  1400,
+			StickyIPGracePeriod:  10 * time.Minute,
+			ServiceHairpinSNATIP: "169.254.169.5",
 		},
 		Gateway: GatewayConfig{
 			Mode: "local",
@@ -253,11 +317,12 @@ func DefaultConfig() *Config {
 			Port: 4789,
 		},
 		DPDK: DPDKConfig{
-			Enabled:        false,
-			SocketDir:      "/var/run/openvswitch",
-			SocketMode:     "client",
-			Queues:         1,
-			MinHugepagesMB: 1024,
+			Enabled:             false,
+			SocketDir:           "/var/run/openvswitch",
+			SocketMode:          "client",
+			Queues:              1,
+			MinHugepagesMB:      1024,
+			CPUManagerStateFile: "/var/lib/kubelet/cpu_manager_state",
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
@@ -328,6 +393,7 @@ func (c *Config) LoadFromFile(path string) error {
 //   - ZSTACK_OVN_SBDB_ADDRESS=tcp:192.168.1.100:6642
 //   - ZSTACK_OVN_CLUSTER_CIDR=10.244.0.0/16
 //   - ZSTACK_OVN_SERVICE_CIDR=10.96.0.0/16
+//   - ZSTACK_OVN_STICKY_IP_GRACE_PERIOD=10m
 //   - ZSTACK_OVN_TUNNEL_TYPE=vxlan
 //   - ZSTACK_OVN_GATEWAY_MODE=local
 //   - ZSTACK_OVN_LOG_LEVEL=debug
@@ -364,6 +430,14 @@ func (c *Config) ApplyEnvOverrides() {
 	if v := os.Getenv("ZSTACK_OVN_SERVICE_CIDR"); v != "" {
 		c.Network.ServiceCIDR = v
 	}
+	if v := os.Getenv("ZSTACK_OVN_STICKY_IP_GRACE_PERIOD"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			c.Network.StickyIPGracePeriod = d
+		}
+	}
+	if v := os.Getenv("ZSTACK_OVN_SERVICE_HAIRPIN_SNAT_IP"); v != "" {
+		c.Network.ServiceHairpinSNATIP = v
+	}
 
 	// Gateway settings
 	if v := os.Getenv("ZSTACK_OVN_GATEWAY_MODE"); v != "" {
@@ -398,6 +472,14 @@ func (c *Config) ApplyEnvOverrides() {
 			c.DPDK.MinHugepagesMB = mb
 		}
 	}
+	if v := os.Getenv("ZSTACK_OVN_DPDK_RXQ_DESCRIPTORS"); v != "" {
+		if desc, err := strconv.Atoi(v); err == nil && desc >= 0 {
+			c.DPDK.RxqDescriptors = desc
+		}
+	}
+	if v := os.Getenv("ZSTACK_OVN_DPDK_CPU_MANAGER_STATE_FILE"); v != "" {
+		c.DPDK.CPUManagerStateFile = v
+	}
 
 	// Logging settings
 	if v := os.Getenv("ZSTACK_OVN_LOG_LEVEL"); v != "" {
@@ -453,12 +535,31 @@ func (c *Config) Validate() error {
 	if c.Network.NodeSubnetSize < 16 || c.Network.NodeSubnetSize > 30 {
 		errors = append(errors, fmt.Sprintf("invalid nodeSubnetSize: %d (must be between 16 and 30)", c.Network.NodeSubnetSize))
 	}
+	if c.Network.StickyIPGracePeriod < 0 {
+		errors = append(errors, fmt.Sprintf("invalid stickyIPGracePeriod: %s (must be >= 0)", c.Network.StickyIPGracePeriod))
+	}
+	if err := validateHairpinSNATIP(c.Network.ServiceHairpinSNATIP); err != nil {
+		errors = append(errors, fmt.Sprintf("invalid serviceHairpinSNATIP: %s (%v)", c.Network.ServiceHairpinSNATIP, err))
+	}
 
 	// Validate gateway mode
 	if c.Gateway.Mode != "shared" && c.Gateway.Mode != "local" {
 		errors = append(errors, fmt.Sprintf("invalid gateway mode: %s (must be 'shared' or 'local')", c.Gateway.Mode))
 	}
 
+	// Validate provider networks
+	providers := map[string]bool{types.DefaultProvider: true}
+	for _, pn := range c.Gateway.ProviderNetworks {
+		if pn.Name == "" || pn.Bridge == "" {
+			errors = append(errors, "provider networks require a name and a bridge")
+			continue
+		}
+		if providers[pn.Name] {
+			errors = append(errors, fmt.Sprintf("duplicate provider network: %s", pn.Name))
+		}
+		providers[pn.Name] = true
+	}
+
 	// Validate tunnel type
 	if c.Tunnel.Type != "vxlan" && c.Tunnel.Type != "geneve" {
 		errors = append(errors, fmt.Sprintf("invalid tunnel type: %s (must be 'vxlan' or 'geneve')", c.Tunnel.Type))
@@ -486,6 +587,9 @@ func (c *Config) Validate() error {
 		if c.DPDK.SocketDir == "" {
 			errors = append(errors, "DPDK socket directory is required when DPDK is enabled")
 		}
+		if d := c.DPDK.RxqDescriptors; d < 0 || d > 4096 || d&(d-1) != 0 {
+			errors = append(errors, fmt.Sprintf("invalid DPDK rxq descriptors: %d (must be a power of 2 up to 4096)", d))
+		}
 	}
 
 	if len(errors) > 0 {
@@ -602,6 +706,24 @@ func validateDBAddressFormat(address string) error {
 	return nil
 }
 
+// validateHairpinSNATIP validates a hairpin SNAT IP setting: empty, or at
+// most one IPv4 and one IPv6 address separated by a space
+func validateHairpinSNATIP(value string) error {
+	families := map[bool]bool{}
+	for _, field := range strings.Fields(value) {
+		ip := net.ParseIP(field)
+		if ip == nil {
+			return fmt.Errorf("invalid IP address: %s", field)
+		}
+		isIPv4 := ip.To4() != nil
+		if families[isIPv4] {
+			return fmt.Errorf("more than one address of the family of %s", field)
+		}
+		families[isIPv4] = true
+	}
+	return nil
+}
+
 // ShouldStartLocalOVN returns true if local OVN processes should be started
 // In external mode, local OVN processes (NB DB, SB DB, northd) should NOT be started
 // as we connect to external ZStack-managed OVN databases
@@ -307,6 +307,32 @@ func TestValidate_InvalidNodeSubnetSize(t *testing.T) {
 	}
 }
 
+func TestValidate_ServiceHairpinSNATIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"disabled", "", false},
+		{"ipv4", "169.254.169.5", false},
+		{"dual stack", "169.254.169.5 fd69::5", false},
+		{"invalid address", "169.254.169", true},
+		{"two ipv4 addresses", "169.254.169.5 169.254.169.6", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Network.ServiceHairpinSNATIP = tt.value
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // DPDK Configuration Tests
 
 func TestDefaultConfig_DPDK(t *testing.T) {
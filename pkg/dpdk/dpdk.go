@@ -106,15 +106,26 @@ type DPDKConfig struct {
 	// Queues is the number of queues for multiqueue support
 	// Default: 1
 	Queues int
+
+	// RxqDescriptors is the number of descriptors per receive queue
+	// (options:n_rxq_desc), a power of 2 up to 4096
+	// Default: 0 (OVS default)
+	RxqDescriptors int
+
+	// CPUManagerStateFile is the kubelet CPU manager checkpoint, used to
+	// learn the NUMA node of a Pod's pinned CPUs
+	// Default: /var/lib/kubelet/cpu_manager_state
+	CPUManagerStateFile string
 }
 
 // DefaultDPDKConfig returns the default DPDK configuration
 func DefaultDPDKConfig() *DPDKConfig {
 	return &DPDKConfig{
-		Enabled:    false,
-		SocketDir:  "/var/run/openvswitch",
-		SocketMode: "client",
-		Queues:     1,
+		Enabled:             false,
+		SocketDir:           "/var/run/openvswitch",
+		SocketMode:          "client",
+		Queues:              1,
+		CPUManagerStateFile: DefaultCPUManagerStateFile,
 	}
 }
 
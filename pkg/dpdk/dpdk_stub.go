@@ -27,19 +27,22 @@ type DPDKStatus struct {
 
 // DPDKConfig contains DPDK-specific configuration
 type DPDKConfig struct {
-	Enabled    bool
-	SocketDir  string
-	SocketMode string
-	Queues     int
+	Enabled             bool
+	SocketDir           string
+	SocketMode          string
+	Queues              int
+	RxqDescriptors      int
+	CPUManagerStateFile string
 }
 
 // DefaultDPDKConfig returns the default DPDK configuration
 func DefaultDPDKConfig() *DPDKConfig {
 	return &DPDKConfig{
-		Enabled:    false,
-		SocketDir:  "/var/run/openvswitch",
-		SocketMode: "client",
-		Queues:     1,
+		Enabled:             false,
+		SocketDir:           "/var/run/openvswitch",
+		SocketMode:          "client",
+		Queues:              1,
+		CPUManagerStateFile: DefaultCPUManagerStateFile,
 	}
 }
 
@@ -0,0 +1,326 @@
+// Package dpdk provides NUMA- and PMD-aware placement of DPDK ports.
+//
+// OVS-DPDK polls every receive queue (rxq) from a PMD thread pinned to one
+// core. When the PMD core and the Pod's CPUs sit on different NUMA nodes,
+// every packet crosses the socket interconnect. This file:
+// - Learns the NUMA node of a Pod's pinned CPUs, from an annotation or the
+//   kubelet CPU manager checkpoint
+// - Picks the least-loaded PMD cores of that NUMA node for the port's rxqs
+//   (other_config:pmd-rxq-affinity), always leaving one core unpinned
+// - Reports which PMD thread polls each rxq (ovs-appctl dpif-netdev/pmd-rxq-show)
+//
+// Reference: OVS-DPDK PMD threads documentation
+//
+//go:build linux
+
+package dpdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// NUMANodeAnnotationKey sets the NUMA node of a DPDK Pod's port.
+	// Set by the user (e.g., "1"); takes precedence over the CPU manager
+	// checkpoint.
+	NUMANodeAnnotationKey = "zstack.io/dpdk-numa-node"
+
+	// DefaultCPUManagerStateFile is the kubelet CPU manager checkpoint
+	DefaultCPUManagerStateFile = "/var/lib/kubelet/cpu_manager_state"
+
+	// sysNodeDir lists the NUMA nodes of the host with their CPUs
+	sysNodeDir = "/sys/devices/system/node"
+)
+
+// RxqAssignment is the PMD thread polling one receive queue of a port
+type RxqAssignment struct {
+	// QueueID is the receive queue ID
+	QueueID int
+
+	// CoreID is the CPU core of the PMD thread
+	CoreID int
+
+	// NUMANode is the NUMA node of the PMD thread
+	NUMANode int
+}
+
+// cpuManagerState is the part of the kubelet CPU manager checkpoint we use
+//
+// Example:
+//
+//	{"policyName":"static","defaultCpuSet":"0-1,4-5",
+//	 "entries":{"<pod UID>":{"app":"2-3"}},"checksum":1234}
+type cpuManagerState struct {
+	PolicyName string                       `json:"policyName"`
+	Entries    map[string]map[string]string `json:"entries"`
+}
+
+// PodNUMANode returns the NUMA node of a DPDK Pod, or nil if unknown.
+//
+// The NUMA node comes from NUMANodeAnnotationKey if set. Otherwise, it is
+// the node holding most of the CPUs pinned to the Pod's containers by the
+// kubelet static CPU manager policy.
+//
+// Parameters:
+//   - podUID: Pod UID, the key of the CPU manager checkpoint entries
+//   - annotations: Pod annotations
+//
+// Returns:
+//   - *int: NUMA node, nil if the Pod has no pinned CPUs
+//   - error: Error if the annotation or checkpoint is invalid
+func (m *PortManager) PodNUMANode(podUID string, annotations map[string]string) (*int, error) {
+	if v := strings.TrimSpace(annotations[NUMANodeAnnotationKey]); v != "" {
+		node, err := strconv.Atoi(v)
+		if err != nil || node < 0 {
+			return nil, fmt.Errorf("invalid NUMA node %q in annotation %s", v, NUMANodeAnnotationKey)
+		}
+		return &node, nil
+	}
+
+	if podUID == "" || m.config.CPUManagerStateFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(m.config.CPUManagerStateFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CPU manager state: %w", err)
+	}
+	cpus, err := podPinnedCPUs(data, podUID)
+	if err != nil || len(cpus) == 0 {
+		return nil, err
+	}
+
+	cpuNodes, err := readCPUNUMANodes(sysNodeDir)
+	if err != nil {
+		return nil, err
+	}
+	return majorityNUMANode(cpus, cpuNodes), nil
+}
+
+// podPinnedCPUs returns the CPUs pinned to the containers of a Pod in a
+// CPU manager checkpoint
+func podPinnedCPUs(data []byte, podUID string) ([]int, error) {
+	var state cpuManagerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse CPU manager state: %w", err)
+	}
+
+	var cpus []int
+	for _, cpuSet := range state.Entries[podUID] {
+		list, err := parseCPUList(cpuSet)
+		if err != nil {
+			return nil, err
+		}
+		cpus = append(cpus, list...)
+	}
+	return cpus, nil
+}
+
+// readCPUNUMANodes maps every CPU of the host to its NUMA node
+func readCPUNUMANodes(dir string) (map[int]int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "node[0-9]*", "cpulist"))
+	if err != nil {
+		return nil, err
+	}
+
+	cpuNodes := make(map[int]int)
+	for _, path := range paths {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(path)), "node"))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		cpus, err := parseCPUList(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, cpu := range cpus {
+			cpuNodes[cpu] = node
+		}
+	}
+	return cpuNodes, nil
+}
+
+// majorityNUMANode returns the NUMA node holding most of the CPUs, the
+// lowest one on a tie, or nil if no CPU has a known node
+func majorityNUMANode(cpus []int, cpuNodes map[int]int) *int {
+	counts := make(map[int]int)
+	for _, cpu := range cpus {
+		if node, ok := cpuNodes[cpu]; ok {
+			counts[node]++
+		}
+	}
+
+	var best *int
+	for node, count := range counts {
+		if best == nil || count > counts[*best] || (count == counts[*best] && node < *best) {
+			n := node
+			best = &n
+		}
+	}
+	return best
+}
+
+// parseCPUList parses a Linux CPU list (e.g., "0-3,8,10-11")
+func parseCPUList(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var cpus []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q", s)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU list %q", s)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// pmdThreadPattern matches the header of a PMD thread in pmd-rxq-show
+// (e.g., "pmd thread numa_id 0 core_id 2:")
+var pmdThreadPattern = regexp.MustCompile(`^pmd thread numa_id (\d+) core_id (\d+):`)
+
+// isolatedPattern matches the line of a PMD thread polling only pinned rxqs
+// in pmd-rxq-show (e.g., "  isolated : true")
+var isolatedPattern = regexp.MustCompile(`^\s*isolated\s*:\s*true`)
+
+// rxqPattern matches an rxq line in pmd-rxq-show
+// (e.g., "  port: vhu1   queue-id:  0 (enabled)   pmd usage:  0 %")
+var rxqPattern = regexp.MustCompile(`^\s*port:\s+(\S+)\s+queue-id:\s+(\d+)`)
+
+// pmdRxqShow runs ovs-appctl dpif-netdev/pmd-rxq-show
+func pmdRxqShow() (string, error) {
+	output, err := exec.Command("ovs-appctl", "dpif-netdev/pmd-rxq-show").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to show PMD rxqs: %w, output: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
+// parsePMDRxqShow parses the output of ovs-appctl dpif-netdev/pmd-rxq-show
+//
+// Returns:
+//   - map[int]int: NUMA node of every PMD core
+//   - map[int]bool: PMD cores polling only pinned rxqs (isolated)
+//   - map[string][]RxqAssignment: rxq assignments per port, ordered by queue
+func parsePMDRxqShow(output string) (map[int]int, map[int]bool, map[string][]RxqAssignment) {
+	pmdCores := make(map[int]int)
+	isolated := make(map[int]bool)
+	ports := make(map[string][]RxqAssignment)
+
+	core, node := -1, -1
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := pmdThreadPattern.FindStringSubmatch(line); m != nil {
+			node, _ = strconv.Atoi(m[1])
+			core, _ = strconv.Atoi(m[2])
+			pmdCores[core] = node
+			continue
+		}
+		if isolatedPattern.MatchString(line) && core >= 0 {
+			isolated[core] = true
+			continue
+		}
+		if m := rxqPattern.FindStringSubmatch(line); m != nil && core >= 0 {
+			queue, _ := strconv.Atoi(m[2])
+			ports[m[1]] = append(ports[m[1]], RxqAssignment{QueueID: queue, CoreID: core, NUMANode: node})
+		}
+	}
+
+	for _, rxqs := range ports {
+		sort.Slice(rxqs, func(i, j int) bool { return rxqs[i].QueueID < rxqs[j].QueueID })
+	}
+	return pmdCores, isolated, ports
+}
+
+// buildRxqAffinity spreads the rxqs of a port over the PMD cores of a NUMA
+// node, in the format of other_config:pmd-rxq-affinity (e.g., "0:2,1:4")
+//
+// A PMD core with pinned rxqs may stop polling unpinned ones (the uplink
+// NIC, ports without a NUMA node), so one core of the node is never used:
+// the lowest core that is not isolated yet. Each queue goes to the
+// remaining core polling the fewest rxqs of other ports, the lowest core on
+// a tie, so the first queues of different ports do not all land on the
+// same core.
+//
+// Returns an empty string if the node has fewer than two PMD cores, or if
+// every PMD core of the node is already isolated.
+func buildRxqAffinity(portName string, queues int, numaNode int, pmdCores map[int]int, isolated map[int]bool, rxqs map[string][]RxqAssignment) string {
+	var cores []int
+	for core, node := range pmdCores {
+		if node == numaNode {
+			cores = append(cores, core)
+		}
+	}
+	sort.Ints(cores)
+
+	reserved := -1
+	for _, core := range cores {
+		if !isolated[core] {
+			reserved = core
+			break
+		}
+	}
+	if reserved < 0 || len(cores) < 2 {
+		return ""
+	}
+
+	load := make(map[int]int)
+	for _, core := range cores {
+		if core != reserved {
+			load[core] = 0
+		}
+	}
+	for port, assignments := range rxqs {
+		if port == portName {
+			continue
+		}
+		for _, rxq := range assignments {
+			if _, ok := load[rxq.CoreID]; ok {
+				load[rxq.CoreID]++
+			}
+		}
+	}
+
+	if queues < 1 {
+		queues = 1
+	}
+	pairs := make([]string, 0, queues)
+	for q := 0; q < queues; q++ {
+		best := -1
+		for core, n := range load {
+			if best < 0 || n < load[best] || (n == load[best] && core < best) {
+				best = core
+			}
+		}
+		load[best]++
+		pairs = append(pairs, fmt.Sprintf("%d:%d", q, best))
+	}
+	return strings.Join(pairs, ",")
+}
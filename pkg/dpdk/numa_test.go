@@ -0,0 +1,115 @@
+//go:build linux
+
+package dpdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"3", []int{3}, false},
+		{"0-3,8,10-11", []int{0, 1, 2, 3, 8, 10, 11}, false},
+		{"3-1", nil, true},
+		{"a-b", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseCPUList(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCPUList(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCPUList(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodNUMANodeFromCheckpoint(t *testing.T) {
+	state := []byte(`{"policyName":"static","defaultCpuSet":"0-1,8-9",` +
+		`"entries":{"pod-a":{"app":"10-11","sidecar":"2"}},"checksum":1}`)
+	cpuNodes := map[int]int{0: 0, 1: 0, 2: 0, 3: 0, 8: 1, 9: 1, 10: 1, 11: 1}
+
+	cpus, err := podPinnedCPUs(state, "pod-a")
+	if err != nil {
+		t.Fatalf("podPinnedCPUs() error = %v", err)
+	}
+	if node := majorityNUMANode(cpus, cpuNodes); node == nil || *node != 1 {
+		t.Errorf("majorityNUMANode(%v) = %v, want 1", cpus, node)
+	}
+
+	cpus, err = podPinnedCPUs(state, "pod-b")
+	if err != nil {
+		t.Fatalf("podPinnedCPUs() error = %v", err)
+	}
+	if node := majorityNUMANode(cpus, cpuNodes); node != nil {
+		t.Errorf("majorityNUMANode() for a Pod without pinned CPUs = %d, want nil", *node)
+	}
+}
+
+func TestParsePMDRxqShow(t *testing.T) {
+	output := `pmd thread numa_id 0 core_id 2:
+  isolated : false
+  port: dpdk0             queue-id:  0 (enabled)   pmd usage:  0 %
+  port: vhu-a             queue-id:  1 (enabled)   pmd usage:  0 %
+  overhead:  0 %
+pmd thread numa_id 1 core_id 9:
+  isolated : true
+  port: vhu-a             queue-id:  0 (enabled)   pmd usage:  0 %
+pmd thread numa_id 1 core_id 11:
+  isolated : false
+pmd thread numa_id 1 core_id 13:
+  isolated : false
+`
+
+	pmdCores, isolated, ports := parsePMDRxqShow(output)
+	if want := map[int]int{2: 0, 9: 1, 11: 1, 13: 1}; !reflect.DeepEqual(pmdCores, want) {
+		t.Errorf("pmdCores = %v, want %v", pmdCores, want)
+	}
+	if want := map[int]bool{9: true}; !reflect.DeepEqual(isolated, want) {
+		t.Errorf("isolated = %v, want %v", isolated, want)
+	}
+	want := []RxqAssignment{{QueueID: 0, CoreID: 9, NUMANode: 1}, {QueueID: 1, CoreID: 2, NUMANode: 0}}
+	if !reflect.DeepEqual(ports["vhu-a"], want) {
+		t.Errorf("ports[vhu-a] = %+v, want %+v", ports["vhu-a"], want)
+	}
+
+	// Core 11 is left unpinned; the rxqs of the port itself do not count as load
+	if got := buildRxqAffinity("vhu-a", 3, 1, pmdCores, isolated, ports); got != "0:9,1:13,2:9" {
+		t.Errorf("buildRxqAffinity() = %q, want %q", got, "0:9,1:13,2:9")
+	}
+	// Core 9 already polls a queue of vhu-a
+	if got := buildRxqAffinity("vhu-b", 3, 1, pmdCores, isolated, ports); got != "0:13,1:9,2:13" {
+		t.Errorf("buildRxqAffinity() = %q, want %q", got, "0:13,1:9,2:13")
+	}
+	// Node 0 has a single PMD core, which must keep polling dpdk0
+	if got := buildRxqAffinity("vhu-b", 1, 0, pmdCores, isolated, ports); got != "" {
+		t.Errorf("buildRxqAffinity() with one PMD on the node = %q, want empty", got)
+	}
+	if got := buildRxqAffinity("vhu-b", 1, 2, pmdCores, isolated, ports); got != "" {
+		t.Errorf("buildRxqAffinity() without PMD on the node = %q, want empty", got)
+	}
+}
+
+func TestBuildRxqAffinityAllCoresPinned(t *testing.T) {
+	output := `pmd thread numa_id 1 core_id 9:
+  isolated : true
+  port: vhu-a             queue-id:  0 (enabled)   pmd usage:  0 %
+pmd thread numa_id 1 core_id 11:
+  isolated : true
+  port: vhu-c             queue-id:  0 (enabled)   pmd usage:  0 %
+`
+
+	pmdCores, isolated, ports := parsePMDRxqShow(output)
+	if got := buildRxqAffinity("vhu-b", 2, 1, pmdCores, isolated, ports); got != "" {
+		t.Errorf("buildRxqAffinity() with every PMD core pinned = %q, want empty", got)
+	}
+}
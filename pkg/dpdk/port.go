@@ -13,6 +13,9 @@
 // 2. Better compatibility with container lifecycle
 // 3. Easier socket permission management
 //
+// When the NUMA node of a Pod is known, the port's receive queues are pinned
+// to PMD threads of that node (see numa.go).
+//
 // Reference: OVS-DPDK vhost-user documentation
 //
 //go:build linux
@@ -24,6 +27,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -70,6 +74,14 @@ type DPDKPortConfig struct {
 	// Queues is the number of queues for multiqueue support
 	Queues int
 
+	// RxqDescriptors is the number of descriptors per receive queue
+	// (options:n_rxq_desc), 0 for the OVS default
+	RxqDescriptors int
+
+	// NUMANode is the NUMA node of the Pod's CPUs, nil if unknown.
+	// The receive queues are pinned to PMD cores of this node.
+	NUMANode *int
+
 	// MACAddress is the MAC address for the port
 	MACAddress string
 
@@ -90,6 +102,13 @@ type DPDKPortInfo struct {
 
 	// MACAddress is the MAC address assigned to the port
 	MACAddress string
+
+	// NUMANode is the NUMA node recorded for the port, nil if unknown
+	NUMANode *int
+
+	// RxqAssignments lists the PMD thread polling each receive queue.
+	// Empty until OVS has scheduled the queues.
+	RxqAssignments []RxqAssignment
 }
 
 // PortManager manages DPDK vhost-user ports
@@ -118,6 +137,12 @@ func NewPortManager(config *DPDKConfig) *PortManager {
 // 2. Creates the socket directory if needed
 // 3. Adds the dpdkvhostuserclient port to OVS br-int
 // 4. Sets the external_ids for OVN integration
+// 5. Pins the receive queues to PMD cores of the Pod's NUMA node, if known
+//
+// Pinned PMD cores stop polling unpinned queues by default, which would
+// leave the uplink NIC unpolled once every core has a pinned queue. Pinning
+// therefore sets other_config:pmd-rxq-isolate=false on Open_vSwitch, and
+// one PMD core of the node is always left unpinned.
 //
 // Parameters:
 //   - cfg: Port configuration
@@ -152,6 +177,9 @@ func (m *PortManager) CreatePort(cfg *DPDKPortConfig) (*DPDKPortInfo, error) {
 	if cfg.Queues == 0 {
 		cfg.Queues = m.config.Queues
 	}
+	if cfg.RxqDescriptors == 0 {
+		cfg.RxqDescriptors = m.config.RxqDescriptors
+	}
 
 	// Determine port type based on socket mode
 	portType := PortTypeDPDKVhostUserClient
@@ -188,6 +216,30 @@ func (m *PortManager) CreatePort(cfg *DPDKPortConfig) (*DPDKPortInfo, error) {
 		args = append(args, fmt.Sprintf("options:n_txq=%d", cfg.Queues))
 	}
 
+	if cfg.RxqDescriptors > 0 {
+		args = append(args, fmt.Sprintf("options:n_rxq_desc=%d", cfg.RxqDescriptors))
+	}
+	if cfg.MTU > 0 {
+		args = append(args, fmt.Sprintf("mtu_request=%d", cfg.MTU))
+	}
+
+	// Pin the receive queues to PMD cores of the Pod's NUMA node
+	if cfg.NUMANode != nil {
+		args = append(args, fmt.Sprintf("external_ids:numa_id=%d", *cfg.NUMANode))
+		pmdRxqs, err := pmdRxqShow()
+		if err != nil {
+			klog.Warningf("Failed to get PMD threads for port %s: %v", cfg.PortName, err)
+		} else {
+			pmdCores, isolated, rxqs := parsePMDRxqShow(pmdRxqs)
+			if affinity := buildRxqAffinity(cfg.PortName, cfg.Queues, *cfg.NUMANode, pmdCores, isolated, rxqs); affinity != "" {
+				args = append(args, fmt.Sprintf("other_config:pmd-rxq-affinity=\"%s\"", affinity),
+					"--", "set", "Open_vSwitch", ".", "other_config:pmd-rxq-isolate=false")
+			} else {
+				klog.Warningf("No PMD thread on NUMA node %d can be pinned for port %s, leaving its queues to OVS", *cfg.NUMANode, cfg.PortName)
+			}
+		}
+	}
+
 	// Add external_ids for OVN integration
 	args = append(args,
 		"--", "set", "interface", cfg.PortName,
@@ -219,10 +271,12 @@ func (m *PortManager) CreatePort(cfg *DPDKPortConfig) (*DPDKPortInfo, error) {
 		cfg.PortName, cfg.SocketPath, cfg.PodNamespace, cfg.PodName)
 
 	return &DPDKPortInfo{
-		PortName:   cfg.PortName,
-		SocketPath: cfg.SocketPath,
-		PortType:   portType,
-		MACAddress: cfg.MACAddress,
+		PortName:       cfg.PortName,
+		SocketPath:     cfg.SocketPath,
+		PortType:       portType,
+		MACAddress:     cfg.MACAddress,
+		NUMANode:       cfg.NUMANode,
+		RxqAssignments: portRxqAssignments(cfg.PortName),
 	}, nil
 }
 
@@ -302,14 +356,36 @@ func (m *PortManager) GetPortInfo(namespace, podName string) (*DPDKPortInfo, err
 	macAddress := strings.TrimSpace(string(output))
 	macAddress = strings.Trim(macAddress, "\"")
 
+	// Get NUMA node
+	var numaNode *int
+	cmd = exec.Command("ovs-vsctl", "get", "interface", portName, "external_ids:numa_id")
+	output, _ = cmd.Output() // NUMA node might not be set
+	if node, err := strconv.Atoi(strings.Trim(strings.TrimSpace(string(output)), "\"")); err == nil {
+		numaNode = &node
+	}
+
 	return &DPDKPortInfo{
-		PortName:   portName,
-		SocketPath: socketPath,
-		PortType:   portType,
-		MACAddress: macAddress,
+		PortName:       portName,
+		SocketPath:     socketPath,
+		PortType:       portType,
+		MACAddress:     macAddress,
+		NUMANode:       numaNode,
+		RxqAssignments: portRxqAssignments(portName),
 	}, nil
 }
 
+// portRxqAssignments returns the PMD thread polling each receive queue of a
+// port, or nil if unknown
+func portRxqAssignments(portName string) []RxqAssignment {
+	output, err := pmdRxqShow()
+	if err != nil {
+		klog.V(4).Infof("Failed to get rxq assignments of port %s: %v", portName, err)
+		return nil
+	}
+	_, _, ports := parsePMDRxqShow(output)
+	return ports[portName]
+}
+
 // PortExists checks if a DPDK port exists for a Pod
 //
 // Parameters:
@@ -17,27 +17,40 @@ const (
 	PortTypeDPDKVhostUserClient = "dpdkvhostuserclient"
 	PortTypeDPDKVhostUser       = "dpdkvhostuser"
 	DefaultSocketPermissions    = 0666
+	NUMANodeAnnotationKey       = "zstack.io/dpdk-numa-node"
+	DefaultCPUManagerStateFile  = "/var/lib/kubelet/cpu_manager_state"
 )
 
+// RxqAssignment is the PMD thread polling one receive queue of a port
+type RxqAssignment struct {
+	QueueID  int
+	CoreID   int
+	NUMANode int
+}
+
 // DPDKPortConfig contains configuration for a DPDK vhost-user port
 type DPDKPortConfig struct {
-	PodNamespace string
-	PodName      string
-	ContainerID  string
-	PortName     string
-	SocketPath   string
-	SocketMode   string
-	Queues       int
-	MACAddress   string
-	MTU          int
+	PodNamespace   string
+	PodName        string
+	ContainerID    string
+	PortName       string
+	SocketPath     string
+	SocketMode     string
+	Queues         int
+	RxqDescriptors int
+	NUMANode       *int
+	MACAddress     string
+	MTU            int
 }
 
 // DPDKPortInfo contains information about a configured DPDK port
 type DPDKPortInfo struct {
-	PortName   string
-	SocketPath string
-	PortType   string
-	MACAddress string
+	PortName       string
+	SocketPath     string
+	PortType       string
+	MACAddress     string
+	NUMANode       *int
+	RxqAssignments []RxqAssignment
 }
 
 // PortManager manages DPDK vhost-user ports
@@ -73,6 +86,11 @@ func (m *PortManager) PortExists(namespace, podName string) bool {
 	return false
 }
 
+// PodNUMANode returns an error on non-Linux platforms
+func (m *PortManager) PodNUMANode(podUID string, annotations map[string]string) (*int, error) {
+	return nil, fmt.Errorf("DPDK is not supported on %s", runtime.GOOS)
+}
+
 // GetSocketPath returns the socket path for a Pod
 func (m *PortManager) GetSocketPath(namespace, podName string) string {
 	return ""
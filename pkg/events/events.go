@@ -43,6 +43,7 @@ const (
 	ReasonIPReleased            = "IPReleased"
 	ReasonIPReleaseFailed       = "IPReleaseFailed"
 	ReasonSubnetExhausted       = "SubnetExhausted"
+	ReasonIPConflict            = "IPConflict"
 
 	// OVN operation events
 	ReasonOVNOperationFailed    = "OVNOperationFailed"
@@ -190,6 +191,12 @@ func (r *Recorder) SubnetExhausted(obj runtime.Object, subnet string) {
 		"Subnet %s has no available IP addresses", subnet)
 }
 
+// IPConflict records an IP address conflict found while rebuilding allocator state
+func (r *Recorder) IPConflict(obj runtime.Object, ip, subnet, reason string) {
+	r.recorder.Eventf(obj, corev1.EventTypeWarning, ReasonIPConflict,
+		"IP address %s in subnet %s conflicts: %s", ip, subnet, reason)
+}
+
 // ---- OVN Operation Events ----
 
 // OVNOperationFailed records an OVN operation failure event
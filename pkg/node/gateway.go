@@ -17,6 +17,7 @@
 // - SNAT: Source NAT for Pod outbound traffic (Pod IP -> Node IP)
 // - Default Route: Route external traffic through the gateway
 // - External Bridge: Connect to physical network
+// - Bridge Mappings: Map underlay provider networks to OVS bridges
 //
 // Reference: OVN-Kubernetes pkg/node/gateway_init_linux.go
 package node
@@ -232,10 +233,9 @@ func (g *GatewayController) configureLocalGateway() error {
 		return fmt.Errorf("failed to ensure external bridge: %w", err)
 	}
 
-	// Configure OVS external_ids for local gateway
-	if err := g.ovsVsctl("set", "Open_vSwitch", ".",
-		"external_ids:ovn-bridge-mappings=physnet1:"+types.BrEx); err != nil {
-		return fmt.Errorf("failed to set bridge mappings: %w", err)
+	// Map br-ex and the underlay provider networks
+	if err := g.configureBridgeMappings(); err != nil {
+		return err
 	}
 
 	// Set gateway mode in OVS
@@ -262,10 +262,9 @@ func (g *GatewayController) configureSharedGateway() error {
 		return fmt.Errorf("failed to ensure external bridge: %w", err)
 	}
 
-	// Configure OVS external_ids for shared gateway
-	if err := g.ovsVsctl("set", "Open_vSwitch", ".",
-		"external_ids:ovn-bridge-mappings=physnet1:"+types.BrEx); err != nil {
-		return fmt.Errorf("failed to set bridge mappings: %w", err)
+	// Map br-ex and the underlay provider networks
+	if err := g.configureBridgeMappings(); err != nil {
+		return err
 	}
 
 	// Set gateway mode in OVS
@@ -278,6 +277,41 @@ func (g *GatewayController) configureSharedGateway() error {
 	return nil
 }
 
+// configureBridgeMappings creates the bridges of the provider networks and
+// sets ovn-bridge-mappings, so that ovn-controller attaches the localnet
+// ports of underlay Subnets to the right bridge.
+func (g *GatewayController) configureBridgeMappings() error {
+	for _, pn := range g.globalConfig.Gateway.ProviderNetworks {
+		if err := g.ovsVsctl("--may-exist", "add-br", pn.Bridge); err != nil {
+			return fmt.Errorf("failed to create bridge %s for provider %s: %w", pn.Bridge, pn.Name, err)
+		}
+		if pn.Interface != "" {
+			if err := g.ovsVsctl("--may-exist", "add-port", pn.Bridge, pn.Interface); err != nil {
+				return fmt.Errorf("failed to add interface %s to %s: %w", pn.Interface, pn.Bridge, err)
+			}
+		}
+	}
+
+	mappings := buildBridgeMappings(g.globalConfig.Gateway.ProviderNetworks)
+	if err := g.ovsVsctl("set", "Open_vSwitch", ".",
+		"external_ids:ovn-bridge-mappings="+mappings); err != nil {
+		return fmt.Errorf("failed to set bridge mappings: %w", err)
+	}
+
+	klog.V(4).Infof("Set ovn-bridge-mappings to %s on node %s", mappings, g.nodeName)
+	return nil
+}
+
+// buildBridgeMappings builds the ovn-bridge-mappings value
+// Format: provider1:bridge1,provider2:bridge2
+func buildBridgeMappings(networks []config.ProviderNetworkConfig) string {
+	mappings := []string{types.DefaultProvider + ":" + types.BrEx}
+	for _, pn := range networks {
+		mappings = append(mappings, pn.Name+":"+pn.Bridge)
+	}
+	return strings.Join(mappings, ",")
+}
+
 // ensureExternalBridge ensures the external bridge (br-ex) exists.
 func (g *GatewayController) ensureExternalBridge() error {
 	// Check if br-ex exists
@@ -35,6 +35,7 @@ import (
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/config"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
 )
 
 // Annotation keys for node network configuration
@@ -385,9 +386,10 @@ func (c *NodeController) Reconcile(ctx context.Context, req reconcile.Request) (
 // handleNodeDelete handles node deletion.
 //
 // Cleanup steps:
-// 1. Delete the node's Logical Switch from OVN
-// 2. Release the allocated subnet
-// 3. Remove from internal tracking
+// 1. Detach the node's Logical Switch from the cluster router
+// 2. Delete the node's Logical Switch from OVN
+// 3. Release the allocated subnet
+// 4. Remove from internal tracking
 func (c *NodeController) handleNodeDelete(ctx context.Context, nodeName string) (ctrl.Result, error) {
 	klog.Infof("Handling deletion of Node %s", nodeName)
 
@@ -401,6 +403,13 @@ func (c *NodeController) handleNodeDelete(ctx context.Context, nodeName string)
 	// Delete the node's Logical Switch
 	lsName := c.getNodeLogicalSwitchName(nodeName)
 	if c.ovnClient != nil && c.ovnClient.IsConnected() {
+		// Detach from the cluster router first so no router port is left dangling
+		lrpOps := ovndb.NewLogicalRouterPortOps(c.ovnClient)
+		if err := lrpOps.DisconnectLogicalSwitch(ctx, ovndb.ClusterRouterName, lsName); err != nil {
+			klog.Errorf("Failed to disconnect Logical Switch %s from cluster router: %v", lsName, err)
+			return ctrl.Result{}, err
+		}
+
 		lsOps := ovndb.NewLogicalSwitchOps(c.ovnClient)
 		if err := lsOps.DeleteLogicalSwitch(ctx, lsName); err != nil {
 			if !ovndb.IsNotFound(err) {
@@ -499,6 +508,9 @@ func (c *NodeController) handleNodeCreateOrUpdate(ctx context.Context, node *cor
 // The Logical Switch is named "node-<nodeName>" and contains:
 // - subnet: The node's allocated subnet CIDR
 // - exclude_ips: The gateway IP (reserved)
+//
+// The switch is then attached to the cluster router through a router port
+// owning the gateway IP.
 func (c *NodeController) ensureNodeLogicalSwitch(ctx context.Context, node *corev1.Node, subnet *net.IPNet, gatewayIP net.IP) error {
 	if c.ovnClient == nil || !c.ovnClient.IsConnected() {
 		klog.V(4).Infof("OVN client not connected, skipping Logical Switch creation for node %s", node.Name)
@@ -531,10 +543,41 @@ func (c *NodeController) ensureNodeLogicalSwitch(ctx context.Context, node *core
 		return fmt.Errorf("failed to create/update Logical Switch %s: %w", lsName, err)
 	}
 
+	// Attach the switch to the cluster router so Pods can reach other nodes
+	if err := c.ensureClusterRouterPort(ctx, node.Name, lsName, subnet, gatewayIP); err != nil {
+		return fmt.Errorf("failed to connect Logical Switch %s to cluster router: %w", lsName, err)
+	}
+
 	klog.V(4).Infof("Ensured Logical Switch %s for node %s", lsName, node.Name)
 	return nil
 }
 
+// ensureClusterRouterPort connects a node's Logical Switch to the cluster router.
+//
+// The cluster router is created on first use. The router port is named
+// "rtos-<switch>" and carries the node gateway IP, so the gateway excluded
+// from the node subnet is answered by the router.
+func (c *NodeController) ensureClusterRouterPort(ctx context.Context, nodeName, lsName string, subnet *net.IPNet, gatewayIP net.IP) error {
+	lrOps := ovndb.NewLogicalRouterOps(c.ovnClient)
+	if err := lrOps.EnsureClusterRouter(ctx); err != nil {
+		return fmt.Errorf("failed to ensure cluster router: %w", err)
+	}
+
+	network, err := ovndb.BuildRouterPortNetwork(gatewayIP.String(), subnet.String())
+	if err != nil {
+		return err
+	}
+
+	externalIDs := map[string]string{
+		"k8s.io/node":    nodeName,
+		"zstack.io/type": "node-switch",
+	}
+
+	lrpOps := ovndb.NewLogicalRouterPortOps(c.ovnClient)
+	return lrpOps.ConnectLogicalSwitch(ctx, ovndb.ClusterRouterName, lsName,
+		util.GenerateMAC(gatewayIP), []string{network}, externalIDs)
+}
+
 // updateNodeAnnotations updates the node's network annotations.
 func (c *NodeController) updateNodeAnnotations(ctx context.Context, node *corev1.Node, subnet *net.IPNet, gatewayIP net.IP) error {
 	// Check if annotations need updating
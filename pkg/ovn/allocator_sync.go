@@ -0,0 +1,410 @@
+// Package ovn provides the IP allocator state rebuild on controller startup.
+//
+// IP allocators only live in memory, so after a controller restart or a
+// leader failover every SubnetAllocator starts empty. Before any new IP is
+// handed out, the AllocatorSyncer marks every address already in use:
+//
+// 1. IPs recorded as IP objects (source of truth, stale objects are deleted)
+// 2. IPs from Pod network annotations (allocations without an IP object)
+// 3. IPs from OVN Logical Switch Port addresses (lost annotations)
+//
+// Logical Switch Ports whose Pod is gone are deleted instead, so their
+// addresses are not held forever.
+//
+// Conflicts found on the way (two Pods with the same IP, an annotation IP
+// outside its subnet, a port whose IP belongs to another Pod) are reported
+// as Warning events on the affected Pod.
+//
+// The PodReconciler requeues every request until the rebuild has finished.
+// After that, IP objects whose owner is gone are garbage-collected
+// periodically, releasing their addresses. IPs reserved for a recreated
+// StatefulSet Pod are collected once their reservation has ended.
+//
+// Reference: OVN-Kubernetes pkg/ovn/base_network_controller_pods.go
+package ovn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/events"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
+)
+
+const (
+	// AllocatorSyncerName is the name of the allocator syncer
+	AllocatorSyncerName = "allocator-syncer"
+
+	// ipGCInterval is the interval between stale IP object collections
+	ipGCInterval = 5 * time.Minute
+)
+
+// AllocatorSyncer rebuilds the subnet IP allocators from cluster state.
+//
+// It implements manager.Runnable and manager.LeaderElectionRunnable, so it
+// runs on the elected leader after the informer caches are started.
+type AllocatorSyncer struct {
+	// client is the Kubernetes client
+	client client.Client
+
+	// cache waits for the informer caches before listing
+	cache cache.Cache
+
+	// recorder records conflict events
+	recorder *events.Recorder
+
+	// lspOps provides Logical Switch Port operations
+	lspOps *ovndb.LogicalSwitchPortOps
+
+	// subnetReconciler owns the allocators being rebuilt
+	subnetReconciler *SubnetReconciler
+
+	// podReconciler owns the per-Pod allocation tracking
+	podReconciler *PodReconciler
+}
+
+// NewAllocatorSyncer creates a new AllocatorSyncer.
+//
+// Parameters:
+//   - c: Kubernetes client
+//   - cache: Informer cache of the manager (mgr.GetCache())
+//   - recorder: Event recorder
+//   - ovnClient: OVN database client
+//   - subnetReconciler: Subnet reconciler owning the allocators
+//   - podReconciler: Pod reconciler owning the Pod allocations
+//
+// Returns:
+//   - *AllocatorSyncer: Allocator syncer instance
+func NewAllocatorSyncer(
+	c client.Client,
+	cache cache.Cache,
+	recorder record.EventRecorder,
+	ovnClient *ovndb.Client,
+	subnetReconciler *SubnetReconciler,
+	podReconciler *PodReconciler,
+) *AllocatorSyncer {
+	return &AllocatorSyncer{
+		client:           c,
+		cache:            cache,
+		recorder:         events.NewRecorderFromEventRecorder(recorder, AllocatorSyncerName),
+		lspOps:           ovndb.NewLogicalSwitchPortOps(ovnClient),
+		subnetReconciler: subnetReconciler,
+		podReconciler:    podReconciler,
+	}
+}
+
+// NeedLeaderElection makes the rebuild run only on the elected leader.
+func (s *AllocatorSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start rebuilds all allocators, unblocks IP allocation and then collects
+// stale IP objects until ctx is cancelled.
+//
+// A failed rebuild is returned to the manager, which stops the process;
+// serving allocations from a partially rebuilt allocator would hand out
+// IPs that are already in use.
+func (s *AllocatorSyncer) Start(ctx context.Context) error {
+	if s.cache != nil && !s.cache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("failed to wait for informer caches to sync")
+	}
+
+	if err := s.rebuild(ctx); err != nil {
+		return fmt.Errorf("failed to rebuild IP allocators: %w", err)
+	}
+
+	s.subnetReconciler.markAllocatorsSynced()
+
+	ticker := time.NewTicker(ipGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.collectStaleIPs(ctx); err != nil {
+				klog.Errorf("Failed to collect stale IP objects: %v", err)
+			}
+		}
+	}
+}
+
+// collectStaleIPs deletes IP objects whose owner no longer exists and
+// releases their addresses.
+func (s *AllocatorSyncer) collectStaleIPs(ctx context.Context) error {
+	ipList := &networkv1.IPList{}
+	if err := s.client.List(ctx, ipList); err != nil {
+		return fmt.Errorf("failed to list IPs: %w", err)
+	}
+
+	for i := range ipList.Items {
+		ipObj := &ipList.Items[i]
+		exists, err := ipOwnerExists(ctx, s.client, ipObj)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		klog.Infof("Releasing stale IP %s of %s %s", ipObj.Name, ipObj.Spec.Owner.Kind, ipObj.OwnerKey())
+		if alloc := s.subnetReconciler.GetAllocator(ipObj.Spec.Subnet); alloc != nil {
+			if ip := net.ParseIP(ipObj.Spec.IPAddress); ip != nil {
+				_ = alloc.Release(ip)
+			}
+		}
+		s.podReconciler.releaseAllocation(ipObj.OwnerKey(), ipObj.Spec.IPAddress)
+
+		if err := s.client.Delete(ctx, ipObj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete IP %s: %w", ipObj.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ipOwner identifies who holds an IP during the rebuild
+type ipOwner struct {
+	// podKey is the namespace/name of the owning Pod (empty for non-Pod owners)
+	podKey string
+
+	// port is the name of the owning Logical Switch Port (empty for annotations)
+	port string
+
+	// ipObject is the name of the IP object of a non-Pod owner
+	ipObject string
+}
+
+// rebuild marks every IP in use in the subnet allocators.
+func (s *AllocatorSyncer) rebuild(ctx context.Context) error {
+	// Create allocators for all usable subnets, indexed by name and switch
+	subnetList := &networkv1.SubnetList{}
+	if err := s.client.List(ctx, subnetList); err != nil {
+		return fmt.Errorf("failed to list subnets: %w", err)
+	}
+
+	allocators := make(map[string]*allocator.DualStackAllocator)
+	switchToSubnet := make(map[string]string)
+	for i := range subnetList.Items {
+		subnet := &subnetList.Items[i]
+		if !subnet.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := s.subnetReconciler.validateSubnet(subnet); err != nil {
+			klog.V(2).Infof("Skipping invalid subnet %s during allocator rebuild: %v", subnet.Name, err)
+			continue
+		}
+		if err := s.subnetReconciler.ensureIPAllocator(subnet); err != nil {
+			return err
+		}
+		allocators[subnet.Name] = s.subnetReconciler.GetAllocator(subnet.Name)
+		switchToSubnet[subnet.GetLogicalSwitchName()] = subnet.Name
+	}
+
+	// owners tracks the holder of each IP, keyed by subnet/ip
+	owners := make(map[string]ipOwner)
+
+	podList := &corev1.PodList{}
+	if err := s.client.List(ctx, podList); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	pods := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		pods[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = pod
+	}
+
+	// 1. IP objects
+	ipList := &networkv1.IPList{}
+	if err := s.client.List(ctx, ipList); err != nil {
+		return fmt.Errorf("failed to list IPs: %w", err)
+	}
+
+	for i := range ipList.Items {
+		ipObj := &ipList.Items[i]
+		exists, err := ipOwnerExists(ctx, s.client, ipObj)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			klog.Infof("Deleting stale IP %s of %s %s", ipObj.Name, ipObj.Spec.Owner.Kind, ipObj.OwnerKey())
+			if err := s.client.Delete(ctx, ipObj); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete IP %s: %w", ipObj.Name, err)
+			}
+			continue
+		}
+
+		alloc := allocators[ipObj.Spec.Subnet]
+		if alloc == nil {
+			klog.V(2).Infof("IP %s uses unknown subnet %s, skipping", ipObj.Name, ipObj.Spec.Subnet)
+			continue
+		}
+
+		if ipObj.Spec.Owner.Kind != networkv1.IPOwnerKindPod {
+			s.reserve(alloc, owners, ipObj.Spec.Subnet, ipObj.Spec.IPAddress, ipOwner{ipObject: ipObj.Name}, nil)
+			continue
+		}
+
+		podKey := ipObj.OwnerKey()
+		if s.reserve(alloc, owners, ipObj.Spec.Subnet, ipObj.Spec.IPAddress, ipOwner{podKey: podKey}, pods[podKey]) {
+			s.podReconciler.restoreAllocation(podKey, ipObj.Spec.IPAddress)
+		}
+	}
+
+	// 2. Pod annotations
+	for podKey, pod := range pods {
+		if !s.podReconciler.shouldManagePod(pod) {
+			continue
+		}
+
+		subnetName := util.GetPodSubnet(pod)
+		ips := util.GetPodIPs(pod)
+		if subnetName == "" || len(ips) == 0 {
+			continue
+		}
+
+		alloc := allocators[subnetName]
+		if alloc == nil {
+			klog.V(2).Infof("Pod %s uses unknown subnet %s, skipping", podKey, subnetName)
+			continue
+		}
+
+		for _, ipStr := range ips {
+			ipStr = strings.Split(ipStr, "/")[0]
+			if s.reserve(alloc, owners, subnetName, ipStr, ipOwner{podKey: podKey}, pod) {
+				s.podReconciler.restoreAllocation(podKey, ipStr)
+			}
+		}
+	}
+
+	// 3. OVN Logical Switch Ports created by the Pod controller
+	ports, err := s.lspOps.ListLogicalSwitchPortsWithPredicate(ctx, func(lsp *ovndb.LogicalSwitchPort) bool {
+		return lsp.ExternalIDs[ovndb.ExternalIDOwner] == PodControllerName
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list logical switch ports: %w", err)
+	}
+
+	for _, lsp := range ports {
+		subnetName := switchToSubnet[lsp.ExternalIDs["logical_switch"]]
+		alloc := allocators[subnetName]
+		if alloc == nil {
+			continue
+		}
+
+		podKey := types.NamespacedName{
+			Namespace: lsp.ExternalIDs[ovndb.ExternalIDNamespace],
+			Name:      lsp.ExternalIDs[ovndb.ExternalIDPod],
+		}.String()
+		pod := pods[podKey]
+		if pod == nil {
+			// The Pod was deleted while no controller was running; the Pod
+			// controller cannot create ports before the rebuild finishes
+			klog.Infof("Deleting Logical Switch Port %s of deleted Pod %s", lsp.Name, podKey)
+			if err := s.lspOps.DeleteLogicalSwitchPort(ctx, lsp.ExternalIDs["logical_switch"], lsp.Name); err != nil {
+				return fmt.Errorf("failed to delete logical switch port %s: %w", lsp.Name, err)
+			}
+			continue
+		}
+
+		for _, addresses := range lsp.Addresses {
+			_, ips := ovndb.ParseAddresses(addresses)
+			for _, ipStr := range ips {
+				if !s.reserve(alloc, owners, subnetName, ipStr, ipOwner{podKey: podKey, port: lsp.Name}, pod) {
+					continue
+				}
+				// Pod whose annotation update was lost: reuse the port IP
+				if !util.HasPodAnnotation(pod) {
+					s.podReconciler.restoreAllocation(podKey, ipStr)
+				}
+			}
+		}
+	}
+
+	for name, alloc := range allocators {
+		klog.Infof("Rebuilt IP allocator for subnet %s: %d used, %d available", name, alloc.Used(), alloc.Available())
+	}
+
+	return nil
+}
+
+// reserve marks an IP as allocated on behalf of owner.
+//
+// Returns true if the IP is now held by owner, false if it could not be
+// reserved. Conflicts are reported on pod when it is set.
+func (s *AllocatorSyncer) reserve(
+	alloc *allocator.DualStackAllocator,
+	owners map[string]ipOwner,
+	subnetName, ipStr string,
+	owner ipOwner,
+	pod *corev1.Pod,
+) bool {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+
+	key := subnetName + "/" + ip.String()
+	if existing, ok := owners[key]; ok {
+		// The port of an annotated Pod reports the same IP again
+		if existing.podKey != "" && existing.podKey == owner.podKey {
+			return true
+		}
+		s.reportConflict(pod, ipStr, subnetName, fmt.Sprintf("already used by %s", existing.describe()))
+		return false
+	}
+
+	// Allocators are empty apart from gateway and excluded IPs until the
+	// rebuild finishes, so an already allocated IP is a reserved one
+	if err := alloc.Allocate(ip); err != nil {
+		var alreadyAllocated *allocator.IPAlreadyAllocatedError
+		if errors.As(err, &alreadyAllocated) {
+			s.reportConflict(pod, ipStr, subnetName, "address is reserved for the gateway or excluded")
+			return false
+		}
+		s.reportConflict(pod, ipStr, subnetName, err.Error())
+		return false
+	}
+
+	owners[key] = owner
+	return true
+}
+
+// reportConflict logs an IP conflict and records an event on the Pod
+func (s *AllocatorSyncer) reportConflict(pod *corev1.Pod, ip, subnetName, reason string) {
+	if pod == nil {
+		klog.Warningf("IP %s in subnet %s conflicts: %s", ip, subnetName, reason)
+		return
+	}
+	klog.Warningf("IP %s of Pod %s/%s in subnet %s conflicts: %s", ip, pod.Namespace, pod.Name, subnetName, reason)
+	s.recorder.IPConflict(pod, ip, subnetName, reason)
+}
+
+// describe returns a human readable description of the IP owner
+func (o ipOwner) describe() string {
+	switch {
+	case o.podKey != "" && o.port != "":
+		return fmt.Sprintf("Pod %s (port %s)", o.podKey, o.port)
+	case o.podKey != "":
+		return fmt.Sprintf("Pod %s", o.podKey)
+	case o.ipObject != "":
+		return fmt.Sprintf("IP %s", o.ipObject)
+	default:
+		return fmt.Sprintf("port %s", o.port)
+	}
+}
@@ -0,0 +1,214 @@
+// Package ovn provides OVN native DHCP for Subnets.
+//
+// A Subnet with EnableDHCP gets one DHCP_Options row per CIDR, handing out
+// the CIDR's gateway as router along with the lease time, DNS servers and
+// MTU. Pod ports reference the rows matching their IPs, so ovn-controller
+// answers their DHCP requests locally. Other ports attached to the same
+// Logical Switch, such as ZStack VMs or KubeVirt guests, can reference the
+// same rows.
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
+)
+
+// defaultDHCPLeaseTime is the lease time used when a Subnet sets none
+const defaultDHCPLeaseTime = 3600
+
+// ensureDHCPOptions creates the DHCP_Options rows of a Subnet, or deletes
+// them when DHCP is disabled, and points the Subnet's Pod ports at them.
+func (r *SubnetReconciler) ensureDHCPOptions(ctx context.Context, subnet *networkv1.Subnet, lsName string) error {
+	log := klog.FromContext(ctx).WithValues("subnet", subnet.Name, "logicalSwitch", lsName)
+
+	externalIDs := dhcpOptionsExternalIDs(subnet.Name)
+	cidrs := subnet.CIDRs()
+	gateways := subnet.Gateways()
+
+	var rows []*ovndb.DHCPOptions
+	if subnet.Spec.EnableDHCP {
+		leaseTime := subnet.Spec.DHCPLeaseTime
+		if leaseTime == 0 {
+			leaseTime = defaultDHCPLeaseTime
+		}
+		mtu := 0
+		if r.config != nil {
+			mtu = r.config.Network.MTU
+		}
+		// Same MAC as the cluster router port of the Subnet
+		serverMAC := util.GenerateMAC(net.ParseIP(gateways[0]))
+
+		for i, cidr := range cidrs {
+			options := buildDHCPOptions(cidr, gateways[i], serverMAC, leaseTime, mtu, subnet.Spec.DNSServers)
+			row, err := r.dhcpOps.CreateOrUpdateDHCPOptions(ctx, cidr, options, externalIDs)
+			if err != nil {
+				return fmt.Errorf("failed to ensure DHCP options for %s: %w", cidr, err)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// Drop the rows of removed CIDRs, or all rows if DHCP is disabled
+	err := r.dhcpOps.DeleteDHCPOptionsWithPredicate(ctx, func(d *ovndb.DHCPOptions) bool {
+		if d.ExternalIDs[ExternalIDSubnetName] != subnet.Name || d.ExternalIDs[ExternalIDManagedBy] != ExternalIDManagedByValue {
+			return false
+		}
+		return !subnet.Spec.EnableDHCP || !containsString(cidrs, d.Cidr)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stale DHCP options: %w", err)
+	}
+
+	ports, err := r.lspOps.ListLogicalSwitchPortsWithPredicate(ctx, func(lsp *ovndb.LogicalSwitchPort) bool {
+		return lsp.ExternalIDs[ovndb.ExternalIDOwner] == PodControllerName && lsp.ExternalIDs["logical_switch"] == lsName
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list Pod ports: %w", err)
+	}
+
+	updated := 0
+	for _, lsp := range ports {
+		var ips []string
+		if len(lsp.Addresses) > 0 {
+			_, ips = ovndb.ParseAddresses(lsp.Addresses[0])
+		}
+		v4, v6 := dhcpOptionsForIPs(rows, ips)
+		if stringPtrEqual(lsp.Dhcpv4Options, v4) && stringPtrEqual(lsp.Dhcpv6Options, v6) {
+			continue
+		}
+		lsp.Dhcpv4Options = v4
+		lsp.Dhcpv6Options = v6
+		if err := r.lspOps.UpdateLogicalSwitchPort(ctx, lsp, &lsp.Dhcpv4Options, &lsp.Dhcpv6Options); err != nil {
+			return fmt.Errorf("failed to set DHCP options of port %s: %w", lsp.Name, err)
+		}
+		updated++
+	}
+
+	log.V(4).Info("DHCP options reconciled", "enabled", subnet.Spec.EnableDHCP, "rows", len(rows), "updatedPorts", updated)
+	return nil
+}
+
+// setPortDHCPOptions points a new Pod port at the DHCP_Options rows of its
+// Subnet.
+func (r *PodReconciler) setPortDHCPOptions(ctx context.Context, subnetName, portName string, ips []string) error {
+	rows, err := r.dhcpOps.ListDHCPOptionsWithPredicate(ctx, func(d *ovndb.DHCPOptions) bool {
+		return d.ExternalIDs[ExternalIDSubnetName] == subnetName && d.ExternalIDs[ExternalIDManagedBy] == ExternalIDManagedByValue
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list DHCP options: %w", err)
+	}
+
+	v4, v6 := dhcpOptionsForIPs(rows, ips)
+	if v4 == nil && v6 == nil {
+		return nil
+	}
+	if err := r.lspOps.SetDHCPOptions(ctx, portName, v4, v6); err != nil {
+		return fmt.Errorf("failed to set DHCP options: %w", err)
+	}
+	return nil
+}
+
+// dhcpOptionsExternalIDs returns the external IDs of a Subnet's DHCP rows
+func dhcpOptionsExternalIDs(subnetName string) map[string]string {
+	return map[string]string{
+		ExternalIDSubnetName: subnetName,
+		ExternalIDManagedBy:  ExternalIDManagedByValue,
+	}
+}
+
+// buildDHCPOptions builds the DHCP options of one CIDR.
+//
+// DHCPv4 hands out the gateway as router, the lease time and the MTU (if not
+// 0). DHCPv6 identifies the server by MAC and only hands out DNS servers, as
+// the default route comes from router advertisements. Each family only gets
+// the DNS servers of its own family.
+func buildDHCPOptions(cidr, gateway, serverMAC string, leaseTime, mtu int, dnsServers []string) map[string]string {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil
+	}
+	isIPv6 := ipNet.IP.To4() == nil
+
+	var dns []string
+	for _, server := range dnsServers {
+		if ip := net.ParseIP(server); ip != nil && (ip.To4() == nil) == isIPv6 {
+			dns = append(dns, ip.String())
+		}
+	}
+
+	if isIPv6 {
+		options := map[string]string{
+			ovndb.DHCPv6OptionServerID: serverMAC,
+		}
+		if len(dns) > 0 {
+			options[ovndb.DHCPv6OptionDNSServer] = "{" + strings.Join(dns, ",") + "}"
+		}
+		return options
+	}
+
+	options := map[string]string{
+		ovndb.DHCPv4OptionServerID:  gateway,
+		ovndb.DHCPv4OptionServerMAC: serverMAC,
+		ovndb.DHCPv4OptionRouter:    gateway,
+		ovndb.DHCPv4OptionLeaseTime: strconv.Itoa(leaseTime),
+	}
+	if mtu > 0 {
+		options[ovndb.DHCPv4OptionMTU] = strconv.Itoa(mtu)
+	}
+	if len(dns) > 0 {
+		options[ovndb.DHCPv4OptionDNSServer] = "{" + strings.Join(dns, ",") + "}"
+	}
+	return options
+}
+
+// dhcpOptionsForIPs returns the UUIDs of the DHCPv4 and DHCPv6 rows whose
+// CIDR contains one of the IPs, or nil for a family without a match.
+func dhcpOptionsForIPs(rows []*ovndb.DHCPOptions, ips []string) (v4, v6 *string) {
+	for _, ipStr := range ips {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			continue
+		}
+		for _, row := range rows {
+			_, ipNet, err := net.ParseCIDR(row.Cidr)
+			if err != nil || !ipNet.Contains(ip) {
+				continue
+			}
+			uuid := row.UUID
+			if ip.To4() != nil {
+				v4 = &uuid
+			} else {
+				v6 = &uuid
+			}
+			break
+		}
+	}
+	return v4, v6
+}
+
+// stringPtrEqual compares two optional strings
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// containsString checks if a slice contains a string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package ovn
+
+import (
+	"testing"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+func TestBuildDHCPOptions(t *testing.T) {
+	dns := []string{"10.96.0.10", "fd00:96::a"}
+
+	v4 := buildDHCPOptions("10.244.1.0/24", "10.244.1.1", "0a:58:0a:f4:01:01", 3600, 1400, dns)
+	expected := map[string]string{
+		"server_id":  "10.244.1.1",
+		"server_mac": "0a:58:0a:f4:01:01",
+		"router":     "10.244.1.1",
+		"lease_time": "3600",
+		"mtu":        "1400",
+		"dns_server": "{10.96.0.10}",
+	}
+	if len(v4) != len(expected) {
+		t.Fatalf("buildDHCPOptions() v4 = %v, want %v", v4, expected)
+	}
+	for k, v := range expected {
+		if v4[k] != v {
+			t.Errorf("buildDHCPOptions() v4[%s] = %q, want %q", k, v4[k], v)
+		}
+	}
+
+	v6 := buildDHCPOptions("fd00:10:244:1::/64", "fd00:10:244:1::1", "0a:58:0a:f4:01:01", 3600, 0, dns)
+	if len(v6) != 2 || v6["server_id"] != "0a:58:0a:f4:01:01" || v6["dns_server"] != "{fd00:96::a}" {
+		t.Errorf("buildDHCPOptions() v6 = %v", v6)
+	}
+}
+
+func TestDHCPOptionsForIPs(t *testing.T) {
+	rows := []*ovndb.DHCPOptions{
+		{UUID: "uuid-v4", Cidr: "10.244.1.0/24"},
+		{UUID: "uuid-v6", Cidr: "fd00::/64"},
+	}
+
+	tests := []struct {
+		name   string
+		ips    []string
+		wantV4 string
+		wantV6 string
+	}{
+		{"dual-stack", []string{"10.244.1.5", "fd00::5"}, "uuid-v4", "uuid-v6"},
+		{"IPv4 only", []string{"10.244.1.5"}, "uuid-v4", ""},
+		{"outside every CIDR", []string{"10.244.2.5"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v4, v6 := dhcpOptionsForIPs(rows, tt.ips)
+			if got := derefString(v4); got != tt.wantV4 {
+				t.Errorf("dhcpOptionsForIPs() v4 = %q, want %q", got, tt.wantV4)
+			}
+			if got := derefString(v6); got != tt.wantV6 {
+				t.Errorf("dhcpOptionsForIPs() v6 = %q, want %q", got, tt.wantV6)
+			}
+		})
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
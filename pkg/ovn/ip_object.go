@@ -0,0 +1,169 @@
+// Package ovn provides IP object (IP CRD) management.
+//
+// Every IP allocated to a Pod is recorded as a cluster-scoped IP object
+// named after its subnet and address. The IP objects are the source of
+// truth the subnet allocators are loaded from on startup (see
+// AllocatorSyncer), and let operators inspect allocations with
+// "kubectl get ips".
+//
+// Because the object name is derived from the address, creating an IP
+// object fails if the address is already recorded for another owner,
+// which guards against handing out the same IP twice.
+package ovn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+)
+
+// errIPInUse indicates that an IP object exists for another owner
+var errIPInUse = errors.New("IP is recorded for another owner")
+
+// newPodIPObject builds the IP object recording an allocation for a Pod
+func newPodIPObject(pod *corev1.Pod, subnetName, ip, mac, portName string) *networkv1.IP {
+	return &networkv1.IP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: networkv1.BuildIPName(subnetName, ip),
+			Labels: map[string]string{
+				networkv1.IPSubnetLabel: subnetName,
+				ExternalIDManagedBy:     ExternalIDManagedByValue,
+			},
+		},
+		Spec: networkv1.IPSpec{
+			Subnet:            subnetName,
+			IPAddress:         ip,
+			MACAddress:        mac,
+			LogicalSwitchPort: portName,
+			NodeName:          pod.Spec.NodeName,
+			Owner: networkv1.IPOwner{
+				Kind:      networkv1.IPOwnerKindPod,
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+		},
+	}
+}
+
+// sameIPOwner returns true if both owners reference the same object
+// An empty UID matches any UID (objects recorded before the UID was known).
+func sameIPOwner(a, b networkv1.IPOwner) bool {
+	if a.Kind != b.Kind || a.Namespace != b.Namespace || a.Name != b.Name {
+		return false
+	}
+	return a.UID == "" || b.UID == "" || a.UID == b.UID
+}
+
+// ensureIPObject creates the IP object or updates its mutable fields
+//
+// Returns errIPInUse if the address is already recorded for another owner.
+func ensureIPObject(ctx context.Context, c client.Client, desired *networkv1.IP) error {
+	existing := &networkv1.IP{}
+	err := c.Get(ctx, types.NamespacedName{Name: desired.Name}, existing)
+	if apierrors.IsNotFound(err) {
+		if err := c.Create(ctx, desired); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("%w: %s", errIPInUse, desired.Name)
+			}
+			return fmt.Errorf("failed to create IP %s: %w", desired.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get IP %s: %w", desired.Name, err)
+	}
+
+	if !sameIPOwner(existing.Spec.Owner, desired.Spec.Owner) {
+		return fmt.Errorf("%w: %s is owned by %s %s", errIPInUse, desired.Name,
+			existing.Spec.Owner.Kind, existing.OwnerKey())
+	}
+
+	_, reserved := existing.ReservedUntil()
+	if existing.Spec == desired.Spec && !reserved {
+		return nil
+	}
+	// A recreated owner takes its reserved IP back
+	existing.Spec = desired.Spec
+	delete(existing.Annotations, networkv1.IPReservedUntilAnnotation)
+	if err := c.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update IP %s: %w", desired.Name, err)
+	}
+	return nil
+}
+
+// deleteIPObject deletes the IP object of an address, ignoring missing objects
+func deleteIPObject(ctx context.Context, c client.Client, subnetName, ip string) error {
+	obj := &networkv1.IP{
+		ObjectMeta: metav1.ObjectMeta{Name: networkv1.BuildIPName(subnetName, ip)},
+	}
+	if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete IP %s: %w", obj.Name, err)
+	}
+	return nil
+}
+
+// reserveIPObject keeps the IP object of a deleted owner until the given
+// time, so an owner recreated with the same name gets the address back.
+//
+// The owner UID is cleared, which lets the recreated owner match the
+// object (see sameIPOwner). Returns false if the object does not exist or
+// belongs to another owner, in which case the IP should be released.
+func reserveIPObject(ctx context.Context, c client.Client, subnetName, ip string, owner networkv1.IPOwner, until time.Time) (bool, error) {
+	existing := &networkv1.IP{}
+	err := c.Get(ctx, types.NamespacedName{Name: networkv1.BuildIPName(subnetName, ip)}, existing)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get IP %s: %w", networkv1.BuildIPName(subnetName, ip), err)
+	}
+	if !sameIPOwner(existing.Spec.Owner, owner) {
+		return false, nil
+	}
+
+	existing.Spec.Owner.UID = ""
+	existing.Spec.LogicalSwitchPort = ""
+	if existing.Annotations == nil {
+		existing.Annotations = make(map[string]string)
+	}
+	existing.Annotations[networkv1.IPReservedUntilAnnotation] = until.UTC().Format(time.RFC3339)
+	if err := c.Update(ctx, existing); err != nil {
+		return false, fmt.Errorf("failed to reserve IP %s: %w", existing.Name, err)
+	}
+	return true, nil
+}
+
+// ipOwnerExists checks whether the owner of an IP object still exists
+//
+// Only Pod owners are checked; IPs of other kinds (e.g. VMs) are managed
+// outside this controller and are always treated as in use. A reserved IP
+// (see reserveIPObject) is in use until its reservation ends.
+func ipOwnerExists(ctx context.Context, c client.Client, ip *networkv1.IP) (bool, error) {
+	if ip.Spec.Owner.Kind != networkv1.IPOwnerKindPod {
+		return true, nil
+	}
+	if until, ok := ip.ReservedUntil(); ok && time.Now().Before(until) {
+		return true, nil
+	}
+
+	pod := &corev1.Pod{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: ip.Spec.Owner.Namespace, Name: ip.Spec.Owner.Name}, pod)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return ip.Spec.Owner.UID == "" || ip.Spec.Owner.UID == pod.UID, nil
+}
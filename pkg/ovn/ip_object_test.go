@@ -0,0 +1,51 @@
+// Package ovn provides tests for IP object helpers.
+package ovn
+
+import (
+	"testing"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+)
+
+// TestBuildIPName tests IP object naming for IPv4 and IPv6 addresses.
+func TestBuildIPName(t *testing.T) {
+	tests := []struct {
+		subnet   string
+		ip       string
+		expected string
+	}{
+		{"default", "10.244.1.5", "default.10.244.1.5"},
+		{"v6", "fd00:10:244::5", "v6.fd00-10-244--5"},
+	}
+
+	for _, tt := range tests {
+		if got := networkv1.BuildIPName(tt.subnet, tt.ip); got != tt.expected {
+			t.Errorf("BuildIPName(%q, %q) = %q, want %q", tt.subnet, tt.ip, got, tt.expected)
+		}
+	}
+}
+
+// TestSameIPOwner tests IP owner comparison.
+func TestSameIPOwner(t *testing.T) {
+	owner := networkv1.IPOwner{Kind: networkv1.IPOwnerKindPod, Namespace: "default", Name: "web-0", UID: "uid-1"}
+
+	tests := []struct {
+		name     string
+		other    networkv1.IPOwner
+		expected bool
+	}{
+		{"identical", owner, true},
+		{"empty UID", networkv1.IPOwner{Kind: owner.Kind, Namespace: owner.Namespace, Name: owner.Name}, true},
+		{"recreated Pod", networkv1.IPOwner{Kind: owner.Kind, Namespace: owner.Namespace, Name: owner.Name, UID: "uid-2"}, false},
+		{"other Pod", networkv1.IPOwner{Kind: owner.Kind, Namespace: owner.Namespace, Name: "web-1", UID: "uid-1"}, false},
+		{"other kind", networkv1.IPOwner{Kind: networkv1.IPOwnerKindVM, Namespace: owner.Namespace, Name: owner.Name}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameIPOwner(owner, tt.other); got != tt.expected {
+				t.Errorf("sameIPOwner() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package ovn provides the cluster Load Balancer Group of Services.
+//
+// Service Load Balancers are not attached to Logical Switches one by one.
+// A single Load_Balancer_Group is referenced by every Subnet switch and by
+// the cluster router, and the Service controller only adds its Load
+// Balancers to the group:
+// - Creating a Service costs one mutation instead of one per switch
+// - A new Subnet gets every Service through its group reference
+// - Deleted Load Balancers drop out of the group (weak references)
+//
+// The group is created on startup, where Load Balancers attached to
+// switches by earlier versions are moved into it.
+//
+// Reference: OVN-Kubernetes pkg/ovn/default_network_controller.go (clusterLBGroup)
+package ovn
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+// SyncLoadBalancerGroup creates the cluster Load Balancer Group and moves
+// the Service Load Balancers into it.
+//
+// Load Balancers still referenced directly by Logical Switches are added to
+// the group and then removed from the switches. It is meant to run once on
+// the elected leader (see manager.RunnableFunc).
+func (r *ServiceReconciler) SyncLoadBalancerGroup(ctx context.Context) error {
+	group, err := r.lbgOps.EnsureLoadBalancerGroup(ctx, ovndb.ClusterLoadBalancerGroupName)
+	if err != nil {
+		return fmt.Errorf("failed to ensure Load Balancer group: %w", err)
+	}
+
+	lbs, err := r.lbOps.ListLoadBalancersWithPredicate(ctx, func(lb *ovndb.LoadBalancer) bool {
+		return lb.ExternalIDs[LBExternalIDOwner] == ServiceControllerName
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list Load Balancers: %w", err)
+	}
+
+	owned := make(map[string]bool, len(lbs))
+	var missing []string
+	for _, lb := range lbs {
+		owned[lb.UUID] = true
+		if !containsString(group.LoadBalancer, lb.UUID) {
+			missing = append(missing, lb.UUID)
+		}
+	}
+	if err := r.lbgOps.AddLoadBalancers(ctx, group.Name, missing...); err != nil {
+		return fmt.Errorf("failed to add Load Balancers to group: %w", err)
+	}
+
+	switches, err := r.lsOps.ListLogicalSwitches(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list switches: %w", err)
+	}
+	for _, ls := range switches {
+		var attached []string
+		for _, lbUUID := range ls.LoadBalancer {
+			if owned[lbUUID] {
+				attached = append(attached, lbUUID)
+			}
+		}
+		if len(attached) == 0 {
+			continue
+		}
+		if err := r.lsOps.RemoveLoadBalancersFromLogicalSwitch(ctx, ls.Name, attached...); err != nil {
+			return fmt.Errorf("failed to detach Load Balancers from switch %s: %w", ls.Name, err)
+		}
+	}
+
+	klog.Infof("Load Balancer group %s synced, %d Load Balancers added", group.Name, len(missing))
+	return nil
+}
+
+// addLoadBalancerToGroup adds a Load Balancer to the cluster Load Balancer
+// Group, making it apply to every Subnet switch and the cluster router.
+func (r *ServiceReconciler) addLoadBalancerToGroup(ctx context.Context, lbUUID string) error {
+	group, err := r.lbgOps.EnsureLoadBalancerGroup(ctx, ovndb.ClusterLoadBalancerGroupName)
+	if err != nil {
+		return err
+	}
+	if containsString(group.LoadBalancer, lbUUID) {
+		return nil
+	}
+	return r.lbgOps.AddLoadBalancers(ctx, group.Name, lbUUID)
+}
+
+// ensureSwitchLoadBalancerGroup makes a Subnet's Logical Switch reference
+// the cluster Load Balancer Group.
+func (r *SubnetReconciler) ensureSwitchLoadBalancerGroup(ctx context.Context, lsName string) error {
+	group, err := r.lbgOps.EnsureLoadBalancerGroup(ctx, ovndb.ClusterLoadBalancerGroupName)
+	if err != nil {
+		return err
+	}
+
+	ls, err := r.lsOps.GetLogicalSwitch(ctx, lsName)
+	if err != nil {
+		return err
+	}
+	if containsString(ls.LoadBalancerGroup, group.UUID) {
+		return nil
+	}
+	return r.lsOps.AddLoadBalancerGroupsToLogicalSwitch(ctx, lsName, group.UUID)
+}
+
+// ensureRouterLoadBalancerGroup makes a Logical Router reference the
+// cluster Load Balancer Group.
+func (r *SubnetReconciler) ensureRouterLoadBalancerGroup(ctx context.Context, routerName string) error {
+	group, err := r.lbgOps.EnsureLoadBalancerGroup(ctx, ovndb.ClusterLoadBalancerGroupName)
+	if err != nil {
+		return err
+	}
+
+	lr, err := r.lrOps.GetLogicalRouter(ctx, routerName)
+	if err != nil {
+		return err
+	}
+	if containsString(lr.LoadBalancerGroup, group.UUID) {
+		return nil
+	}
+	return r.lrOps.AddLoadBalancerGroupsToLogicalRouter(ctx, routerName, group.UUID)
+}
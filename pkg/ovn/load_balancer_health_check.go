@@ -0,0 +1,152 @@
+// Package ovn provides OVN Load Balancer health checks for Services.
+//
+// A backend only leaves a Service's VIPs once its Pod is no longer Ready. A
+// Pod that is still Ready but no longer answers (e.g., a hung process
+// without a liveness probe) keeps receiving connections. With the
+// zstack.io/lb-health-check annotation, ovn-controller probes the backends
+// itself and northd removes those failing the probes:
+//   - Each VIP of a listed protocol gets a Load_Balancer_Health_Check row
+//   - ip_port_mappings maps each backend IP to the Logical Switch Port of
+//     its Pod and to the health check source IP of its Subnet
+//     (Subnet spec.healthCheckIP)
+//
+// Backends without a mapping (no Pod, or a Subnet without a health check
+// IP) are not probed and stay in the VIPs. OVN supports TCP and UDP health
+// checks, but not on template Load Balancers (NodePorts, and node-dependent
+// ClusterIPs), which are left unchecked.
+//
+// Reference: ovn-nb(5) Load_Balancer_Health_Check
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+// Health check probe settings, the OVN defaults
+const (
+	healthCheckInterval     = "5"
+	healthCheckTimeout      = "20"
+	healthCheckSuccessCount = "3"
+	healthCheckFailureCount = "3"
+)
+
+// ensureHealthChecks creates the health checks of the Service's Load
+// Balancers for the protocols listed in ServiceHealthCheckAnnotation, and
+// removes them from the other Load Balancers.
+func (r *ServiceReconciler) ensureHealthChecks(ctx context.Context, svc *corev1.Service, endpoints []EndpointInfo) error {
+	log := klog.FromContext(ctx).WithValues("service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
+
+	key := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+	lbs, err := r.lbOps.ListLoadBalancersWithPredicate(ctx, func(lb *ovndb.LoadBalancer) bool {
+		return lb.ExternalIDs[LBExternalIDService] == key && lb.ExternalIDs[LBExternalIDOwner] == ServiceControllerName
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list Load Balancers: %w", err)
+	}
+
+	protocols := healthCheckProtocols(svc)
+	var mappings map[string]string
+	if len(protocols) > 0 {
+		subnetList := &networkv1.SubnetList{}
+		if err := r.client.List(ctx, subnetList); err != nil {
+			return fmt.Errorf("failed to list subnets: %w", err)
+		}
+		mappings = buildIPPortMappings(endpoints, subnetList.Items)
+	}
+
+	for _, lb := range lbs {
+		protocol := ovndb.LoadBalancerProtocolTCP
+		if lb.Protocol != nil {
+			protocol = *lb.Protocol
+		}
+
+		var checks map[string]map[string]string
+		var lbMappings map[string]string
+		if protocols[protocol] && lb.Options[ovndb.LBOptionTemplate] != "true" {
+			checks = make(map[string]map[string]string, len(lb.Vips))
+			for vip := range lb.Vips {
+				checks[vip] = map[string]string{
+					ovndb.LBHealthCheckOptionInterval:     healthCheckInterval,
+					ovndb.LBHealthCheckOptionTimeout:      healthCheckTimeout,
+					ovndb.LBHealthCheckOptionSuccessCount: healthCheckSuccessCount,
+					ovndb.LBHealthCheckOptionFailureCount: healthCheckFailureCount,
+				}
+			}
+			lbMappings = mappings
+		}
+		if len(checks) == 0 && len(lb.HealthCheck) == 0 && len(lb.IPPortMappings) == 0 {
+			continue
+		}
+
+		if err := r.lbOps.SetHealthChecks(ctx, lb.Name, checks, lbMappings); err != nil {
+			return fmt.Errorf("failed to set health checks of %s: %w", lb.Name, err)
+		}
+		log.V(4).Info("Health checks configured", "lb", lb.Name, "vips", len(checks), "backends", len(lbMappings))
+	}
+
+	return nil
+}
+
+// healthCheckProtocols returns the Load Balancer protocols a Service
+// requests health checks for
+func healthCheckProtocols(svc *corev1.Service) map[string]bool {
+	protocols := make(map[string]bool)
+	for _, p := range strings.Split(svc.Annotations[ServiceHealthCheckAnnotation], ",") {
+		switch p = strings.ToLower(strings.TrimSpace(p)); p {
+		case ovndb.LoadBalancerProtocolTCP, ovndb.LoadBalancerProtocolUDP:
+			protocols[p] = true
+		}
+	}
+	return protocols
+}
+
+// buildIPPortMappings builds the ip_port_mappings of a Service's Load
+// Balancers, mapping each Pod backend to its Logical Switch Port and the
+// health check source IP of its Subnet
+func buildIPPortMappings(endpoints []EndpointInfo, subnets []networkv1.Subnet) map[string]string {
+	mappings := make(map[string]string)
+	for _, ep := range endpoints {
+		if ep.PodName == "" {
+			continue
+		}
+		sourceIP := healthCheckSourceIP(net.ParseIP(ep.Address), subnets)
+		if sourceIP == "" {
+			continue
+		}
+		key, value := ovndb.BuildIPPortMapping(ep.Address, ovndb.BuildPortName(ep.PodNamespace, ep.PodName), sourceIP)
+		mappings[key] = value
+	}
+	return mappings
+}
+
+// healthCheckSourceIP returns the health check IP of the Subnet containing
+// ip, of the same family, or an empty string if there is none
+func healthCheckSourceIP(ip net.IP, subnets []networkv1.Subnet) string {
+	if ip == nil {
+		return ""
+	}
+	for i := range subnets {
+		for _, cidr := range subnets[i].CIDRs() {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil || !ipNet.Contains(ip) {
+				continue
+			}
+			for _, hcIP := range subnets[i].HealthCheckIPs() {
+				if parsed := net.ParseIP(hcIP); parsed != nil && (parsed.To4() == nil) == (ip.To4() == nil) {
+					return parsed.String()
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
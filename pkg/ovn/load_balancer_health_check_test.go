@@ -0,0 +1,58 @@
+package ovn
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+)
+
+func TestHealthCheckProtocols(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected map[string]bool
+	}{
+		{"", map[string]bool{}},
+		{"tcp", map[string]bool{"tcp": true}},
+		{"TCP, udp", map[string]bool{"tcp": true, "udp": true}},
+		{"sctp", map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{ServiceHealthCheckAnnotation: tt.value},
+			}}
+			if got := healthCheckProtocols(svc); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("healthCheckProtocols(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildIPPortMappings(t *testing.T) {
+	subnets := []networkv1.Subnet{
+		{Spec: networkv1.SubnetSpec{
+			CIDR:          "10.244.1.0/24,fd00:10:244:1::/64",
+			HealthCheckIP: "10.244.1.254,fd00:10:244:1::fe",
+		}},
+		{Spec: networkv1.SubnetSpec{CIDR: "10.244.2.0/24"}},
+	}
+	endpoints := []EndpointInfo{
+		{Address: "10.244.1.5", PodNamespace: "default", PodName: "web-1"},
+		{Address: "fd00:10:244:1::5", PodNamespace: "default", PodName: "web-1"},
+		{Address: "10.244.2.6", PodNamespace: "default", PodName: "web-2"},
+		{Address: "10.244.1.7"},
+	}
+
+	expected := map[string]string{
+		"10.244.1.5":         "default_web-1:10.244.1.254",
+		"[fd00:10:244:1::5]": "default_web-1:[fd00:10:244:1::fe]",
+	}
+	if got := buildIPPortMappings(endpoints, subnets); !reflect.DeepEqual(got, expected) {
+		t.Errorf("buildIPPortMappings() = %v, want %v", got, expected)
+	}
+}
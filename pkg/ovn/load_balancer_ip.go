@@ -0,0 +1,438 @@
+// Package ovn provides external IPs for LoadBalancer Services.
+//
+// Without a cloud provider, nothing assigns the external IP of a
+// LoadBalancer Service. The ServiceReconciler allocates it from a
+// LoadBalancerIPPool (e.g., a ZStack public network range) instead:
+//   - The pool comes from the Service annotation, or the first pool by name
+//     with an available IP; spec.loadBalancerIP requests a specific IP
+//   - The allocation is recorded in status.loadBalancer.ingress, which is
+//     the source of truth after a controller restart
+//   - Each IP and Service port becomes a VIP of a dedicated Load Balancer
+//     ("Service_<namespace>/<name>_<protocol>_lb"), attached to the Logical
+//     Switches for Pod traffic and to the pool's router, which answers ARP
+//     and neighbor discovery for the VIPs (options:neighbor_responder=all)
+//   - The pool's router must be bound to a chassis (a gateway router or a
+//     router with a gateway port); otherwise the IPs are reported in
+//     events on the Service and the pool and not programmed
+//
+// Services with spec.loadBalancerClass belong to another implementation
+// and are left alone.
+package ovn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+// ensureLoadBalancerIngress allocates the external IPs of a LoadBalancer
+// Service and programs them as VIPs, or releases them for other Services.
+//
+// An allocation failure is reported as an event and does not fail the
+// reconciliation; the Service is reconciled again when a pool changes.
+func (r *ServiceReconciler) ensureLoadBalancerIngress(ctx context.Context, svc *corev1.Service, endpoints []EndpointInfo) error {
+	log := klog.FromContext(ctx).WithValues("service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
+
+	if !managesLoadBalancerIngress(svc) {
+		return r.releaseLoadBalancerIngress(ctx, svc)
+	}
+
+	pool, ips, err := r.allocateLoadBalancerIPs(ctx, svc)
+	if err != nil {
+		log.Info("No external IP available", "reason", err.Error())
+		r.recorder.Event(svc, corev1.EventTypeWarning, "LoadBalancerIPUnavailable", err.Error())
+		return nil
+	}
+
+	if !equalStrings(loadBalancerIngressIPs(svc), ips) {
+		patch := client.MergeFrom(svc.DeepCopy())
+		svc.Status.LoadBalancer.Ingress = make([]corev1.LoadBalancerIngress, 0, len(ips))
+		for _, ip := range ips {
+			svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: ip})
+		}
+		if err := r.client.Status().Patch(ctx, svc, patch); err != nil {
+			return fmt.Errorf("failed to update load balancer status: %w", err)
+		}
+		log.Info("Allocated external IPs", "pool", pool.Name, "ips", ips)
+		r.recorder.Event(svc, corev1.EventTypeNormal, "LoadBalancerIPAllocated",
+			fmt.Sprintf("Allocated %s from LoadBalancerIPPool %s", strings.Join(ips, ","), pool.Name))
+	}
+
+	router := pool.Spec.Router
+	reason, err := r.poolRouterProblem(ctx, router)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		msg := fmt.Sprintf("LoadBalancerIPPool %s cannot answer ARP for %s: %s", pool.Name, strings.Join(ips, ","), reason)
+		log.Info("External IPs not programmed", "pool", pool.Name, "reason", reason)
+		r.recorder.Event(svc, corev1.EventTypeWarning, "LoadBalancerRouterNotGateway", msg)
+		r.recorder.Event(pool, corev1.EventTypeWarning, "RouterNotGateway", msg)
+		return nil
+	}
+
+	// One Load Balancer per protocol, holding the VIPs of all its ports
+	vipsByProtocol := map[string]map[string]string{}
+	for _, port := range svc.Spec.Ports {
+		protocol := serviceProtocol(port)
+		if vipsByProtocol[protocol] == nil {
+			vipsByProtocol[protocol] = map[string]string{}
+		}
+		backends := r.buildBackends(endpoints, port)
+		if backends == "" {
+			continue
+		}
+		for _, ip := range ips {
+			vipsByProtocol[protocol][ovndb.BuildVIP(ip, int(port.Port))] = backends
+		}
+	}
+
+	for protocol, vips := range vipsByProtocol {
+		if err := r.ensureIngressLoadBalancer(ctx, svc, protocol, vips, router); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// poolRouterProblem returns why the router of a LoadBalancerIPPool cannot
+// answer ARP and neighbor discovery from the external network, or an empty
+// string if it can. Only a router bound to a chassis can.
+func (r *ServiceReconciler) poolRouterProblem(ctx context.Context, router string) (string, error) {
+	if router == "" {
+		return "spec.router is not set", nil
+	}
+
+	gateway, err := r.lrOps.IsGatewayRouter(ctx, router)
+	if err != nil {
+		if ovndb.IsNotFound(err) {
+			return fmt.Sprintf("router %s does not exist", router), nil
+		}
+		return "", fmt.Errorf("failed to get router %s: %w", router, err)
+	}
+	if !gateway {
+		return fmt.Sprintf("router %s is not a gateway router and has no gateway port", router), nil
+	}
+	return "", nil
+}
+
+// ensureIngressLoadBalancer creates or updates the Load Balancer of a
+// LoadBalancer Service's external IPs for one protocol and attaches it to
+// the router answering ARP for them.
+func (r *ServiceReconciler) ensureIngressLoadBalancer(
+	ctx context.Context,
+	svc *corev1.Service,
+	protocol string,
+	vips map[string]string,
+	router string,
+) error {
+	// Reply to ARP/ND for the VIPs even though they are outside the
+	// router's networks; an empty value removes the option
+	options := map[string]string{
+		ovndb.LBOptionNeighborResponder: "all",
+		ovndb.LBOptionSkipSNAT:          "",
+		ovndb.LBOptionAffinityTimeout:   sessionAffinityTimeout(svc),
+		ovndb.LBOptionHairpinSNATIP:     r.hairpinSNATIP(),
+	}
+	if svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		// Skip SNAT to preserve the client source IP
+		options[ovndb.LBOptionSkipSNAT] = "true"
+	}
+
+	lbUUID, err := r.ensureLoadBalancer(ctx, svc, LBKindLoadBalancer, protocol, vips, options)
+	if err != nil {
+		return err
+	}
+
+	if err := r.attachLoadBalancerToRouter(ctx, router, lbUUID); err != nil {
+		return fmt.Errorf("failed to attach LoadBalancer LB to router %s: %w", router, err)
+	}
+	return nil
+}
+
+// attachLoadBalancerToRouter attaches a Load Balancer to a router, and
+// detaches it from the routers of a pool it moved away from.
+func (r *ServiceReconciler) attachLoadBalancerToRouter(ctx context.Context, router, lbUUID string) error {
+	if err := r.lrOps.AddLoadBalancersToLogicalRouter(ctx, router, lbUUID); err != nil {
+		return err
+	}
+
+	others, err := r.lrOps.ListLogicalRoutersWithPredicate(ctx, func(lr *ovndb.LogicalRouter) bool {
+		return lr.Name != router && containsString(lr.LoadBalancer, lbUUID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list routers: %w", err)
+	}
+	for _, lr := range others {
+		if err := r.lrOps.RemoveLoadBalancersFromLogicalRouter(ctx, lr.Name, lbUUID); err != nil {
+			return fmt.Errorf("failed to detach LB from router %s: %w", lr.Name, err)
+		}
+	}
+	return nil
+}
+
+// allocateLoadBalancerIPs returns the external IPs of a LoadBalancer
+// Service, keeping the current ones if they still fit its pool and
+// spec.loadBalancerIP.
+func (r *ServiceReconciler) allocateLoadBalancerIPs(ctx context.Context, svc *corev1.Service) (*networkv1.LoadBalancerIPPool, []string, error) {
+	poolList := &networkv1.LoadBalancerIPPoolList{}
+	if err := r.client.List(ctx, poolList); err != nil {
+		return nil, nil, fmt.Errorf("failed to list LoadBalancerIPPools: %w", err)
+	}
+	pools := poolList.Items
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+
+	if name := svc.Annotations[networkv1.LoadBalancerIPPoolAnnotation]; name != "" {
+		var selected []networkv1.LoadBalancerIPPool
+		for _, pool := range pools {
+			if pool.Name == name {
+				selected = append(selected, pool)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, nil, fmt.Errorf("LoadBalancerIPPool %s not found", name)
+		}
+		pools = selected
+	}
+	if len(pools) == 0 {
+		return nil, nil, fmt.Errorf("no LoadBalancerIPPool exists")
+	}
+
+	key := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+	requested := svc.Spec.LoadBalancerIP
+	current := loadBalancerIngressIPs(svc)
+
+	r.lbIngressMu.Lock()
+	defer r.lbIngressMu.Unlock()
+
+	if len(current) > 0 && (requested == "" || containsString(current, requested)) {
+		for i := range pools {
+			if poolContainsIPs(&pools[i], current) {
+				r.recordLoadBalancerIPs(key, current)
+				return &pools[i], current, nil
+			}
+		}
+	}
+
+	used, err := r.usedLoadBalancerIPs(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var lastErr error
+	for i := range pools {
+		ips, err := allocateFromPool(&pools[i], used, requested, svc.Spec.IPFamilies)
+		if err != nil {
+			lastErr = fmt.Errorf("LoadBalancerIPPool %s: %w", pools[i].Name, err)
+			continue
+		}
+		r.recordLoadBalancerIPs(key, ips)
+		return &pools[i], ips, nil
+	}
+	return nil, nil, lastErr
+}
+
+// usedLoadBalancerIPs returns the external IPs in use by Services other
+// than key, from their status and from allocations not yet in the cache.
+// The caller must hold lbIngressMu.
+func (r *ServiceReconciler) usedLoadBalancerIPs(ctx context.Context, key string) (map[string]bool, error) {
+	svcList := &corev1.ServiceList{}
+	if err := r.client.List(ctx, svcList); err != nil {
+		return nil, fmt.Errorf("failed to list Services: %w", err)
+	}
+
+	used := make(map[string]bool)
+	for i := range svcList.Items {
+		other := &svcList.Items[i]
+		if fmt.Sprintf("%s/%s", other.Namespace, other.Name) == key {
+			continue
+		}
+		for _, ip := range loadBalancerIngressIPs(other) {
+			used[ip] = true
+		}
+	}
+	for ip, owner := range r.lbIngressIPs {
+		if owner != key {
+			used[ip] = true
+		}
+	}
+	return used, nil
+}
+
+// recordLoadBalancerIPs records the external IPs allocated to a Service.
+// The caller must hold lbIngressMu.
+func (r *ServiceReconciler) recordLoadBalancerIPs(key string, ips []string) {
+	for ip, owner := range r.lbIngressIPs {
+		if owner == key {
+			delete(r.lbIngressIPs, ip)
+		}
+	}
+	for _, ip := range ips {
+		r.lbIngressIPs[ip] = key
+	}
+}
+
+// releaseLoadBalancerIngress forgets the external IPs of a Service that is
+// no longer of type LoadBalancer, clearing them from its status.
+func (r *ServiceReconciler) releaseLoadBalancerIngress(ctx context.Context, svc *corev1.Service) error {
+	r.lbIngressMu.Lock()
+	r.recordLoadBalancerIPs(fmt.Sprintf("%s/%s", svc.Namespace, svc.Name), nil)
+	r.lbIngressMu.Unlock()
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer || len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return nil
+	}
+	patch := client.MergeFrom(svc.DeepCopy())
+	svc.Status.LoadBalancer = corev1.LoadBalancerStatus{}
+	if err := r.client.Status().Patch(ctx, svc, patch); err != nil {
+		return fmt.Errorf("failed to clear load balancer status: %w", err)
+	}
+	return nil
+}
+
+// loadBalancerIPPoolToServices maps LoadBalancerIPPool events to the
+// LoadBalancer Services, so that pending Services get an IP from a new pool.
+func (r *ServiceReconciler) loadBalancerIPPoolToServices(ctx context.Context, obj client.Object) []reconcile.Request {
+	svcList := &corev1.ServiceList{}
+	if err := r.client.List(ctx, svcList); err != nil {
+		klog.V(4).Infof("Failed to list Services for LoadBalancerIPPool %s: %v", obj.GetName(), err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, svc := range svcList.Items {
+		if managesLoadBalancerIngress(&svc) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&svc)})
+		}
+	}
+	return requests
+}
+
+// managesLoadBalancerIngress checks if the controller assigns the external
+// IPs of a Service
+func managesLoadBalancerIngress(svc *corev1.Service) bool {
+	return svc.Spec.Type == corev1.ServiceTypeLoadBalancer && svc.Spec.LoadBalancerClass == nil
+}
+
+// loadBalancerIngressIPs returns the IPs in a Service's load balancer status
+func loadBalancerIngressIPs(svc *corev1.Service) []string {
+	var ips []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			ips = append(ips, ingress.IP)
+		}
+	}
+	return ips
+}
+
+// allocateFromPool allocates one IP per IP family of a Service from a pool,
+// skipping the used IPs.
+//
+// Parameters:
+//   - pool: Pool to allocate from
+//   - used: IPs in use by other Services
+//   - requested: Requested IP (spec.loadBalancerIP), empty for any
+//   - families: IP families of the Service, empty for all families of the pool
+//
+// Returns:
+//   - []string: Allocated IPs, in family order of the pool
+//   - error: Error if the pool is invalid, exhausted, or cannot provide the
+//     requested IP
+func allocateFromPool(pool *networkv1.LoadBalancerIPPool, used map[string]bool, requested string, families []corev1.IPFamily) ([]string, error) {
+	a, err := allocator.NewDualStackAllocator(pool.CIDRs(), pool.Spec.ExcludeIPs)
+	if err != nil {
+		return nil, err
+	}
+	for ip := range used {
+		if parsed := net.ParseIP(ip); parsed != nil && a.Contains(parsed) {
+			_ = a.Allocate(parsed)
+		}
+	}
+
+	var ips []net.IP
+	if requested != "" {
+		ip := net.ParseIP(requested)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid loadBalancerIP %q", requested)
+		}
+		ips, err = a.AllocateRequested([]net.IP{ip})
+	} else {
+		ips, err = a.AllocateNext()
+	}
+	if err != nil {
+		var exhausted *allocator.SubnetExhaustedError
+		if errors.As(err, &exhausted) {
+			return nil, fmt.Errorf("no available IP")
+		}
+		return nil, err
+	}
+
+	var result []string
+	for _, ip := range ips {
+		family := corev1.IPv4Protocol
+		if ip.To4() == nil {
+			family = corev1.IPv6Protocol
+		}
+		if len(families) == 0 || containsIPFamily(families, family) {
+			result = append(result, ip.String())
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no IP of families %v", families)
+	}
+	return result, nil
+}
+
+// poolContainsIPs checks if every IP belongs to a pool CIDR
+func poolContainsIPs(pool *networkv1.LoadBalancerIPPool, ips []string) bool {
+	for _, ipStr := range ips {
+		ip := net.ParseIP(ipStr)
+		found := false
+		for _, cidr := range pool.CIDRs() {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err == nil && ip != nil && ipNet.Contains(ip) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// containsIPFamily checks if a list of IP families contains a family
+func containsIPFamily(families []corev1.IPFamily, family corev1.IPFamily) bool {
+	for _, f := range families {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
+// equalStrings checks if two string slices have the same elements in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
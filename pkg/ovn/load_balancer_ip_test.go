@@ -0,0 +1,85 @@
+package ovn
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+)
+
+func TestAllocateFromPool(t *testing.T) {
+	pool := &networkv1.LoadBalancerIPPool{
+		Spec: networkv1.LoadBalancerIPPoolSpec{
+			CIDR:       "192.168.100.0/29,fd00:100::/125",
+			ExcludeIPs: []string{"192.168.100.1"},
+		},
+	}
+	ipv4 := []corev1.IPFamily{corev1.IPv4Protocol}
+
+	tests := []struct {
+		name      string
+		used      map[string]bool
+		requested string
+		families  []corev1.IPFamily
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:     "first free IPv4",
+			families: ipv4,
+			want:     []string{"192.168.100.2"},
+		},
+		{
+			name:     "skips used IPs",
+			used:     map[string]bool{"192.168.100.2": true, "10.0.0.1": true},
+			families: ipv4,
+			want:     []string{"192.168.100.3"},
+		},
+		{
+			name: "dual-stack pool without families",
+			want: []string{"192.168.100.2", "fd00:100::1"},
+		},
+		{
+			name:      "requested IP",
+			requested: "192.168.100.5",
+			families:  ipv4,
+			want:      []string{"192.168.100.5"},
+		},
+		{
+			name:      "requested IP in use",
+			used:      map[string]bool{"192.168.100.5": true},
+			requested: "192.168.100.5",
+			families:  ipv4,
+			wantErr:   true,
+		},
+		{
+			name:      "requested IP outside the pool",
+			requested: "10.0.0.5",
+			families:  ipv4,
+			wantErr:   true,
+		},
+		{
+			name: "exhausted",
+			used: map[string]bool{
+				"192.168.100.2": true, "192.168.100.3": true, "192.168.100.4": true,
+				"192.168.100.5": true, "192.168.100.6": true,
+			},
+			families: ipv4,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateFromPool(pool, tt.used, tt.requested, tt.families)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("allocateFromPool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateFromPool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Package ovn provides the node IP template variables of NodePort Services.
+//
+// A NodePort is reachable on every node IP. Instead of one VIP per node IP,
+// the NodePort Load Balancer holds a single template VIP per port
+// ("^NODEIP_IPv4:<nodePort>"), and the Chassis_Template_Var row of every
+// node's chassis maps NODEIP_IPv4 / NODEIP_IPv6 to the node's own IP.
+//
+// The NodeTemplateReconciler keeps these variables up to date. Adding or
+// removing a node only writes that node's row; no Load Balancer changes.
+//
+// Reference: OVN-Kubernetes pkg/ovn/controller/services/node_tracker.go
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+const (
+	// NodeTemplateControllerName is the name of the node template controller
+	NodeTemplateControllerName = "node-template-controller"
+
+	// NodeIPv4TemplateVar holds the IPv4 InternalIP of a node
+	NodeIPv4TemplateVar = "NODEIP_IPv4"
+
+	// NodeIPv6TemplateVar holds the IPv6 InternalIP of a node
+	NodeIPv6TemplateVar = "NODEIP_IPv6"
+)
+
+// NodeTemplateReconciler writes the node IP template variables of every
+// node to its chassis.
+type NodeTemplateReconciler struct {
+	client client.Client
+	tvOps  *ovndb.ChassisTemplateVarOps
+
+	// nodeChassis remembers the chassis of each node, so the variables of a
+	// deleted node can be removed after its chassis has gone from the SB
+	nodeChassis   map[string]string
+	nodeChassisMu sync.Mutex
+}
+
+// NewNodeTemplateReconciler creates a new NodeTemplateReconciler.
+func NewNodeTemplateReconciler(c client.Client, ovnClient *ovndb.Client) *NodeTemplateReconciler {
+	return &NodeTemplateReconciler{
+		client:      c,
+		tvOps:       ovndb.NewChassisTemplateVarOps(ovnClient),
+		nodeChassis: make(map[string]string),
+	}
+}
+
+// Reconcile sets the node IP template variables of a node, or deletes them
+// when the node is gone.
+func (r *NodeTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := klog.FromContext(ctx).WithValues("node", req.Name)
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, req.NamespacedName, node); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		chassis := r.forgetChassis(ctx, req.Name)
+		log.V(4).Info("Deleting node IP template variables", "chassis", chassis)
+		if err := r.tvOps.DeleteTemplateVariables(ctx, chassis, NodeIPv4TemplateVar, NodeIPv6TemplateVar); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete node IP template variables: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	chassis := r.chassisFor(ctx, node.Name)
+	vars := nodeTemplateVars(node)
+
+	var stale []string
+	for _, name := range []string{NodeIPv4TemplateVar, NodeIPv6TemplateVar} {
+		if _, ok := vars[name]; !ok {
+			stale = append(stale, name)
+		}
+	}
+
+	log.V(4).Info("Setting node IP template variables", "chassis", chassis, "variables", vars)
+	if err := r.tvOps.SetTemplateVariables(ctx, chassis, vars); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to set node IP template variables: %w", err)
+	}
+	if err := r.tvOps.DeleteTemplateVariables(ctx, chassis, stale...); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to delete node IP template variables: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// chassisFor returns the chassis of a node and remembers it.
+func (r *NodeTemplateReconciler) chassisFor(ctx context.Context, nodeName string) string {
+	chassis := lookupNodeChassis(ctx, r.tvOps, nodeName)
+
+	r.nodeChassisMu.Lock()
+	defer r.nodeChassisMu.Unlock()
+	r.nodeChassis[nodeName] = chassis
+	return chassis
+}
+
+// forgetChassis returns the remembered chassis of a deleted node, falling
+// back to a lookup if the node was deleted before this process saw it.
+func (r *NodeTemplateReconciler) forgetChassis(ctx context.Context, nodeName string) string {
+	r.nodeChassisMu.Lock()
+	chassis, ok := r.nodeChassis[nodeName]
+	delete(r.nodeChassis, nodeName)
+	r.nodeChassisMu.Unlock()
+
+	if ok {
+		return chassis
+	}
+	return lookupNodeChassis(ctx, r.tvOps, nodeName)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Node{}, builder.WithPredicates(nodeAddressesChanged)).
+		Named(NodeTemplateControllerName).
+		Complete(r)
+}
+
+// nodeTemplateVars returns the node IP template variables of a node: the
+// first InternalIP of each address family.
+func nodeTemplateVars(node *corev1.Node) map[string]string {
+	vars := map[string]string{}
+	for _, addr := range node.Status.Addresses {
+		if addr.Type != corev1.NodeInternalIP {
+			continue
+		}
+		ip := net.ParseIP(addr.Address)
+		if ip == nil {
+			continue
+		}
+
+		name := NodeIPv4TemplateVar
+		if ip.To4() == nil {
+			name = NodeIPv6TemplateVar
+		}
+		if _, ok := vars[name]; !ok {
+			vars[name] = addr.Address
+		}
+	}
+	return vars
+}
+
+// nodeIPTemplateVar returns the node IP template variable of an address
+// family ("ipv4" or "ipv6").
+func nodeIPTemplateVar(family string) string {
+	if family == "ipv6" {
+		return NodeIPv6TemplateVar
+	}
+	return NodeIPv4TemplateVar
+}
+
+// buildTemplateVIP builds a VIP whose address is a template variable.
+// Variables are expanded as text, so IPv6 VIPs keep their brackets.
+func buildTemplateVIP(variable, family string, port int) string {
+	ref := ovndb.BuildTemplateReference(variable)
+	if family == "ipv6" {
+		return fmt.Sprintf("[%s]:%d", ref, port)
+	}
+	return fmt.Sprintf("%s:%d", ref, port)
+}
+
+// lookupNodeChassis returns the OVN chassis name of a node. The chassis is
+// looked up by hostname in the Southbound database; without a match, the
+// node name is assumed to be the chassis name (system-id).
+func lookupNodeChassis(ctx context.Context, tvOps *ovndb.ChassisTemplateVarOps, nodeName string) string {
+	chassis, err := tvOps.LookupChassisName(ctx, nodeName)
+	if err != nil {
+		klog.V(4).Infof("No chassis found for node %s, using the node name: %v", nodeName, err)
+		return nodeName
+	}
+	return chassis
+}
+
+// nodeAddressesChanged filters Node updates down to InternalIP changes.
+var nodeAddressesChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return !sets.New(nodeInternalIPs(oldNode)...).Equal(sets.New(nodeInternalIPs(newNode)...))
+	},
+}
+
+// nodeInternalIPs returns the InternalIPs of a node.
+func nodeInternalIPs(node *corev1.Node) []string {
+	var ips []string
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			ips = append(ips, addr.Address)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,45 @@
+package ovn
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeTemplateVars(t *testing.T) {
+	node := func(addrs ...corev1.NodeAddress) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{Addresses: addrs}}
+	}
+	internal := func(ip string) corev1.NodeAddress {
+		return corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: ip}
+	}
+
+	tests := []struct {
+		name     string
+		node     *corev1.Node
+		expected map[string]string
+	}{
+		{"no address", node(), map[string]string{}},
+		{"ipv4", node(internal("192.168.1.10")), map[string]string{NodeIPv4TemplateVar: "192.168.1.10"}},
+		{"first address of a family", node(internal("192.168.1.10"), internal("192.168.1.11")),
+			map[string]string{NodeIPv4TemplateVar: "192.168.1.10"}},
+		{"dual stack", node(internal("fd00::10"), internal("192.168.1.10")),
+			map[string]string{NodeIPv4TemplateVar: "192.168.1.10", NodeIPv6TemplateVar: "fd00::10"}},
+		{"external address ignored", node(corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.5"}),
+			map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodeTemplateVars(tt.node)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("nodeTemplateVars() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	if vip := buildTemplateVIP(NodeIPv6TemplateVar, "ipv6", 30080); vip != "[^NODEIP_IPv6]:30080" {
+		t.Errorf("buildTemplateVIP() = %s, want [^NODEIP_IPv6]:30080", vip)
+	}
+}
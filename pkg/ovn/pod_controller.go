@@ -17,12 +17,15 @@ package ovn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -36,6 +39,7 @@ import (
 	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/config"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/events"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
 )
@@ -49,6 +53,13 @@ const (
 
 	// DefaultSubnetAnnotation is the annotation key for specifying a subnet
 	DefaultSubnetAnnotation = "zstack.io/subnet"
+
+	// allocatorSyncRequeueDelay is the requeue delay while allocators are rebuilt
+	allocatorSyncRequeueDelay = 2 * time.Second
+
+	// maxGeneratedMACCandidates bounds the MACs tried for a Pod whose
+	// generated MAC is already used in its subnet
+	maxGeneratedMACCandidates = 16
 )
 
 // PodReconciler reconciles Pod objects for network configuration.
@@ -77,12 +88,15 @@ type PodReconciler struct {
 	// lspOps provides Logical Switch Port operations
 	lspOps *ovndb.LogicalSwitchPortOps
 
+	// dhcpOps provides DHCP Options operations
+	dhcpOps *ovndb.DHCPOptionsOps
+
 	// subnetReconciler provides access to subnet allocators
 	subnetReconciler *SubnetReconciler
 
 	// podAllocations tracks IP allocations per Pod
-	// Key: namespace/name, Value: allocated IP
-	podAllocations map[string]string
+	// Key: namespace/name, Value: allocated IPs, one per IP family
+	podAllocations map[string][]string
 	allocationsMu  sync.RWMutex
 }
 
@@ -113,13 +127,17 @@ func NewPodReconciler(
 		config:           cfg,
 		ovnClient:        ovnClient,
 		lspOps:           ovndb.NewLogicalSwitchPortOps(ovnClient),
+		dhcpOps:          ovndb.NewDHCPOptionsOps(ovnClient),
 		subnetReconciler: subnetReconciler,
-		podAllocations:   make(map[string]string),
+		podAllocations:   make(map[string][]string),
 	}
 }
 
 // Reconcile handles the reconciliation of a Pod resource.
 //
+// Requests are requeued until the subnet allocators have been rebuilt
+// from cluster state (see AllocatorSyncer).
+//
 // The reconciliation logic:
 // 1. If Pod is being deleted, clean up OVN resources
 // 2. If Pod already has network annotation, skip (already configured)
@@ -131,6 +149,12 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	log := klog.FromContext(ctx).WithValues("pod", req.NamespacedName)
 	log.V(4).Info("Reconciling Pod")
 
+	// Hold off until the allocators reflect the IPs already in use
+	if !r.subnetReconciler.AllocatorsSynced() {
+		log.V(4).Info("IP allocators not synced yet, requeuing")
+		return ctrl.Result{RequeueAfter: allocatorSyncRequeueDelay}, nil
+	}
+
 	// Get the Pod
 	pod := &corev1.Pod{}
 	if err := r.client.Get(ctx, req.NamespacedName, pod); err != nil {
@@ -168,7 +192,11 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 	// Check if Pod already has network annotation
 	if util.HasPodAnnotation(pod) {
-		log.V(4).Info("Pod already has network annotation, skipping")
+		log.V(4).Info("Pod already has network annotation, syncing IP object")
+		if err := r.syncIPObject(ctx, pod); err != nil {
+			log.Error(err, "Failed to sync IP object")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -215,8 +243,8 @@ func (r *PodReconciler) shouldManagePod(pod *corev1.Pod) bool {
 //
 // Steps:
 // 1. Find the appropriate subnet
-// 2. Allocate IP address
-// 3. Generate MAC address
+// 2. Allocate IP address (requested with zstack.io/ip-address, or the next free one)
+// 3. Generate MAC address (unless requested with zstack.io/mac-address)
 // 4. Create OVN Logical Switch Port
 // 5. Set Pod annotation
 func (r *PodReconciler) configurePodNetwork(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
@@ -240,19 +268,30 @@ func (r *PodReconciler) configurePodNetwork(ctx context.Context, pod *corev1.Pod
 		return ctrl.Result{Requeue: true}, fmt.Errorf("IP allocator not ready for subnet %s", subnet.Name)
 	}
 
-	// Allocate IP address
-	ip, err := r.allocateIP(ctx, pod, alloc)
+	// Allocate IP addresses, one per IP family of the subnet
+	ips, err := r.allocateIP(ctx, pod, subnet.Name, alloc)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to allocate IP: %w", err)
 	}
 
-	// Calculate prefix length from subnet CIDR
-	_, ipNet, _ := net.ParseCIDR(subnet.Spec.CIDR)
-	prefixLen, _ := ipNet.Mask.Size()
-	ipWithPrefix := fmt.Sprintf("%s/%d", ip.String(), prefixLen)
+	// Add prefix lengths from the subnet CIDRs
+	ipsWithPrefix := buildIPsWithPrefix(ips, subnet.CIDRs())
+	ipStrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		ipStrs = append(ipStrs, ip.String())
+	}
+
+	podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
 
-	// Generate MAC address from IP
-	mac := util.GenerateMAC(ip)
+	// Use the requested MAC address, or generate one from the first IP
+	mac, err := r.podMAC(ctx, pod, subnet.Name, ips[0])
+	if err != nil {
+		for _, ip := range ips {
+			_ = alloc.Release(ip)
+		}
+		r.cleanupAllocation(podKey)
+		return ctrl.Result{}, err
+	}
 
 	// Get logical switch name
 	logicalSwitch := subnet.GetLogicalSwitchName()
@@ -260,29 +299,60 @@ func (r *PodReconciler) configurePodNetwork(ctx context.Context, pod *corev1.Pod
 	// Build port name
 	portName := ovndb.BuildPortName(pod.Namespace, pod.Name)
 
+	// Record the allocation as IP objects, one per address
+	for i, ip := range ips {
+		if err := ensureIPObject(ctx, r.client, newPodIPObject(pod, subnet.Name, ip.String(), mac, portName)); err != nil {
+			// Keep an IP recorded for another owner marked as allocated
+			for j, allocated := range ips {
+				if j == i && errors.Is(err, errIPInUse) {
+					continue
+				}
+				if j < i {
+					_ = deleteIPObject(ctx, r.client, subnet.Name, allocated.String())
+				}
+				_ = alloc.Release(allocated)
+			}
+			r.cleanupAllocation(podKey)
+			return ctrl.Result{}, fmt.Errorf("failed to record IP: %w", err)
+		}
+	}
+
 	log.V(4).Info("Creating OVN Logical Switch Port",
 		"port", portName,
 		"switch", logicalSwitch,
-		"ip", ipWithPrefix,
+		"ips", ipsWithPrefix,
 		"mac", mac)
 
 	// Create OVN Logical Switch Port
-	if err := r.createLogicalSwitchPort(ctx, pod, logicalSwitch, portName, mac, ip.String()); err != nil {
-		// Release IP on failure
-		_ = alloc.Release(ip)
-		r.cleanupAllocation(fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+	if err := r.createLogicalSwitchPort(ctx, pod, logicalSwitch, portName, mac, ipStrs); err != nil {
+		// Release IPs on failure
+		for _, ip := range ips {
+			_ = alloc.Release(ip)
+			_ = deleteIPObject(ctx, r.client, subnet.Name, ip.String())
+		}
+		r.cleanupAllocation(podKey)
 		return ctrl.Result{}, fmt.Errorf("failed to create OVN LSP: %w", err)
 	}
 
+	// Point the port at the Subnet's DHCP options
+	if subnet.Spec.EnableDHCP {
+		if err := r.setPortDHCPOptions(ctx, subnet.Name, portName, ipStrs); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Create Pod annotation
+	gateways := gatewaysForIPs(ips, subnet.CIDRs(), subnet.Gateways())
 	annotation := util.NewPodAnnotation(
-		ipWithPrefix,
+		ipsWithPrefix[0],
 		mac,
-		subnet.Spec.Gateway,
+		gateways[0],
 		subnet.Name,
 		logicalSwitch,
 		portName,
 	)
+	annotation.IPAddresses = ipsWithPrefix
+	annotation.GatewayIPs = gateways
 
 	// Set annotation on Pod
 	if err := util.SetPodAnnotation(pod, annotation); err != nil {
@@ -295,14 +365,59 @@ func (r *PodReconciler) configurePodNetwork(ctx context.Context, pod *corev1.Pod
 	}
 
 	log.Info("Pod network configured",
-		"ip", ipWithPrefix,
+		"ips", ipsWithPrefix,
 		"mac", mac,
-		"gateway", subnet.Spec.Gateway,
+		"gateways", gateways,
 		"logicalSwitch", logicalSwitch)
 
 	return ctrl.Result{}, nil
 }
 
+// buildIPsWithPrefix adds the prefix length of the matching CIDR to each IP.
+// IPs not contained in any CIDR are returned as host addresses (/32 or /128).
+func buildIPsWithPrefix(ips []net.IP, cidrs []string) []string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		prefixLen := 128
+		if ip.To4() != nil {
+			prefixLen = 32
+		}
+		for _, cidr := range cidrs {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(ip) {
+				prefixLen, _ = ipNet.Mask.Size()
+				break
+			}
+		}
+		result = append(result, fmt.Sprintf("%s/%d", ip.String(), prefixLen))
+	}
+	return result
+}
+
+// gatewaysForIPs returns the gateway of the CIDR containing each IP, as
+// gateways[i] is the gateway of cidrs[i]. IPs in no CIDR get the gateway of
+// the first CIDR of their family.
+func gatewaysForIPs(ips []net.IP, cidrs, gateways []string) []string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		gateway := ""
+		for i, cidr := range cidrs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil || i >= len(gateways) || (ipNet.IP.To4() == nil) != (ip.To4() == nil) {
+				continue
+			}
+			if gateway == "" {
+				gateway = gateways[i]
+			}
+			if ipNet.Contains(ip) {
+				gateway = gateways[i]
+				break
+			}
+		}
+		result = append(result, gateway)
+	}
+	return result
+}
+
 // findSubnetForPod finds the appropriate subnet for a Pod.
 //
 // Subnet selection order:
@@ -371,31 +486,312 @@ func (r *PodReconciler) findSubnetForPod(ctx context.Context, pod *corev1.Pod) (
 	return nil, nil
 }
 
-// allocateIP allocates an IP address for a Pod.
-func (r *PodReconciler) allocateIP(ctx context.Context, pod *corev1.Pod, alloc *allocator.SubnetAllocator) (net.IP, error) {
+// allocateIP allocates IP addresses for a Pod, one per IP family.
+//
+// The addresses are chosen in this order:
+// 1. IPs reserved for a StatefulSet Pod recreated with the same name
+// 2. IPs requested with the zstack.io/ip-address annotation
+// 3. The first free IPs of the zstack.io/ip-pool annotation
+// 4. The next available IPs of the subnet
+//
+// Families without a requested or pool IP get the next available address.
+// A requested IP that is already in use is reported as an IPConflict event.
+func (r *PodReconciler) allocateIP(ctx context.Context, pod *corev1.Pod, subnetName string, alloc *allocator.DualStackAllocator) ([]net.IP, error) {
 	podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
 
+	requested, err := util.GetPodStaticIPs(pod)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := util.GetPodIPPool(pod)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := r.reservedIPs(ctx, pod, subnetName)
+	if err != nil {
+		return nil, err
+	}
+
 	r.allocationsMu.Lock()
 	defer r.allocationsMu.Unlock()
 
-	// Check if we already allocated an IP for this Pod
-	if ipStr, ok := r.podAllocations[podKey]; ok {
-		ip := net.ParseIP(ipStr)
-		if ip != nil {
-			return ip, nil
+	// Check if we already allocated IPs for this Pod in every family
+	if ipStrs, ok := r.podAllocations[podKey]; ok {
+		ips := make([]net.IP, 0, len(ipStrs))
+		for _, ipStr := range ipStrs {
+			if ip := net.ParseIP(ipStr); ip != nil && alloc.Contains(ip) {
+				ips = append(ips, ip)
+			}
+		}
+		if len(ips) == len(ipStrs) && len(ips) == len(alloc.Families()) && containsIPs(ips, requested) {
+			return ips, nil
+		}
+
+		// The requested IPs changed before the Pod was configured
+		if len(requested) > 0 {
+			for _, ip := range ips {
+				_ = alloc.Release(ip)
+			}
+			delete(r.podAllocations, podKey)
+		} else {
+			// A family is missing, because the subnet gained a family or
+			// only some IPs were restored: keep the held IPs and allocate
+			// the missing ones. The Pod's existing switch port still lists
+			// only the held IPs; createLogicalSwitchPort rewrites it.
+			ips, err := alloc.AllocateMissing(ips)
+			if err != nil {
+				return nil, err
+			}
+			r.podAllocations[podKey] = ipStrings(ips)
+			return ips, nil
 		}
 	}
 
-	// Allocate new IP
-	ip, err := alloc.AllocateNext()
+	// Allocate new IPs
+	var ips []net.IP
+	switch {
+	case len(reserved) == len(alloc.Families()) && containsIPs(reserved, requested):
+		// Reserved IPs stay allocated while the Pod is away
+		ips = reserved
+	case len(requested) > 0:
+		ips, err = alloc.AllocateRequested(requested)
+		var inUse *allocator.IPAlreadyAllocatedError
+		if errors.As(err, &inUse) {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, events.ReasonIPConflict,
+				"Requested IP %s is already in use in subnet %s", inUse.IP, subnetName)
+		}
+	case len(pool) > 0:
+		var poolIPs []net.IP
+		poolIPs, err = selectPoolIPs(pool, alloc)
+		if err == nil {
+			ips, err = alloc.AllocateRequested(poolIPs)
+		}
+	default:
+		ips, err = alloc.AllocateNext()
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// Track allocation
-	r.podAllocations[podKey] = ip.String()
+	r.podAllocations[podKey] = ipStrings(ips)
+
+	return ips, nil
+}
+
+// reservedIPs returns the IPs of a subnet reserved for a Pod recreated with
+// the same name (see reserveIPObject), in family order.
+func (r *PodReconciler) reservedIPs(ctx context.Context, pod *corev1.Pod, subnetName string) ([]net.IP, error) {
+	if !isStatefulSetPod(pod) {
+		return nil, nil
+	}
+
+	ipList := &networkv1.IPList{}
+	if err := r.client.List(ctx, ipList, client.MatchingLabels{networkv1.IPSubnetLabel: subnetName}); err != nil {
+		return nil, fmt.Errorf("failed to list IPs of subnet %s: %w", subnetName, err)
+	}
+
+	var ips []net.IP
+	for i := range ipList.Items {
+		ipObj := &ipList.Items[i]
+		if _, ok := ipObj.ReservedUntil(); !ok {
+			continue
+		}
+		owner := ipObj.Spec.Owner
+		if owner.Kind != networkv1.IPOwnerKindPod || owner.Namespace != pod.Namespace || owner.Name != pod.Name {
+			continue
+		}
+		if ip := net.ParseIP(ipObj.Spec.IPAddress); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	// Order the IPs like the subnet families
+	ordered := make([]net.IP, 0, len(ips))
+	if alloc := r.subnetReconciler.GetAllocator(subnetName); alloc != nil {
+		for _, family := range alloc.Families() {
+			for _, ip := range ips {
+				if family.Contains(ip) {
+					ordered = append(ordered, ip)
+					break
+				}
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// selectPoolIPs picks the first free IP of the pool in every IP family the
+// pool covers.
+//
+// Returns an error if the pool has no IP in the subnet, or if every pool IP
+// of a family is already allocated.
+func selectPoolIPs(pool []net.IP, alloc *allocator.DualStackAllocator) ([]net.IP, error) {
+	var selected []net.IP
+	for _, family := range alloc.Families() {
+		covered, found := false, false
+		for _, ip := range pool {
+			if !family.Contains(ip) {
+				continue
+			}
+			covered = true
+			if !family.IsAllocated(ip) {
+				selected = append(selected, ip)
+				found = true
+				break
+			}
+		}
+		if covered && !found {
+			return nil, fmt.Errorf("IP pool exhausted in subnet %s", family)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("IP pool has no address in subnet %s", alloc.String())
+	}
+	return selected, nil
+}
+
+// isStatefulSetPod returns true if the Pod is controlled by a StatefulSet.
+// StatefulSet Pods keep their name when recreated, so their IPs are sticky.
+func isStatefulSetPod(pod *corev1.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "StatefulSet"
+}
+
+// stickyIPGracePeriod returns how long the IPs of a deleted StatefulSet Pod
+// stay reserved
+func (r *PodReconciler) stickyIPGracePeriod() time.Duration {
+	if r.config == nil {
+		return 0
+	}
+	return r.config.Network.StickyIPGracePeriod
+}
+
+// containsIPs returns true if every IP in want is in ips
+func containsIPs(ips, want []net.IP) bool {
+	for _, w := range want {
+		found := false
+		for _, ip := range ips {
+			if ip.Equal(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ipStrings returns the string form of IPs
+func ipStrings(ips []net.IP) []string {
+	strs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		strs = append(strs, ip.String())
+	}
+	return strs
+}
+
+// podMAC returns the MAC address for a Pod.
+//
+// The MAC requested with the zstack.io/mac-address annotation is used if set,
+// unless another owner in the subnet already uses it (reported as an
+// IPConflict event). Otherwise the MAC is generated from the first IP,
+// skipping MACs already used in the subnet; requested MACs never use the
+// generated range.
+func (r *PodReconciler) podMAC(ctx context.Context, pod *corev1.Pod, subnetName string, ip net.IP) (string, error) {
+	mac, err := util.GetPodStaticMAC(pod)
+	if err != nil {
+		return "", err
+	}
+
+	// MACs must be unique on the logical switch; the IP objects of the
+	// subnet record the MAC of every allocation
+	ipList := &networkv1.IPList{}
+	if err := r.client.List(ctx, ipList, client.MatchingLabels{networkv1.IPSubnetLabel: subnetName}); err != nil {
+		return "", fmt.Errorf("failed to list IPs of subnet %s: %w", subnetName, err)
+	}
+	owner := networkv1.IPOwner{Kind: networkv1.IPOwnerKindPod, Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID}
+	used := make(map[string]*networkv1.IP)
+	for i := range ipList.Items {
+		existing := &ipList.Items[i]
+		if existing.Spec.MACAddress != "" && !sameIPOwner(existing.Spec.Owner, owner) {
+			used[existing.Spec.MACAddress] = existing
+		}
+	}
+
+	if mac == "" {
+		return generatePodMAC(ip, used, subnetName)
+	}
+	if existing, ok := used[mac]; ok {
+		r.recorder.Eventf(pod, corev1.EventTypeWarning, events.ReasonIPConflict,
+			"Requested MAC %s is already used by %s %s in subnet %s",
+			mac, existing.Spec.Owner.Kind, existing.OwnerKey(), subnetName)
+		return "", fmt.Errorf("MAC address %s is already in use in subnet %s", mac, subnetName)
+	}
+
+	return mac, nil
+}
+
+// generatePodMAC returns the first MAC generated from ip that is not in
+// used, trying up to maxGeneratedMACCandidates candidates.
+func generatePodMAC(ip net.IP, used map[string]*networkv1.IP, subnetName string) (string, error) {
+	for n := 0; n < maxGeneratedMACCandidates; n++ {
+		mac := util.GenerateAlternateMAC(ip, n)
+		if _, ok := used[mac]; !ok {
+			return mac, nil
+		}
+	}
+	return "", fmt.Errorf("no free MAC address generated from %s in subnet %s", ip, subnetName)
+}
+
+// syncIPObject makes sure an annotated Pod has an up-to-date IP object.
+// This records allocations made before IP objects existed and keeps the
+// node name current once the Pod is scheduled.
+func (r *PodReconciler) syncIPObject(ctx context.Context, pod *corev1.Pod) error {
+	annotation, err := util.GetPodAnnotation(pod)
+	if err != nil || annotation == nil || annotation.Subnet == "" || annotation.GetIP() == "" {
+		return nil
+	}
+
+	for _, ip := range annotation.GetIPs() {
+		desired := newPodIPObject(pod, annotation.Subnet, ip, annotation.MACAddress, annotation.LogicalSwitchPort)
+		if err := ensureIPObject(ctx, r.client, desired); err != nil {
+			if errors.Is(err, errIPInUse) {
+				r.recorder.Event(pod, corev1.EventTypeWarning, events.ReasonIPConflict, err.Error())
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
 
-	return ip, nil
+// restoreAllocation records an IP found in use while rebuilding allocators,
+// so a Pod whose annotation was never written gets the same IPs back.
+func (r *PodReconciler) restoreAllocation(podKey, ip string) {
+	r.allocationsMu.Lock()
+	defer r.allocationsMu.Unlock()
+	for _, existing := range r.podAllocations[podKey] {
+		if existing == ip {
+			return
+		}
+	}
+	r.podAllocations[podKey] = append(r.podAllocations[podKey], ip)
+}
+
+// releaseAllocation removes the tracked IP allocation of a Pod if it
+// still refers to ip.
+func (r *PodReconciler) releaseAllocation(podKey, ip string) {
+	r.allocationsMu.Lock()
+	defer r.allocationsMu.Unlock()
+	for _, existing := range r.podAllocations[podKey] {
+		if existing == ip {
+			delete(r.podAllocations, podKey)
+			return
+		}
+	}
 }
 
 // cleanupAllocation removes tracked IP allocation for a Pod.
@@ -406,10 +802,14 @@ func (r *PodReconciler) cleanupAllocation(podKey string) {
 }
 
 // createLogicalSwitchPort creates an OVN Logical Switch Port for a Pod.
+// An existing port whose addresses differ from mac and ips, e.g. one created
+// before AllocateMissing added an IP family, gets its addresses and port
+// security rewritten.
 func (r *PodReconciler) createLogicalSwitchPort(
 	ctx context.Context,
 	pod *corev1.Pod,
-	switchName, portName, mac, ip string,
+	switchName, portName, mac string,
+	ips []string,
 ) error {
 	// Check if port already exists
 	existingPort, err := r.lspOps.GetLogicalSwitchPort(ctx, portName)
@@ -418,7 +818,17 @@ func (r *PodReconciler) createLogicalSwitchPort(
 	}
 
 	if existingPort != nil {
-		klog.V(4).Infof("Logical Switch Port %s already exists", portName)
+		if lspAddressesMatch(existingPort, mac, ips) {
+			klog.V(4).Infof("Logical Switch Port %s already exists", portName)
+			return nil
+		}
+		// The port survived from an earlier allocation (for example an IP
+		// re-allocated after a restart); rewrite addresses and port security
+		// so OVN does not keep forwarding for the stale MAC and IPs.
+		klog.Infof("Updating addresses of Logical Switch Port %s to %s %v", portName, mac, ips)
+		if err := r.lspOps.SetAddresses(ctx, portName, mac, ips); err != nil {
+			return fmt.Errorf("failed to update LSP addresses: %w", err)
+		}
 		return nil
 	}
 
@@ -437,7 +847,7 @@ func (r *PodReconciler) createLogicalSwitchPort(
 		switchName,
 		portName,
 		mac,
-		[]string{ip},
+		ips,
 		externalIDs,
 	)
 	if err != nil {
@@ -447,11 +857,42 @@ func (r *PodReconciler) createLogicalSwitchPort(
 	return nil
 }
 
+// lspAddressesMatch reports whether a Logical Switch Port already carries the
+// given MAC and IPs in its addresses and, for normal ports, its port security.
+func lspAddressesMatch(lsp *ovndb.LogicalSwitchPort, mac string, ips []string) bool {
+	if len(lsp.Addresses) != 1 || !addressEntryMatches(lsp.Addresses[0], mac, ips) {
+		return false
+	}
+	if lsp.Type == ovndb.PortTypeNormal && mac != "" && len(ips) > 0 {
+		return len(lsp.PortSecurity) == 1 && addressEntryMatches(lsp.PortSecurity[0], mac, ips)
+	}
+	return true
+}
+
+// addressEntryMatches compares one "MAC IP1 IP2 ..." entry with the given MAC
+// and IPs, ignoring IP order and prefix lengths.
+func addressEntryMatches(entry, mac string, ips []string) bool {
+	entryMAC, entryIPs := ovndb.ParseAddresses(entry)
+	if !strings.EqualFold(entryMAC, mac) || len(entryIPs) != len(ips) {
+		return false
+	}
+	want := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		want[strings.Split(ip, "/")[0]] = true
+	}
+	for _, ip := range entryIPs {
+		if !want[ip] {
+			return false
+		}
+	}
+	return true
+}
+
 // handleDeletion handles Pod deletion.
 //
 // Steps:
 // 1. Delete OVN Logical Switch Port
-// 2. Release IP address
+// 2. Release IP address (StatefulSet Pods: reserve it for the grace period)
 // 3. Remove finalizer
 func (r *PodReconciler) handleDeletion(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
 	log := klog.FromContext(ctx).WithValues("pod", fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
@@ -484,12 +925,27 @@ func (r *PodReconciler) handleDeletion(ctx context.Context, pod *corev1.Pod) (ct
 		}
 	}
 
-	// Release IP address
+	// Release IP addresses, or keep them reserved for a recreated
+	// StatefulSet Pod
 	if annotation != nil && annotation.Subnet != "" {
+		gracePeriod := r.stickyIPGracePeriod()
+		sticky := gracePeriod > 0 && isStatefulSetPod(pod)
+		owner := networkv1.IPOwner{Kind: networkv1.IPOwnerKindPod, Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID}
 		alloc := r.subnetReconciler.GetAllocator(annotation.Subnet)
-		if alloc != nil {
-			ipStr := annotation.GetIP()
-			if ipStr != "" {
+		for _, ipStr := range annotation.GetIPs() {
+			if sticky {
+				reserved, err := reserveIPObject(ctx, r.client, annotation.Subnet, ipStr, owner, time.Now().Add(gracePeriod))
+				if err != nil {
+					log.Error(err, "Failed to reserve IP object")
+					return ctrl.Result{}, err
+				}
+				if reserved {
+					log.V(4).Info("Reserved IP for recreated Pod", "ip", ipStr, "gracePeriod", gracePeriod)
+					continue
+				}
+			}
+
+			if alloc != nil {
 				ip := net.ParseIP(ipStr)
 				if ip != nil {
 					if err := alloc.Release(ip); err != nil {
@@ -499,6 +955,12 @@ func (r *PodReconciler) handleDeletion(ctx context.Context, pod *corev1.Pod) (ct
 					}
 				}
 			}
+
+			// Delete the IP object recording the allocation
+			if err := deleteIPObject(ctx, r.client, annotation.Subnet, ipStr); err != nil {
+				log.Error(err, "Failed to delete IP object")
+				return ctrl.Result{}, err
+			}
 		}
 	}
 
@@ -570,6 +1032,11 @@ func (r *PodReconciler) podEventFilter() predicate.Predicate {
 				return true
 			}
 
+			// Process if the Pod was scheduled (IP object node name)
+			if oldPod.Spec.NodeName != newPod.Spec.NodeName {
+				return true
+			}
+
 			// Process if finalizer changed
 			oldHasFinalizer := controllerutil.ContainsFinalizer(oldPod, PodFinalizer)
 			newHasFinalizer := controllerutil.ContainsFinalizer(newPod, PodFinalizer)
@@ -589,54 +1056,57 @@ func (r *PodReconciler) podEventFilter() predicate.Predicate {
 	}
 }
 
-// GetPodAllocation returns the allocated IP for a Pod.
+// GetPodAllocation returns the allocated IPs for a Pod, one per IP family.
 // This is useful for testing and debugging.
-func (r *PodReconciler) GetPodAllocation(namespace, name string) string {
+func (r *PodReconciler) GetPodAllocation(namespace, name string) []string {
 	r.allocationsMu.RLock()
 	defer r.allocationsMu.RUnlock()
 	return r.podAllocations[fmt.Sprintf("%s/%s", namespace, name)]
 }
 
-// AllocateIPForPod allocates an IP for a Pod from the specified subnet.
+// AllocateIPForPod allocates IPs for a Pod from the specified subnet,
+// one per IP family of the subnet.
 // This is a public method that can be called by other components.
 //
 // Parameters:
 //   - ctx: Context for cancellation
-//   - pod: The Pod to allocate IP for
+//   - pod: The Pod to allocate IPs for
 //   - subnetName: Name of the subnet to allocate from
 //
 // Returns:
-//   - string: Allocated IP address with prefix (e.g., "10.244.1.5/24")
-//   - string: Generated MAC address
+//   - []string: Allocated IP addresses with prefix (e.g., ["10.244.1.5/24", "fd00:10:244:1::5/64"])
+//   - string: Requested or generated MAC address
 //   - error: Allocation error
-func (r *PodReconciler) AllocateIPForPod(ctx context.Context, pod *corev1.Pod, subnetName string) (string, string, error) {
+func (r *PodReconciler) AllocateIPForPod(ctx context.Context, pod *corev1.Pod, subnetName string) ([]string, string, error) {
 	// Get subnet
 	subnet := &networkv1.Subnet{}
 	if err := r.client.Get(ctx, types.NamespacedName{Name: subnetName}, subnet); err != nil {
-		return "", "", fmt.Errorf("subnet %s not found: %w", subnetName, err)
+		return nil, "", fmt.Errorf("subnet %s not found: %w", subnetName, err)
 	}
 
 	// Get allocator
 	alloc := r.subnetReconciler.GetAllocator(subnetName)
-	if alloc == nil {
-		return "", "", fmt.Errorf("IP allocator not ready for subnet %s", subnetName)
+	if alloc == nil || !r.subnetReconciler.AllocatorsSynced() {
+		return nil, "", fmt.Errorf("IP allocator not ready for subnet %s", subnetName)
 	}
 
-	// Allocate IP
-	ip, err := r.allocateIP(ctx, pod, alloc)
+	// Allocate IPs
+	ips, err := r.allocateIP(ctx, pod, subnet.Name, alloc)
 	if err != nil {
-		return "", "", err
+		return nil, "", err
 	}
 
-	// Calculate prefix
-	_, ipNet, _ := net.ParseCIDR(subnet.Spec.CIDR)
-	prefixLen, _ := ipNet.Mask.Size()
-	ipWithPrefix := fmt.Sprintf("%s/%d", ip.String(), prefixLen)
-
-	// Generate MAC
-	mac := util.GenerateMAC(ip)
+	// Use the requested MAC, or generate one from the first IP
+	mac, err := r.podMAC(ctx, pod, subnetName, ips[0])
+	if err != nil {
+		for _, ip := range ips {
+			_ = alloc.Release(ip)
+		}
+		r.cleanupAllocation(fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		return nil, "", err
+	}
 
-	return ipWithPrefix, mac, nil
+	return buildIPsWithPrefix(ips, subnet.CIDRs()), mac, nil
 }
 
 // ReleaseIPForPod releases the IP allocated for a Pod.
@@ -0,0 +1,188 @@
+// Package ovn provides tests for Pod controller helpers.
+package ovn
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	networkv1 "github.com/jiayi-1994/zstack-ovn-kubernetes/api/v1"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
+)
+
+// TestSelectPoolIPs tests picking the first free IPs of an IP pool.
+func TestSelectPoolIPs(t *testing.T) {
+	tests := []struct {
+		name      string
+		pool      []string
+		allocated []string
+		expected  []string
+		wantErr   bool
+	}{
+		{"first free", []string{"10.244.1.10", "10.244.1.11"}, nil, []string{"10.244.1.10"}, false},
+		{"skip allocated", []string{"10.244.1.10", "10.244.1.11"}, []string{"10.244.1.10"}, []string{"10.244.1.11"}, false},
+		{"both families", []string{"fd00::10", "10.244.1.10"}, nil, []string{"10.244.1.10", "fd00::10"}, false},
+		{"exhausted", []string{"10.244.1.10"}, []string{"10.244.1.10"}, nil, true},
+		{"outside subnet", []string{"10.244.2.10"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc, err := allocator.NewDualStackAllocator([]string{"10.244.1.0/24", "fd00::/64"}, nil)
+			if err != nil {
+				t.Fatalf("NewDualStackAllocator() error = %v", err)
+			}
+			for _, ip := range tt.allocated {
+				if err := alloc.Allocate(net.ParseIP(ip)); err != nil {
+					t.Fatalf("Allocate(%s) error = %v", ip, err)
+				}
+			}
+			pool := make([]net.IP, 0, len(tt.pool))
+			for _, ip := range tt.pool {
+				pool = append(pool, net.ParseIP(ip))
+			}
+
+			got, err := selectPoolIPs(pool, alloc)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("selectPoolIPs() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectPoolIPs() error = %v", err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("selectPoolIPs() = %v, want %v", got, tt.expected)
+			}
+			for i, ip := range got {
+				if ip.String() != tt.expected[i] {
+					t.Errorf("selectPoolIPs()[%d] = %s, want %s", i, ip, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGatewaysForIPs tests picking the gateway of the CIDR containing each IP.
+func TestGatewaysForIPs(t *testing.T) {
+	cidrs := []string{"10.244.1.0/24", "fd00::/64", "10.244.2.0/24"}
+	gateways := []string{"10.244.1.1", "fd00::1", "10.244.2.1"}
+
+	tests := []struct {
+		name     string
+		ips      []string
+		expected []string
+	}{
+		{"primary range", []string{"10.244.1.5"}, []string{"10.244.1.1"}},
+		{"extra range", []string{"10.244.2.5", "fd00::5"}, []string{"10.244.2.1", "fd00::1"}},
+		{"outside every range", []string{"10.244.3.5"}, []string{"10.244.1.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ips := make([]net.IP, 0, len(tt.ips))
+			for _, ip := range tt.ips {
+				ips = append(ips, net.ParseIP(ip))
+			}
+			got := gatewaysForIPs(ips, cidrs, gateways)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("gatewaysForIPs() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("gatewaysForIPs()[%d] = %s, want %s", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestGeneratePodMAC tests that generated MACs are unique across the IPv6
+// ranges of a subnet and skip MACs already used in the subnet.
+func TestGeneratePodMAC(t *testing.T) {
+	// Same host part in two IPv6 ranges of one subnet
+	mac1, err := generatePodMAC(net.ParseIP("fd00:1::5"), nil, "subnet-a")
+	if err != nil {
+		t.Fatalf("generatePodMAC() error = %v", err)
+	}
+	mac2, err := generatePodMAC(net.ParseIP("fd00:2::5"), nil, "subnet-a")
+	if err != nil {
+		t.Fatalf("generatePodMAC() error = %v", err)
+	}
+	if mac1 == mac2 {
+		t.Errorf("fd00:1::5 and fd00:2::5 both got MAC %s", mac1)
+	}
+
+	// The generated MAC is already recorded for another owner
+	used := map[string]*networkv1.IP{mac2: {}}
+	mac3, err := generatePodMAC(net.ParseIP("fd00:2::5"), used, "subnet-a")
+	if err != nil {
+		t.Fatalf("generatePodMAC() error = %v", err)
+	}
+	if mac3 == mac2 || !strings.HasPrefix(mac3, util.GeneratedMACPrefix) {
+		t.Errorf("generatePodMAC() with %s in use = %s, want another generated MAC", mac2, mac3)
+	}
+}
+
+// TestLSPAddressesMatch tests detecting a Pod port whose addresses are stale.
+func TestLSPAddressesMatch(t *testing.T) {
+	mac := "0a:58:0a:00:00:05"
+	ips := []string{"10.0.0.5", "fd00::5"}
+	current := mac + " 10.0.0.5 fd00::5"
+	stale := "0a:58:0a:00:00:09 10.0.0.9"
+
+	tests := []struct {
+		name     string
+		lsp      ovndb.LogicalSwitchPort
+		expected bool
+	}{
+		{
+			name:     "up to date",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{current}, PortSecurity: []string{current}},
+			expected: true,
+		},
+		{
+			name:     "IPs in another order",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{mac + " fd00::5 10.0.0.5"}, PortSecurity: []string{current}},
+			expected: true,
+		},
+		{
+			name:     "stale addresses",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{stale}, PortSecurity: []string{stale}},
+			expected: false,
+		},
+		{
+			name:     "missing IP",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{mac + " 10.0.0.5"}, PortSecurity: []string{current}},
+			expected: false,
+		},
+		{
+			// Created before the subnet gained IPv6 and AllocateMissing
+			// added the IPv6 address
+			name:     "port without the missing family",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{mac + " 10.0.0.5"}, PortSecurity: []string{mac + " 10.0.0.5"}},
+			expected: false,
+		},
+		{
+			name:     "stale port security",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{current}, PortSecurity: []string{stale}},
+			expected: false,
+		},
+		{
+			name:     "dynamic addresses",
+			lsp:      ovndb.LogicalSwitchPort{Addresses: []string{"dynamic"}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lspAddressesMatch(&tt.lsp, mac, ips); got != tt.expected {
+				t.Errorf("lspAddressesMatch() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
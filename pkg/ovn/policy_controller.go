@@ -4,30 +4,43 @@
 // NetworkPolicy resources into OVN ACL (Access Control List) rules.
 //
 // NetworkPolicy to OVN ACL Mapping:
-// - Ingress rules map to ACLs with direction "to-lport"
-// - Egress rules map to ACLs with direction "from-lport"
-// - Default deny rules are created when a policy selects pods
+// - Each policy maps to one Port Group holding the ports of the selected pods
+// - All policy ACLs are attached to that Port Group
+// - Ingress rules map to ACLs with direction "to-lport" matching "outport == @pg"
+// - Egress rules map to ACLs with direction "from-lport" matching "inport == @pg"
+// - Default deny rules are created for every policy
 // - Allow rules are created for each rule in the policy
+// - Hairpinned Service traffic (the hairpin SNAT IP) is always allowed on ingress
 //
 // Priority Scheme:
 // - Default deny rules: 1000 (lower priority)
 // - Allow rules: 1001+ (higher priority, evaluated first)
 //
+// Peer Selectors:
+// - ipBlock peers are matched directly by CIDR
+// - podSelector/namespaceSelector peers map to one OVN Address Set per IP family ("$<name>_v4", "$<name>_v6")
+// - IPv4 operands are matched with ip4 fields, IPv6 operands with ip6 fields
+// - Pod and Namespace changes re-sync the Address Set membership
+//
 // Reference: OVN-Kubernetes pkg/ovn/controller/network_policy.go
 package ovn
 
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -60,21 +73,27 @@ type PolicyController struct {
 	client.Client
 	Scheme    *runtime.Scheme
 	OVNClient *ovndb.Client
+
+	// HairpinSNATIP is the source IP of Service traffic load balanced back
+	// to its sender (config.NetworkConfig.ServiceHairpinSNATIP). Pods may
+	// always reach themselves, so ingress allows it.
+	HairpinSNATIP string
 }
 
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile handles NetworkPolicy create/update/delete events
 //
 // The reconciliation logic:
 //  1. Get the NetworkPolicy
-//  2. If deleted, clean up all associated ACLs
+//  2. If deleted, delete the policy Port Group (and with it all ACLs) and Address Sets
 //  3. If created/updated:
-//     a. Find all pods selected by the policy
-//     b. Create default deny ACLs for selected pods
-//     c. Create allow ACLs for each ingress/egress rule
+//     a. Sync the Port Group membership with the pods selected by the policy
+//     b. Build default deny ACLs and allow ACLs for each ingress/egress rule
+//     c. Replace the Port Group ACLs only if they changed
 func (r *PolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling NetworkPolicy", "namespace", req.Namespace, "name", req.Name)
@@ -83,9 +102,15 @@ func (r *PolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	var policy networkingv1.NetworkPolicy
 	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
 		if errors.IsNotFound(err) {
-			// Policy was deleted, clean up ACLs
+			// Policy was deleted, clean up Port Group, ACLs and Address Sets
 			logger.Info("NetworkPolicy deleted, cleaning up ACLs")
-			return r.cleanupACLs(ctx, req.Namespace, req.Name)
+			if err := r.cleanupPortGroup(ctx, req.Namespace, req.Name); err != nil {
+				return ctrl.Result{}, err
+			}
+			if result, err := r.cleanupACLs(ctx, req.Namespace, req.Name); err != nil {
+				return result, err
+			}
+			return ctrl.Result{}, r.cleanupAddressSets(ctx, req.Namespace, req.Name, nil)
 		}
 		logger.Error(err, "Failed to get NetworkPolicy")
 		return ctrl.Result{}, err
@@ -101,7 +126,10 @@ func (r *PolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, nil
 }
 
-// processNetworkPolicy creates OVN ACLs for a NetworkPolicy
+// processNetworkPolicy syncs the OVN Port Group and ACLs for a NetworkPolicy
+//
+// The Port Group is kept even when the policy currently selects no pods,
+// so pods that appear later only need to be added to the group.
 func (r *PolicyController) processNetworkPolicy(ctx context.Context, policy *networkingv1.NetworkPolicy) error {
 	logger := log.FromContext(ctx)
 
@@ -113,31 +141,56 @@ func (r *PolicyController) processNetworkPolicy(ctx context.Context, policy *net
 
 	if len(selectedPods) == 0 {
 		logger.Info("No pods selected by NetworkPolicy")
-		return nil
 	}
 
-	// Get IPs of selected pods
-	selectedPodIPs := getPodIPs(selectedPods)
+	// Sync Port Group membership with the selected pods
+	pgName := BuildPolicyPortGroupName(policy.Namespace, policy.Name)
+	pgOps := ovndb.NewPortGroupOps(r.OVNClient)
 
-	// Clean up existing ACLs for this policy
-	if _, err := r.cleanupACLs(ctx, policy.Namespace, policy.Name); err != nil {
-		return fmt.Errorf("failed to cleanup existing ACLs: %w", err)
+	portUUIDs, err := r.getPodPortUUIDs(ctx, selectedPods)
+	if err != nil {
+		return fmt.Errorf("failed to get pod logical switch ports: %w", err)
 	}
 
-	// Create ACLs based on policy types
+	pgExternalIDs := map[string]string{
+		ovndb.ACLExternalIDPolicy:     fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+		ovndb.ACLExternalIDNamespace:  policy.Namespace,
+		ovndb.PortGroupExternalIDName: fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+	}
+	if err := pgOps.CreateOrUpdatePortGroup(ctx, pgName, portUUIDs, pgExternalIDs); err != nil {
+		return fmt.Errorf("failed to sync port group: %w", err)
+	}
+
+	// Build ACLs based on policy types, tracking the Address Sets in use
+	addressSets := make(map[string]bool)
+	var acls []*ovndb.ACL
 	for _, policyType := range policy.Spec.PolicyTypes {
 		switch policyType {
 		case networkingv1.PolicyTypeIngress:
-			if err := r.createIngressACLs(ctx, policy, selectedPodIPs); err != nil {
-				return fmt.Errorf("failed to create ingress ACLs: %w", err)
+			ingressACLs, err := r.buildIngressACLs(ctx, policy, pgName, addressSets)
+			if err != nil {
+				return fmt.Errorf("failed to build ingress ACLs: %w", err)
 			}
+			acls = append(acls, ingressACLs...)
 		case networkingv1.PolicyTypeEgress:
-			if err := r.createEgressACLs(ctx, policy, selectedPodIPs); err != nil {
-				return fmt.Errorf("failed to create egress ACLs: %w", err)
+			egressACLs, err := r.buildEgressACLs(ctx, policy, pgName, addressSets)
+			if err != nil {
+				return fmt.Errorf("failed to build egress ACLs: %w", err)
 			}
+			acls = append(acls, egressACLs...)
 		}
 	}
 
+	// Attach ACLs to the Port Group; unchanged ACLs are not rewritten
+	if err := pgOps.SetACLs(ctx, pgName, acls); err != nil {
+		return fmt.Errorf("failed to set port group ACLs: %w", err)
+	}
+
+	// Remove Address Sets of peers that no longer exist in the policy
+	if err := r.cleanupAddressSets(ctx, policy.Namespace, policy.Name, addressSets); err != nil {
+		return fmt.Errorf("failed to cleanup stale address sets: %w", err)
+	}
+
 	return nil
 }
 
@@ -156,120 +209,168 @@ func (r *PolicyController) getSelectedPods(ctx context.Context, policy *networki
 	return podList.Items, nil
 }
 
-// getPodIPs extracts IP addresses from pods
+// getPodPortUUIDs returns the Logical Switch Port UUIDs of the given pods
+// Pods whose port has not been created yet are skipped; they are added to
+// the Port Group when their Pod update triggers the next reconcile.
+func (r *PolicyController) getPodPortUUIDs(ctx context.Context, pods []corev1.Pod) ([]string, error) {
+	lspOps := ovndb.NewLogicalSwitchPortOps(r.OVNClient)
+
+	var uuids []string
+	for _, pod := range pods {
+		lsp, err := lspOps.GetLogicalSwitchPort(ctx, ovndb.BuildPortName(pod.Namespace, pod.Name))
+		if err != nil {
+			if ovndb.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		uuids = append(uuids, lsp.UUID)
+	}
+
+	sort.Strings(uuids)
+	return uuids, nil
+}
+
+// getPodIPs extracts IP addresses of all families from pods
 func getPodIPs(pods []corev1.Pod) []string {
 	var ips []string
 	for _, pod := range pods {
-		if pod.Status.PodIP != "" {
-			ips = append(ips, pod.Status.PodIP)
+		if len(pod.Status.PodIPs) == 0 {
+			if pod.Status.PodIP != "" {
+				ips = append(ips, pod.Status.PodIP)
+			}
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			ips = append(ips, podIP.IP)
 		}
 	}
 	return ips
 }
 
-// createIngressACLs creates ACLs for ingress rules
-func (r *PolicyController) createIngressACLs(ctx context.Context, policy *networkingv1.NetworkPolicy, selectedPodIPs []string) error {
-	aclOps := ovndb.NewACLOps(r.OVNClient)
+// splitIPsByFamily splits IPs into IPv4 and IPv6 addresses
+func splitIPsByFamily(ips []string) ([]string, []string) {
+	var v4, v6 []string
+	for _, ip := range ips {
+		if strings.Contains(ip, ":") {
+			v6 = append(v6, ip)
+		} else {
+			v4 = append(v4, ip)
+		}
+	}
+	return v4, v6
+}
 
-	// Create default deny ACL for ingress
-	defaultDenyName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionIngress, "default")
-	defaultDenyMatch := buildDefaultDenyMatch(selectedPodIPs, DirectionIngress)
+// buildIngressACLs builds ACLs for ingress rules
+// Names of the Address Sets referenced by the ACLs are added to addressSets.
+func (r *PolicyController) buildIngressACLs(ctx context.Context, policy *networkingv1.NetworkPolicy, pgName string, addressSets map[string]bool) ([]*ovndb.ACL, error) {
+	var acls []*ovndb.ACL
 
-	if defaultDenyMatch != "" {
-		_, err := aclOps.CreateACL(
-			ctx,
-			&defaultDenyName,
+	// Default deny ACL for ingress
+	defaultDenyName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionIngress, "default")
+	acls = append(acls, ovndb.BuildACL(
+		&defaultDenyName,
+		ovndb.ACLDirectionToLport,
+		ACLPriorityDefaultDenyIngress,
+		buildPortGroupDefaultDenyMatch(pgName, DirectionIngress),
+		ovndb.ACLActionDrop,
+		buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionIngress),
+	))
+
+	// Allow hairpinned Service traffic, a pod reaching itself
+	if hairpinMatch := buildHairpinAllowMatch(pgName, r.HairpinSNATIP); hairpinMatch != "" {
+		hairpinName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionIngress, "hairpin")
+		acls = append(acls, ovndb.BuildACL(
+			&hairpinName,
 			ovndb.ACLDirectionToLport,
-			ACLPriorityDefaultDenyIngress,
-			defaultDenyMatch,
-			ovndb.ACLActionDrop,
+			ACLPriorityAllowRuleBase,
+			hairpinMatch,
+			ovndb.ACLActionAllow,
 			buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionIngress),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create default deny ingress ACL: %w", err)
-		}
+		))
 	}
 
-	// Create allow ACLs for each ingress rule
+	// Allow ACLs for each ingress rule
 	for ruleIdx, rule := range policy.Spec.Ingress {
-		sourceCIDRs := getIngressSourceCIDRs(ctx, r.Client, policy.Namespace, rule.From)
-
 		peerCount := getIngressPeerCount(rule.From)
 		for peerIdx := 0; peerIdx < peerCount; peerIdx++ {
-			aclName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionIngress, fmt.Sprintf("%d_%d", ruleIdx, peerIdx))
-			match := ConvertIngressRuleToACLMatch(selectedPodIPs, sourceCIDRs, rule.Ports)
-
-			if match != "" {
-				priority := GetACLPriority(DirectionIngress, false, ruleIdx)
-				_, err := aclOps.CreateACL(
-					ctx,
-					&aclName,
-					ovndb.ACLDirectionToLport,
-					priority,
-					match,
-					ovndb.ACLActionAllow,
-					buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionIngress),
-				)
+			// An empty From list allows traffic from any source
+			var sourceCIDRs []string
+			if len(rule.From) > 0 {
+				var err error
+				sourceCIDRs, err = r.getIngressSourceCIDRs(ctx, policy, ruleIdx, peerIdx, rule.From[peerIdx], addressSets)
 				if err != nil {
-					return fmt.Errorf("failed to create ingress allow ACL: %w", err)
+					return nil, err
 				}
 			}
+
+			aclName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionIngress, fmt.Sprintf("%d_%d", ruleIdx, peerIdx))
+			acls = append(acls, ovndb.BuildACL(
+				&aclName,
+				ovndb.ACLDirectionToLport,
+				GetACLPriority(DirectionIngress, false, ruleIdx),
+				ConvertRuleToPortGroupACLMatch(pgName, DirectionIngress, sourceCIDRs, rule.Ports),
+				ovndb.ACLActionAllow,
+				buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionIngress),
+			))
 		}
 	}
 
-	return nil
+	return acls, nil
 }
 
-// createEgressACLs creates ACLs for egress rules
-func (r *PolicyController) createEgressACLs(ctx context.Context, policy *networkingv1.NetworkPolicy, selectedPodIPs []string) error {
-	aclOps := ovndb.NewACLOps(r.OVNClient)
+// buildEgressACLs builds ACLs for egress rules
+// Names of the Address Sets referenced by the ACLs are added to addressSets.
+func (r *PolicyController) buildEgressACLs(ctx context.Context, policy *networkingv1.NetworkPolicy, pgName string, addressSets map[string]bool) ([]*ovndb.ACL, error) {
+	var acls []*ovndb.ACL
 
-	// Create default deny ACL for egress
+	// Default deny ACL for egress
 	defaultDenyName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionEgress, "default")
-	defaultDenyMatch := buildDefaultDenyMatch(selectedPodIPs, DirectionEgress)
-
-	if defaultDenyMatch != "" {
-		_, err := aclOps.CreateACL(
-			ctx,
-			&defaultDenyName,
-			ovndb.ACLDirectionFromLport,
-			ACLPriorityDefaultDenyEgress,
-			defaultDenyMatch,
-			ovndb.ACLActionDrop,
-			buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionEgress),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create default deny egress ACL: %w", err)
-		}
-	}
-
-	// Create allow ACLs for each egress rule
+	acls = append(acls, ovndb.BuildACL(
+		&defaultDenyName,
+		ovndb.ACLDirectionFromLport,
+		ACLPriorityDefaultDenyEgress,
+		buildPortGroupDefaultDenyMatch(pgName, DirectionEgress),
+		ovndb.ACLActionDrop,
+		buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionEgress),
+	))
+
+	// Allow ACLs for each egress rule
 	for ruleIdx, rule := range policy.Spec.Egress {
-		destCIDRs := getEgressDestCIDRs(ctx, r.Client, policy.Namespace, rule.To)
-
 		peerCount := getEgressPeerCount(rule.To)
 		for peerIdx := 0; peerIdx < peerCount; peerIdx++ {
-			aclName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionEgress, fmt.Sprintf("%d_%d", ruleIdx, peerIdx))
-			match := ConvertEgressRuleToACLMatch(selectedPodIPs, destCIDRs, rule.Ports)
-
-			if match != "" {
-				priority := GetACLPriority(DirectionEgress, false, ruleIdx)
-				_, err := aclOps.CreateACL(
-					ctx,
-					&aclName,
-					ovndb.ACLDirectionFromLport,
-					priority,
-					match,
-					ovndb.ACLActionAllow,
-					buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionEgress),
-				)
+			// An empty To list allows traffic to any destination
+			var destCIDRs []string
+			if len(rule.To) > 0 {
+				var err error
+				destCIDRs, err = r.getEgressDestCIDRs(ctx, policy, ruleIdx, peerIdx, rule.To[peerIdx], addressSets)
 				if err != nil {
-					return fmt.Errorf("failed to create egress allow ACL: %w", err)
+					return nil, err
 				}
 			}
+
+			aclName := BuildPolicyACLName(policy.Namespace, policy.Name, DirectionEgress, fmt.Sprintf("%d_%d", ruleIdx, peerIdx))
+			acls = append(acls, ovndb.BuildACL(
+				&aclName,
+				ovndb.ACLDirectionFromLport,
+				GetACLPriority(DirectionEgress, false, ruleIdx),
+				ConvertRuleToPortGroupACLMatch(pgName, DirectionEgress, destCIDRs, rule.Ports),
+				ovndb.ACLActionAllow,
+				buildPolicyExternalIDs(policy.Namespace, policy.Name, DirectionEgress),
+			))
 		}
 	}
 
+	return acls, nil
+}
+
+// cleanupPortGroup deletes the Port Group of a NetworkPolicy
+// The ACLs attached to the group are garbage collected along with it.
+func (r *PolicyController) cleanupPortGroup(ctx context.Context, namespace, name string) error {
+	pgOps := ovndb.NewPortGroupOps(r.OVNClient)
+	if err := pgOps.DeletePortGroup(ctx, BuildPolicyPortGroupName(namespace, name)); err != nil {
+		return fmt.Errorf("failed to delete port group: %w", err)
+	}
 	return nil
 }
 
@@ -282,13 +383,197 @@ func (r *PolicyController) cleanupACLs(ctx context.Context, namespace, name stri
 	return ctrl.Result{}, nil
 }
 
+// cleanupAddressSets removes Address Sets owned by a NetworkPolicy
+// Sets whose names are in keep are preserved; a nil keep removes all of them.
+func (r *PolicyController) cleanupAddressSets(ctx context.Context, namespace, name string, keep map[string]bool) error {
+	asOps := ovndb.NewAddressSetOps(r.OVNClient)
+	policyRef := fmt.Sprintf("%s/%s", namespace, name)
+	err := asOps.DeleteAddressSetsWithPredicate(ctx, func(as *ovndb.AddressSet) bool {
+		return as.ExternalIDs[ovndb.ACLExternalIDPolicy] == policyRef && !keep[as.Name]
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete address sets: %w", err)
+	}
+	return nil
+}
+
+// getIngressSourceCIDRs returns the source match operands for one ingress peer
+func (r *PolicyController) getIngressSourceCIDRs(ctx context.Context, policy *networkingv1.NetworkPolicy, ruleIdx, peerIdx int, peer networkingv1.NetworkPolicyPeer, addressSets map[string]bool) ([]string, error) {
+	return r.getPeerAddresses(ctx, policy, DirectionIngress, ruleIdx, peerIdx, peer, addressSets)
+}
+
+// getEgressDestCIDRs returns the destination match operands for one egress peer
+func (r *PolicyController) getEgressDestCIDRs(ctx context.Context, policy *networkingv1.NetworkPolicy, ruleIdx, peerIdx int, peer networkingv1.NetworkPolicyPeer, addressSets map[string]bool) ([]string, error) {
+	return r.getPeerAddresses(ctx, policy, DirectionEgress, ruleIdx, peerIdx, peer, addressSets)
+}
+
+// getPeerAddresses returns the match operands for a NetworkPolicy peer
+//
+// An ipBlock peer yields its CIDR. A podSelector/namespaceSelector peer is
+// synced into two Address Sets holding the IPv4 and IPv6 IPs of the selected
+// Pods, and the returned operands reference both sets ("$<name>_v4",
+// "$<name>_v6"). An Address Set with no addresses matches nothing, which is
+// the expected semantics for a selector that currently selects no Pods.
+func (r *PolicyController) getPeerAddresses(
+	ctx context.Context,
+	policy *networkingv1.NetworkPolicy,
+	direction string,
+	ruleIdx, peerIdx int,
+	peer networkingv1.NetworkPolicyPeer,
+	addressSets map[string]bool,
+) ([]string, error) {
+	if peer.IPBlock != nil {
+		return []string{peer.IPBlock.CIDR}, nil
+	}
+
+	podIPs, err := r.getPeerPodIPs(ctx, policy.Namespace, peer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s peer %d_%d: %w", strings.ToLower(direction), ruleIdx, peerIdx, err)
+	}
+
+	key := BuildPeerAddressSetKey(policy.Namespace, policy.Name, direction, ruleIdx, peerIdx)
+	v4Name, v6Name := ovndb.BuildAddressSetNames(key)
+	v4IPs, v6IPs := splitIPsByFamily(podIPs)
+
+	asOps := ovndb.NewAddressSetOps(r.OVNClient)
+	var operands []string
+	for _, set := range []struct {
+		name, suffix string
+		ips          []string
+	}{
+		{v4Name, ovndb.AddressSetIPv4Suffix, v4IPs},
+		{v6Name, ovndb.AddressSetIPv6Suffix, v6IPs},
+	} {
+		externalIDs := buildPolicyExternalIDs(policy.Namespace, policy.Name, direction)
+		externalIDs[ovndb.AddressSetExternalIDName] = key + set.suffix
+		if err := asOps.CreateOrUpdateAddressSet(ctx, set.name, set.ips, externalIDs); err != nil {
+			return nil, fmt.Errorf("failed to sync address set %s: %w", key+set.suffix, err)
+		}
+		addressSets[set.name] = true
+		operands = append(operands, ovndb.BuildAddressSetReference(set.name))
+	}
+
+	return operands, nil
+}
+
+// getPeerPodIPs returns the sorted IPs of all Pods selected by a peer
+//
+// A nil namespaceSelector restricts the peer to the policy namespace, while
+// an empty one selects all namespaces. A nil podSelector selects all Pods in
+// the matched namespaces.
+func (r *PolicyController) getPeerPodIPs(ctx context.Context, policyNamespace string, peer networkingv1.NetworkPolicyPeer) ([]string, error) {
+	podSelector := labels.Everything()
+	if peer.PodSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pod selector: %w", err)
+		}
+		podSelector = sel
+	}
+
+	namespaces := []string{policyNamespace}
+	if peer.NamespaceSelector != nil {
+		nsSelector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+
+		var nsList corev1.NamespaceList
+		if err := r.List(ctx, &nsList, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+			return nil, err
+		}
+
+		namespaces = namespaces[:0]
+		for _, ns := range nsList.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	var ips []string
+	for _, ns := range namespaces {
+		var podList corev1.PodList
+		if err := r.List(ctx, &podList, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+			return nil, err
+		}
+		ips = append(ips, getPodIPs(podList.Items)...)
+	}
+
+	sort.Strings(ips)
+	return ips, nil
+}
+
 // SetupWithManager sets up the controller with the Manager
+//
+// Besides NetworkPolicies, the controller watches Pods and Namespaces so
+// that selected Pods and peer Address Sets follow label and IP changes.
 func (r *PolicyController) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&networkingv1.NetworkPolicy{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.podToPolicies),
+		).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToPolicies),
+		).
 		Complete(r)
 }
 
+// podToPolicies maps Pod events to the NetworkPolicies that may select the Pod
+//
+// Policies in the Pod's namespace may select it directly or through a
+// podSelector peer. Policies elsewhere can only reach it through a
+// namespaceSelector peer, so those are enqueued as well.
+func (r *PolicyController) podToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+
+	var policyList networkingv1.NetworkPolicyList
+	if err := r.List(ctx, &policyList); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list NetworkPolicies for Pod", "pod", pod.Namespace+"/"+pod.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		if policy.Namespace == pod.Namespace || hasNamespaceSelectorPeer(policy) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// namespaceToPolicies maps Namespace events to NetworkPolicies with
+// namespaceSelector peers, whose Address Sets depend on namespace labels
+func (r *PolicyController) namespaceToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	if _, ok := obj.(*corev1.Namespace); !ok {
+		return nil
+	}
+
+	var policyList networkingv1.NetworkPolicyList
+	if err := r.List(ctx, &policyList); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list NetworkPolicies for Namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range policyList.Items {
+		policy := &policyList.Items[i]
+		if hasNamespaceSelectorPeer(policy) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name},
+			})
+		}
+	}
+	return requests
+}
+
 // ============================================================================
 // Helper Functions for ACL Building
 // ============================================================================
@@ -360,13 +645,13 @@ func ConvertIngressRuleToACLMatch(selectedPodIPs, sourceCIDRs []string, ports []
 	var conditions []string
 
 	// Build destination match (selected pods)
-	destMatch := buildIPListMatch("ip4.dst", selectedPodIPs)
+	destMatch := buildIPListMatch("dst", selectedPodIPs)
 	if destMatch != "" {
 		conditions = append(conditions, destMatch)
 	}
 
 	// Build source match
-	srcMatch := buildIPListMatch("ip4.src", sourceCIDRs)
+	srcMatch := buildIPListMatch("src", sourceCIDRs)
 	if srcMatch != "" {
 		conditions = append(conditions, srcMatch)
 	}
@@ -393,13 +678,13 @@ func ConvertEgressRuleToACLMatch(selectedPodIPs, destCIDRs []string, ports []net
 	var conditions []string
 
 	// Build source match (selected pods)
-	srcMatch := buildIPListMatch("ip4.src", selectedPodIPs)
+	srcMatch := buildIPListMatch("src", selectedPodIPs)
 	if srcMatch != "" {
 		conditions = append(conditions, srcMatch)
 	}
 
 	// Build destination match
-	destMatch := buildIPListMatch("ip4.dst", destCIDRs)
+	destMatch := buildIPListMatch("dst", destCIDRs)
 	if destMatch != "" {
 		conditions = append(conditions, destMatch)
 	}
@@ -413,23 +698,47 @@ func ConvertEgressRuleToACLMatch(selectedPodIPs, destCIDRs []string, ports []net
 	return strings.Join(conditions, " && ")
 }
 
-// buildIPListMatch builds a match expression for a list of IPs/CIDRs
-func buildIPListMatch(field string, ips []string) string {
+// buildIPListMatch builds a match expression for a list of IPs/CIDRs and
+// Address Set references
+//
+// Each operand is compared against the ip4 or ip6 field of its family
+// ("ip4.src", "ip6.src" for side "src"), since OVN rejects an IPv6
+// address in an ip4 field.
+func buildIPListMatch(side string, ips []string) string {
 	if len(ips) == 0 {
 		return ""
 	}
-	if len(ips) == 1 {
-		return fmt.Sprintf("%s == %s", field, ips[0])
-	}
 
 	var parts []string
 	for _, ip := range ips {
-		parts = append(parts, fmt.Sprintf("%s == %s", field, ip))
+		parts = append(parts, fmt.Sprintf("%s == %s", ipMatchField(side, ip), ip))
+	}
+	if len(parts) == 1 {
+		return parts[0]
 	}
 	return "(" + strings.Join(parts, " || ") + ")"
 }
 
+// ipMatchField returns the ip4 or ip6 match field of an operand, given the
+// side of the packet ("src" or "dst")
+func ipMatchField(side, operand string) string {
+	if strings.HasPrefix(operand, "$") {
+		if ovndb.IsIPv6AddressSet(operand) {
+			return "ip6." + side
+		}
+		return "ip4." + side
+	}
+	if strings.Contains(operand, ":") {
+		return "ip6." + side
+	}
+	return "ip4." + side
+}
+
 // buildPortMatch builds a match expression for ports
+//
+// A port without a number matches every port of its protocol. Port ranges
+// are parenthesized when combined, since OVN rejects mixing && and ||
+// without parentheses.
 func buildPortMatch(ports []networkingv1.NetworkPolicyPort) string {
 	if len(ports) == 0 {
 		return ""
@@ -437,33 +746,47 @@ func buildPortMatch(ports []networkingv1.NetworkPolicyPort) string {
 
 	var parts []string
 	for _, port := range ports {
-		protocol := "tcp"
-		if port.Protocol != nil {
-			protocol = strings.ToLower(string(*port.Protocol))
-		}
-
-		if port.Port != nil {
-			portNum := port.Port.IntValue()
-			if port.EndPort != nil && *port.EndPort > int32(portNum) {
-				// Port range
-				parts = append(parts, fmt.Sprintf("%s.dst >= %d && %s.dst <= %d",
-					protocol, portNum, protocol, *port.EndPort))
-			} else {
-				// Single port
-				parts = append(parts, fmt.Sprintf("%s.dst == %d", protocol, portNum))
+		protocol := policyPortProtocol(port)
+
+		switch {
+		case port.Port == nil:
+			// Any port of the protocol
+			parts = append(parts, protocol)
+		case port.EndPort != nil && *port.EndPort > int32(port.Port.IntValue()):
+			// Port range
+			match := ovndb.BuildPortRangeMatch(protocol, "dst", port.Port.IntValue(), int(*port.EndPort))
+			if len(ports) > 1 {
+				match = "(" + match + ")"
 			}
+			parts = append(parts, match)
+		default:
+			// Single port
+			parts = append(parts, ovndb.BuildPortMatch(protocol, "dst", port.Port.IntValue()))
 		}
 	}
 
-	if len(parts) == 0 {
-		return ""
-	}
 	if len(parts) == 1 {
 		return parts[0]
 	}
 	return "(" + strings.Join(parts, " || ") + ")"
 }
 
+// policyPortProtocol returns the OVN match protocol of a NetworkPolicy
+// port (tcp, udp or sctp); the protocol defaults to TCP.
+func policyPortProtocol(port networkingv1.NetworkPolicyPort) string {
+	if port.Protocol == nil {
+		return "tcp"
+	}
+	switch *port.Protocol {
+	case corev1.ProtocolUDP:
+		return "udp"
+	case corev1.ProtocolSCTP:
+		return "sctp"
+	default:
+		return "tcp"
+	}
+}
+
 // buildDefaultDenyMatch builds the match expression for default deny rules
 func buildDefaultDenyMatch(podIPs []string, direction string) string {
 	if len(podIPs) == 0 {
@@ -471,9 +794,70 @@ func buildDefaultDenyMatch(podIPs []string, direction string) string {
 	}
 
 	if direction == DirectionIngress {
-		return buildIPListMatch("ip4.dst", podIPs)
+		return buildIPListMatch("dst", podIPs)
 	}
-	return buildIPListMatch("ip4.src", podIPs)
+	return buildIPListMatch("src", podIPs)
+}
+
+// BuildPolicyPortGroupName builds the OVN Port Group name for a NetworkPolicy
+//
+// The name is a hash of "<namespace>/<policyName>" since OVN identifiers
+// cannot contain '/' or '-'.
+func BuildPolicyPortGroupName(namespace, policyName string) string {
+	return ovndb.BuildPortGroupName(fmt.Sprintf("%s/%s", namespace, policyName))
+}
+
+// ConvertRuleToPortGroupACLMatch converts a rule peer to an OVN ACL match
+// expression scoped to the policy Port Group
+//
+// Ingress rules match traffic leaving OVN towards a member port
+// ("outport == @pg"), egress rules match traffic entering from a member
+// port ("inport == @pg"). Selected pods are no longer listed by IP, so the
+// match does not change when pods come and go.
+//
+// Parameters:
+//   - portGroup: Name of the policy Port Group
+//   - direction: "Ingress" or "Egress"
+//   - peerCIDRs: Source (ingress) or destination (egress) CIDRs and Address Set references
+//   - ports: Ports from the rule
+//
+// Returns:
+//   - string: OVN match expression
+func ConvertRuleToPortGroupACLMatch(portGroup, direction string, peerCIDRs []string, ports []networkingv1.NetworkPolicyPort) string {
+	if direction == DirectionIngress {
+		return ovndb.BuildMatchExpression(
+			buildPortGroupMatch(portGroup, direction),
+			ConvertIngressRuleToACLMatch(nil, peerCIDRs, ports),
+		)
+	}
+	return ovndb.BuildMatchExpression(
+		buildPortGroupMatch(portGroup, direction),
+		ConvertEgressRuleToACLMatch(nil, peerCIDRs, ports),
+	)
+}
+
+// buildPortGroupMatch builds the port match for the policy Port Group
+func buildPortGroupMatch(portGroup, direction string) string {
+	if direction == DirectionIngress {
+		return fmt.Sprintf("outport == %s", ovndb.BuildPortGroupReference(portGroup))
+	}
+	return fmt.Sprintf("inport == %s", ovndb.BuildPortGroupReference(portGroup))
+}
+
+// buildHairpinAllowMatch builds the match of hairpinned Service traffic to
+// the policy Port Group, whose source is the hairpin SNAT IP
+// Returns an empty string if no hairpin SNAT IP is configured.
+func buildHairpinAllowMatch(portGroup, hairpinSNATIP string) string {
+	srcMatch := buildIPListMatch("src", strings.Fields(hairpinSNATIP))
+	if srcMatch == "" {
+		return ""
+	}
+	return ovndb.BuildMatchExpression(buildPortGroupMatch(portGroup, DirectionIngress), srcMatch)
+}
+
+// buildPortGroupDefaultDenyMatch builds the default deny match for the policy Port Group
+func buildPortGroupDefaultDenyMatch(portGroup, direction string) string {
+	return buildPortGroupMatch(portGroup, direction) + " && ip"
 }
 
 // buildPolicyExternalIDs builds external IDs for policy ACLs
@@ -485,28 +869,40 @@ func buildPolicyExternalIDs(namespace, policyName, direction string) map[string]
 	}
 }
 
-// getIngressSourceCIDRs extracts source CIDRs from ingress peers
-func getIngressSourceCIDRs(ctx context.Context, c client.Client, namespace string, peers []networkingv1.NetworkPolicyPeer) []string {
-	var cidrs []string
-	for _, peer := range peers {
-		if peer.IPBlock != nil {
-			cidrs = append(cidrs, peer.IPBlock.CIDR)
-		}
-		// TODO: Handle PodSelector and NamespaceSelector
-	}
-	return cidrs
+// BuildPeerAddressSetKey builds the unhashed key of a peer Address Set
+//
+// Format: <namespace>/<policyName>_<direction>_<ruleIndex>_<peerIndex>
+//
+// The OVN Address Set name is derived from this key with
+// ovndb.BuildAddressSetName.
+func BuildPeerAddressSetKey(namespace, policyName, direction string, ruleIdx, peerIdx int) string {
+	return fmt.Sprintf("%s/%s_%s_%d_%d",
+		namespace,
+		policyName,
+		strings.ToLower(direction),
+		ruleIdx,
+		peerIdx,
+	)
 }
 
-// getEgressDestCIDRs extracts destination CIDRs from egress peers
-func getEgressDestCIDRs(ctx context.Context, c client.Client, namespace string, peers []networkingv1.NetworkPolicyPeer) []string {
-	var cidrs []string
-	for _, peer := range peers {
-		if peer.IPBlock != nil {
-			cidrs = append(cidrs, peer.IPBlock.CIDR)
+// hasNamespaceSelectorPeer reports whether any rule of the policy has a
+// peer with a namespaceSelector
+func hasNamespaceSelectorPeer(policy *networkingv1.NetworkPolicy) bool {
+	for _, rule := range policy.Spec.Ingress {
+		for _, peer := range rule.From {
+			if peer.NamespaceSelector != nil {
+				return true
+			}
+		}
+	}
+	for _, rule := range policy.Spec.Egress {
+		for _, peer := range rule.To {
+			if peer.NamespaceSelector != nil {
+				return true
+			}
 		}
-		// TODO: Handle PodSelector and NamespaceSelector
 	}
-	return cidrs
+	return false
 }
 
 // getIngressPeerCount returns the number of peers (minimum 1 for iteration)
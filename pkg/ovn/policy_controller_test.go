@@ -6,6 +6,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
@@ -329,6 +330,57 @@ func TestPortRangeMatch(t *testing.T) {
 	}
 }
 
+// TestBuildPortMatchProtocols tests port matches across protocols.
+func TestBuildPortMatchProtocols(t *testing.T) {
+	tcp := corev1.ProtocolTCP
+	udp := corev1.ProtocolUDP
+	sctp := corev1.ProtocolSCTP
+	port53 := intstr.FromInt(53)
+	port8000 := intstr.FromInt(8000)
+	endPort9000 := int32(9000)
+
+	tests := []struct {
+		name     string
+		ports    []networkingv1.NetworkPolicyPort
+		expected string
+	}{
+		{
+			name:     "SCTP port",
+			ports:    []networkingv1.NetworkPolicyPort{{Protocol: &sctp, Port: &port53}},
+			expected: "sctp.dst == 53",
+		},
+		{
+			name:     "SCTP without port",
+			ports:    []networkingv1.NetworkPolicyPort{{Protocol: &sctp}},
+			expected: "sctp",
+		},
+		{
+			name: "TCP and UDP on one port number",
+			ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &port53},
+				{Protocol: &tcp, Port: &port53},
+			},
+			expected: "(udp.dst == 53 || tcp.dst == 53)",
+		},
+		{
+			name: "range combined with a port",
+			ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &sctp, Port: &port8000, EndPort: &endPort9000},
+				{Protocol: &udp, Port: &port53},
+			},
+			expected: "((sctp.dst >= 8000 && sctp.dst <= 9000) || udp.dst == 53)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := buildPortMatch(tt.ports); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
 // TestPriorityOrdering tests that allow rules have higher priority than default deny.
 func TestPriorityOrdering(t *testing.T) {
 	// Default deny should have lower priority than allow rules
@@ -379,3 +431,183 @@ func TestEmptyInputs(t *testing.T) {
 		t.Errorf("expected empty string, got %q", result)
 	}
 }
+
+// TestBuildPeerAddressSetKey tests the peer Address Set key building function.
+func TestBuildPeerAddressSetKey(t *testing.T) {
+	key := BuildPeerAddressSetKey("default", "allow-web", DirectionIngress, 1, 2)
+	expected := "default/allow-web_ingress_1_2"
+	if key != expected {
+		t.Errorf("expected %q, got %q", expected, key)
+	}
+
+	// Ingress and egress peers at the same index must not share an Address Set
+	ingress := ovndb.BuildAddressSetName(BuildPeerAddressSetKey("default", "p", DirectionIngress, 0, 0))
+	egress := ovndb.BuildAddressSetName(BuildPeerAddressSetKey("default", "p", DirectionEgress, 0, 0))
+	if ingress == egress {
+		t.Errorf("ingress and egress address set names collide: %q", ingress)
+	}
+}
+
+// TestAddressSetPeerMatch tests that Address Set references are usable as match operands.
+func TestAddressSetPeerMatch(t *testing.T) {
+	tcp := corev1.ProtocolTCP
+	port80 := intstr.FromInt(80)
+
+	asName := ovndb.BuildAddressSetName("default/allow-web_ingress_0_0")
+	ref := ovndb.BuildAddressSetReference(asName)
+
+	result := ConvertIngressRuleToACLMatch([]string{"10.244.1.5"}, []string{ref},
+		[]networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port80}})
+	expected := "ip4.dst == 10.244.1.5 && ip4.src == $" + asName + " && tcp.dst == 80"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+// TestDualStackPeerMatch tests that each operand is matched with the field of its IP family.
+func TestDualStackPeerMatch(t *testing.T) {
+	v4Name, v6Name := ovndb.BuildAddressSetNames("default/allow-web_ingress_0_0")
+	peers := []string{
+		ovndb.BuildAddressSetReference(v4Name),
+		ovndb.BuildAddressSetReference(v6Name),
+		"fd00:10::/64",
+	}
+
+	result := ConvertIngressRuleToACLMatch(nil, peers, nil)
+	expected := "(ip4.src == $" + v4Name + " || ip6.src == $" + v6Name + " || ip6.src == fd00:10::/64)"
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	pods := []corev1.Pod{
+		{Status: corev1.PodStatus{PodIP: "10.244.1.5", PodIPs: []corev1.PodIP{{IP: "10.244.1.5"}, {IP: "fd00:10::5"}}}},
+		{Status: corev1.PodStatus{PodIP: "10.244.1.6"}},
+	}
+	v4, v6 := splitIPsByFamily(getPodIPs(pods))
+	if len(v4) != 2 || len(v6) != 1 || v6[0] != "fd00:10::5" {
+		t.Errorf("unexpected pod IPs: v4=%v v6=%v", v4, v6)
+	}
+}
+
+// TestHasNamespaceSelectorPeer tests detection of namespaceSelector peers.
+func TestHasNamespaceSelectorPeer(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     networkingv1.NetworkPolicySpec
+		expected bool
+	}{
+		{
+			name: "pod selector only",
+			spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "ingress namespace selector",
+			spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{From: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "egress namespace selector",
+			spec: networkingv1.NetworkPolicySpec{
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					{To: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &networkingv1.NetworkPolicy{Spec: tt.spec}
+			if result := hasNamespaceSelectorPeer(policy); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestConvertRuleToPortGroupACLMatch tests port group scoped ACL matches.
+func TestConvertRuleToPortGroupACLMatch(t *testing.T) {
+	tcp := corev1.ProtocolTCP
+	port80 := intstr.FromInt(80)
+	ports := []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port80}}
+
+	pgName := BuildPolicyPortGroupName("default", "allow-web")
+	pgRef := ovndb.BuildPortGroupReference(pgName)
+
+	tests := []struct {
+		name      string
+		direction string
+		peers     []string
+		ports     []networkingv1.NetworkPolicyPort
+		expected  string
+	}{
+		{
+			name:      "ingress from CIDR with port",
+			direction: DirectionIngress,
+			peers:     []string{"10.0.0.0/8"},
+			ports:     ports,
+			expected:  "outport == " + pgRef + " && ip4.src == 10.0.0.0/8 && tcp.dst == 80",
+		},
+		{
+			name:      "ingress from any source",
+			direction: DirectionIngress,
+			expected:  "outport == " + pgRef,
+		},
+		{
+			name:      "egress to CIDR with port",
+			direction: DirectionEgress,
+			peers:     []string{"10.0.0.0/8"},
+			ports:     ports,
+			expected:  "inport == " + pgRef + " && ip4.dst == 10.0.0.0/8 && tcp.dst == 80",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConvertRuleToPortGroupACLMatch(pgName, tt.direction, tt.peers, tt.ports)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	if got := buildPortGroupDefaultDenyMatch(pgName, DirectionIngress); got != "outport == "+pgRef+" && ip" {
+		t.Errorf("unexpected ingress default deny match %q", got)
+	}
+	if got := buildPortGroupDefaultDenyMatch(pgName, DirectionEgress); got != "inport == "+pgRef+" && ip" {
+		t.Errorf("unexpected egress default deny match %q", got)
+	}
+}
+
+// TestBuildHairpinAllowMatch tests the match of hairpinned Service traffic.
+func TestBuildHairpinAllowMatch(t *testing.T) {
+	pgRef := ovndb.BuildPortGroupReference("pg")
+
+	tests := []struct {
+		name     string
+		ips      string
+		expected string
+	}{
+		{"disabled", "", ""},
+		{"ipv4", "169.254.169.5", "outport == " + pgRef + " && ip4.src == 169.254.169.5"},
+		{"dual stack", "169.254.169.5 fd69::5", "outport == " + pgRef + " && (ip4.src == 169.254.169.5 || ip6.src == fd69::5)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := buildHairpinAllowMatch("pg", tt.ips); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -8,12 +8,18 @@
 // - Creating OVN Load Balancers for ClusterIP Services
 // - Managing VIP to backend mappings based on EndpointSlices
 // - Handling NodePort Services with per-node load balancers
+// - Programming spec.externalIPs as VIPs
+// - Allocating external IPs of LoadBalancer Services from LoadBalancerIPPools
+// - Applying ClientIP session affinity (options:affinity_timeout)
+// - Configuring OVN health checks of the backends on request
 // - Cleaning up OVN resources when Services are deleted
 // - Attaching Load Balancers to appropriate Logical Switches
 //
 // Load Balancer Naming Convention:
 // - ClusterIP: "Service_<namespace>/<name>_<protocol>"
 // - NodePort: "Service_<namespace>/<name>_<protocol>_nodeport"
+// - ExternalIP: "Service_<namespace>/<name>_<protocol>_externalip"
+// - LoadBalancer: "Service_<namespace>/<name>_<protocol>_lb"
 //
 // Reference: OVN-Kubernetes pkg/ovn/controller/services/
 package ovn
@@ -24,14 +30,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -51,9 +60,16 @@ const (
 	LBExternalIDKind      = "k8s.ovn.org/kind"
 	LBExternalIDOwner     = "k8s.ovn.org/owner"
 
+	// ServiceHealthCheckAnnotation enables OVN health checks on the Load
+	// Balancers of a Service, listing the protocols to check (e.g., "tcp"
+	// or "tcp,udp")
+	ServiceHealthCheckAnnotation = "zstack.io/lb-health-check"
+
 	// Load Balancer kinds
-	LBKindClusterIP = "ClusterIP"
-	LBKindNodePort  = "NodePort"
+	LBKindClusterIP    = "ClusterIP"
+	LBKindNodePort     = "NodePort"
+	LBKindExternalIP   = "ExternalIP"
+	LBKindLoadBalancer = "LoadBalancer"
 )
 
 // ServiceReconciler reconciles Service objects for OVN Load Balancer management.
@@ -61,7 +77,7 @@ const (
 // The reconciler is responsible for:
 // - Creating/updating OVN Load Balancers for Services
 // - Managing VIP to backend mappings
-// - Attaching Load Balancers to Logical Switches
+// - Adding Load Balancers to the cluster Load Balancer Group
 // - Cleaning up resources when Services are deleted
 type ServiceReconciler struct {
 	// client is the Kubernetes client
@@ -85,10 +101,32 @@ type ServiceReconciler struct {
 	// lsOps provides Logical Switch operations
 	lsOps *ovndb.LogicalSwitchOps
 
+	// lrOps provides Logical Router operations
+	lrOps *ovndb.LogicalRouterOps
+
+	// lbgOps provides Load Balancer Group operations
+	lbgOps *ovndb.LoadBalancerGroupOps
+
+	// tvOps provides Chassis Template Variable operations
+	tvOps *ovndb.ChassisTemplateVarOps
+
+	// nbgOps provides NB_Global operations
+	nbgOps *ovndb.NBGlobalOps
+
 	// serviceLBs tracks Load Balancer UUIDs per Service
 	// Key: namespace/name, Value: map of protocol -> LB UUID
 	serviceLBs   map[string]map[string]string
 	serviceLBsMu sync.RWMutex
+
+	// lbIngressIPs tracks the external IPs allocated to LoadBalancer
+	// Services until their status reaches the cache
+	// Key: IP, Value: namespace/name
+	lbIngressIPs map[string]string
+	lbIngressMu  sync.Mutex
+
+	// lbIndexSynced is set once SyncLoadBalancers rebuilt serviceLBs from
+	// OVN; no Service is reconciled before that
+	lbIndexSynced atomic.Bool
 }
 
 // NewServiceReconciler creates a new ServiceReconciler.
@@ -110,14 +148,19 @@ func NewServiceReconciler(
 	ovnClient *ovndb.Client,
 ) *ServiceReconciler {
 	return &ServiceReconciler{
-		client:     c,
-		scheme:     scheme,
-		recorder:   recorder,
-		config:     cfg,
-		ovnClient:  ovnClient,
-		lbOps:      ovndb.NewLoadBalancerOps(ovnClient),
-		lsOps:      ovndb.NewLogicalSwitchOps(ovnClient),
-		serviceLBs: make(map[string]map[string]string),
+		client:       c,
+		scheme:       scheme,
+		recorder:     recorder,
+		config:       cfg,
+		ovnClient:    ovnClient,
+		lbOps:        ovndb.NewLoadBalancerOps(ovnClient),
+		lsOps:        ovndb.NewLogicalSwitchOps(ovnClient),
+		lrOps:        ovndb.NewLogicalRouterOps(ovnClient),
+		lbgOps:       ovndb.NewLoadBalancerGroupOps(ovnClient),
+		tvOps:        ovndb.NewChassisTemplateVarOps(ovnClient),
+		nbgOps:       ovndb.NewNBGlobalOps(ovnClient),
+		serviceLBs:   make(map[string]map[string]string),
+		lbIngressIPs: make(map[string]string),
 	}
 }
 
@@ -128,11 +171,17 @@ func NewServiceReconciler(
 // 2. Skip Services that don't need load balancing (ExternalName, Headless)
 // 3. Get EndpointSlices for the Service
 // 4. Create/update OVN Load Balancers with VIP and backends
-// 5. Attach Load Balancers to appropriate Logical Switches
+// 5. Add Load Balancers to the cluster Load Balancer Group
 func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := klog.FromContext(ctx).WithValues("service", req.NamespacedName)
 	log.V(4).Info("Reconciling Service")
 
+	// Hold off until the Load Balancers created before a restart are tracked
+	if !r.LoadBalancersSynced() {
+		log.V(4).Info("Load Balancers not synced yet, requeuing")
+		return ctrl.Result{RequeueAfter: lbSyncRequeueDelay}, nil
+	}
+
 	// Get the Service
 	svc := &corev1.Service{}
 	if err := r.client.Get(ctx, req.NamespacedName, svc); err != nil {
@@ -197,34 +246,73 @@ func (r *ServiceReconciler) reconcileService(ctx context.Context, svc *corev1.Se
 		return ctrl.Result{}, fmt.Errorf("failed to get endpoints: %w", err)
 	}
 
-	// Process each port in the Service
-	for _, port := range svc.Spec.Ports {
-		protocol := string(port.Protocol)
-		if protocol == "" {
-			protocol = "TCP"
-		}
+	// Write the per-node backends of internalTrafficPolicy: Local and
+	// topology-aware Services, and of externalTrafficPolicy: Local NodePorts;
+	// their VIPs reference them
+	if err := r.ensureTemplateVars(ctx, svc, endpoints); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure template variables: %w", err)
+	}
+	template := usesNodeBackends(svc, endpoints)
+
+	// Each protocol has its own Load Balancers holding the VIPs of all its
+	// ports; a port number may be used by several protocols (e.g., DNS)
+	isNodePort := svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer
+	for protocol, ports := range servicePortsByProtocol(svc) {
+		clusterIPVIPs := map[string]string{}
+		nodePortVIPs := map[string]string{}
+		hasNodePort := false
+
+		for _, port := range ports {
+			nodePort := isNodePort && port.NodePort > 0
+			hasNodePort = hasNodePort || nodePort
+
+			// Build backends from endpoints
+			backends := r.buildBackends(endpoints, port)
+			if backends == "" {
+				continue
+			}
 
-		// Build VIP string
-		vip := ovndb.BuildVIP(svc.Spec.ClusterIP, int(port.Port))
+			clusterIPBackends := backends
+			if template {
+				clusterIPBackends = ovndb.BuildTemplateReference(
+					templateVarName(svc.Namespace, svc.Name, protocol, port.Port))
+			}
+			clusterIPVIPs[ovndb.BuildVIP(svc.Spec.ClusterIP, int(port.Port))] = clusterIPBackends
 
-		// Build backends from endpoints
-		backends := r.buildBackends(endpoints, port.TargetPort.IntValue(), protocol)
+			if nodePort {
+				vip, nodePortBackends := nodePortVIP(svc, protocol, port, backends)
+				nodePortVIPs[vip] = nodePortBackends
+			}
+		}
 
 		// Create or update Load Balancer for ClusterIP
-		if err := r.ensureClusterIPLoadBalancer(ctx, svc, port, vip, backends); err != nil {
+		if err := r.ensureClusterIPLoadBalancer(ctx, svc, protocol, clusterIPVIPs, template); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to ensure ClusterIP LB: %w", err)
 		}
 
 		// Handle NodePort if applicable
-		if svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
-			if port.NodePort > 0 {
-				if err := r.ensureNodePortLoadBalancer(ctx, svc, port, backends); err != nil {
-					return ctrl.Result{}, fmt.Errorf("failed to ensure NodePort LB: %w", err)
-				}
+		if hasNodePort {
+			if err := r.ensureNodePortLoadBalancer(ctx, svc, protocol, nodePortVIPs); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to ensure NodePort LB: %w", err)
 			}
 		}
 	}
 
+	// Program spec.externalIPs
+	if err := r.ensureExternalIPLoadBalancers(ctx, svc, endpoints); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure external IP LBs: %w", err)
+	}
+
+	// Allocate and program the external IPs of LoadBalancer Services
+	if err := r.ensureLoadBalancerIngress(ctx, svc, endpoints); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure LoadBalancer ingress: %w", err)
+	}
+
+	// Configure health checks of the Load Balancers
+	if err := r.ensureHealthChecks(ctx, svc, endpoints); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure LB health checks: %w", err)
+	}
+
 	// Clean up Load Balancers for removed ports
 	if err := r.cleanupStaleLoadBalancers(ctx, svc); err != nil {
 		log.V(4).Info("Failed to cleanup stale LBs", "error", err)
@@ -264,6 +352,9 @@ func (r *ServiceReconciler) getEndpointsForService(ctx context.Context, svc *cor
 			continue
 		}
 
+		// Target ports resolved for the endpoints of this slice
+		ports := endpointSlicePorts(eps.Ports)
+
 		for _, endpoint := range eps.Endpoints {
 			// Check if endpoint is ready
 			// Ready means the Pod is ready to receive traffic
@@ -293,6 +384,7 @@ func (r *ServiceReconciler) getEndpointsForService(ctx context.Context, svc *cor
 				info := EndpointInfo{
 					Address:     addr,
 					NodeName:    "",
+					Ports:       ports,
 					Ready:       isReady,
 					Serving:     isServing,
 					Terminating: isTerminating,
@@ -300,9 +392,18 @@ func (r *ServiceReconciler) getEndpointsForService(ctx context.Context, svc *cor
 				if endpoint.NodeName != nil {
 					info.NodeName = *endpoint.NodeName
 				}
+				if ref := endpoint.TargetRef; ref != nil && ref.Kind == "Pod" {
+					info.PodNamespace = ref.Namespace
+					info.PodName = ref.Name
+				}
 				if endpoint.Zone != nil {
 					info.Zone = *endpoint.Zone
 				}
+				if endpoint.Hints != nil {
+					for _, zone := range endpoint.Hints.ForZones {
+						info.ForZones = append(info.ForZones, zone.Name)
+					}
+				}
 				endpoints = append(endpoints, info)
 			}
 		}
@@ -386,9 +487,20 @@ type EndpointInfo struct {
 	// Zone is the zone of the endpoint (for topology-aware routing)
 	Zone string
 
+	// ForZones are the zones the endpoint is hinted for (hints.forZones)
+	ForZones []string
+
+	// PodNamespace and PodName identify the Pod behind the endpoint, if any
+	PodNamespace string
+	PodName      string
+
 	// TargetPort is the target port for this endpoint
 	TargetPort int
 
+	// Ports maps Service port names to the port numbers this endpoint
+	// serves them on, as resolved in its EndpointSlice
+	Ports map[string]int
+
 	// Ready indicates if the endpoint is ready to receive traffic
 	Ready bool
 
@@ -399,14 +511,54 @@ type EndpointInfo struct {
 	Terminating bool
 }
 
+// endpointSlicePorts maps the port names of an EndpointSlice to their
+// port numbers
+func endpointSlicePorts(ports []discoveryv1.EndpointPort) map[string]int {
+	result := make(map[string]int, len(ports))
+	for _, p := range ports {
+		if p.Port == nil {
+			continue
+		}
+		name := ""
+		if p.Name != nil {
+			name = *p.Name
+		}
+		result[name] = int(*p.Port)
+	}
+	return result
+}
+
+// endpointTargetPort returns the port an endpoint serves a Service port on,
+// or 0 if it cannot be resolved.
+//
+// The EndpointSlice controller resolves targetPort, named ports included,
+// for each Pod and records it under the Service port name; Pods exposing a
+// named port on different numbers end up in different slices. Without such
+// an entry, only a numeric targetPort can be used.
+func endpointTargetPort(ep EndpointInfo, port corev1.ServicePort) int {
+	if p, ok := ep.Ports[port.Name]; ok {
+		return p
+	}
+	if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal > 0 {
+		return int(port.TargetPort.IntVal)
+	}
+	return 0
+}
+
 // buildBackends builds the backend string for OVN Load Balancer.
-func (r *ServiceReconciler) buildBackends(endpoints []EndpointInfo, targetPort int, protocol string) string {
+// Each endpoint uses its own resolved target port; endpoints without one
+// are skipped.
+func (r *ServiceReconciler) buildBackends(endpoints []EndpointInfo, port corev1.ServicePort) string {
 	if len(endpoints) == 0 {
 		return ""
 	}
 
 	var backends []string
 	for _, ep := range endpoints {
+		targetPort := endpointTargetPort(ep, port)
+		if targetPort == 0 {
+			continue
+		}
 		backend := ovndb.BuildVIP(ep.Address, targetPort)
 		backends = append(backends, backend)
 	}
@@ -414,204 +566,273 @@ func (r *ServiceReconciler) buildBackends(endpoints []EndpointInfo, targetPort i
 	return strings.Join(backends, ",")
 }
 
-// ensureClusterIPLoadBalancer creates or updates the ClusterIP Load Balancer.
-func (r *ServiceReconciler) ensureClusterIPLoadBalancer(
-	ctx context.Context,
-	svc *corev1.Service,
-	port corev1.ServicePort,
-	vip, backends string,
-) error {
-	log := klog.FromContext(ctx).WithValues(
-		"service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-		"port", port.Port,
-		"protocol", port.Protocol,
-	)
-
-	protocol := strings.ToLower(string(port.Protocol))
-	if protocol == "" {
-		protocol = ovndb.LoadBalancerProtocolTCP
+// sessionAffinityTimeout returns the affinity_timeout option of a Service's
+// Load Balancers, or an empty string without session affinity.
+//
+// With ClientIP affinity, OVN keeps sending a client to the same backend
+// until the client has been idle for the timeout, as kube-proxy does.
+func sessionAffinityTimeout(svc *corev1.Service) string {
+	if svc.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return ""
+	}
+	timeout := corev1.DefaultClientIPServiceAffinitySeconds
+	if cfg := svc.Spec.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil && cfg.ClientIP.TimeoutSeconds != nil {
+		timeout = *cfg.ClientIP.TimeoutSeconds
 	}
+	return strconv.Itoa(int(timeout))
+}
 
-	// Build Load Balancer name
-	lbName := buildLoadBalancerName(svc.Namespace, svc.Name, protocol, LBKindClusterIP)
+// hairpinSNATIP returns the source IP of hairpinned traffic, empty for the
+// OVN default (the VIP).
+func (r *ServiceReconciler) hairpinSNATIP() string {
+	if r.config == nil {
+		return ""
+	}
+	return r.config.Network.ServiceHairpinSNATIP
+}
 
-	// Build external IDs
-	externalIDs := map[string]string{
-		LBExternalIDService:   fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-		LBExternalIDNamespace: svc.Namespace,
-		LBExternalIDKind:      LBKindClusterIP,
-		LBExternalIDOwner:     ServiceControllerName,
+// optionsChanged checks if applying options, where an empty value removes
+// the key, would change the existing options
+func optionsChanged(existing, options map[string]string) bool {
+	for k, v := range options {
+		if existing[k] != v {
+			return true
+		}
 	}
+	return false
+}
 
-	// Build VIPs map
-	vips := map[string]string{}
-	if backends != "" {
-		vips[vip] = backends
+// createOptions returns the options to create a Load Balancer with,
+// dropping the empty values that only remove a key on update
+func createOptions(options map[string]string) map[string]string {
+	result := make(map[string]string, len(options))
+	for k, v := range options {
+		if v != "" {
+			result[k] = v
+		}
 	}
+	return result
+}
+
+// ensureLoadBalancer creates or updates the Load Balancer of a Service for
+// one kind and protocol, tracks it and adds it to the cluster Load Balancer
+// Group. An empty option value removes the option. It returns the UUID of
+// the Load Balancer.
+func (r *ServiceReconciler) ensureLoadBalancer(
+	ctx context.Context,
+	svc *corev1.Service,
+	kind, protocol string,
+	vips, options map[string]string,
+) (string, error) {
+	log := klog.FromContext(ctx).WithValues(
+		"service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
+		"protocol", protocol,
+		"kind", kind,
+	)
+
+	lbName := buildLoadBalancerName(svc.Namespace, svc.Name, protocol, kind)
 
-	// Check if Load Balancer exists
 	existingLB, err := r.lbOps.GetLoadBalancer(ctx, lbName)
 	if err != nil && !ovndb.IsNotFound(err) {
-		return fmt.Errorf("failed to get existing LB: %w", err)
+		return "", fmt.Errorf("failed to get existing %s LB: %w", kind, err)
 	}
 
+	var lbUUID string
 	if existingLB != nil {
-		// Update existing Load Balancer
 		log.V(4).Info("Updating existing Load Balancer", "name", lbName)
 
-		// Update VIPs
 		if err := r.lbOps.SetVips(ctx, lbName, vips); err != nil {
-			return fmt.Errorf("failed to update LB VIPs: %w", err)
+			return "", fmt.Errorf("failed to update %s LB VIPs: %w", kind, err)
 		}
-
-		// Track the LB UUID
-		r.trackLoadBalancer(svc.Namespace, svc.Name, protocol, existingLB.UUID)
+		if optionsChanged(existingLB.Options, options) {
+			if err := r.lbOps.SetOptions(ctx, lbName, options); err != nil {
+				return "", fmt.Errorf("failed to update %s LB options: %w", kind, err)
+			}
+		}
+		lbUUID = existingLB.UUID
 	} else {
-		// Create new Load Balancer
-		log.Info("Creating new Load Balancer", "name", lbName, "vip", vip)
+		log.Info("Creating new Load Balancer", "name", lbName, "vips", len(vips))
 
-		lb, err := r.lbOps.CreateLoadBalancer(ctx, lbName, protocol, vips, nil, externalIDs)
+		externalIDs := map[string]string{
+			LBExternalIDService:   fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
+			LBExternalIDNamespace: svc.Namespace,
+			LBExternalIDKind:      kind,
+			LBExternalIDOwner:     ServiceControllerName,
+		}
+		lb, err := r.lbOps.CreateLoadBalancer(ctx, lbName, protocol, vips, createOptions(options), externalIDs)
 		if err != nil {
-			return fmt.Errorf("failed to create LB: %w", err)
+			return "", fmt.Errorf("failed to create %s LB: %w", kind, err)
 		}
+		lbUUID = lb.UUID
+	}
 
-		// Track the LB UUID
-		r.trackLoadBalancer(svc.Namespace, svc.Name, protocol, lb.UUID)
+	r.trackLoadBalancer(svc.Namespace, svc.Name, lbTrackingKey(protocol, kind), lbUUID)
 
-		// Attach Load Balancer to all Logical Switches
-		if err := r.attachLoadBalancerToSwitches(ctx, lb.UUID); err != nil {
-			log.V(4).Info("Failed to attach LB to switches", "error", err)
-		}
+	// Add Load Balancer to the cluster Load Balancer Group
+	if err := r.addLoadBalancerToGroup(ctx, lbUUID); err != nil {
+		return "", fmt.Errorf("failed to add %s LB to group: %w", kind, err)
 	}
 
-	return nil
+	return lbUUID, nil
 }
 
-// ensureNodePortLoadBalancer creates or updates the NodePort Load Balancer.
+// ensureClusterIPLoadBalancer creates or updates the ClusterIP Load Balancer
+// of a protocol, holding the VIPs of all the Service ports of that protocol.
+func (r *ServiceReconciler) ensureClusterIPLoadBalancer(
+	ctx context.Context,
+	svc *corev1.Service,
+	protocol string,
+	vips map[string]string,
+	template bool,
+) error {
+	// Build options for the Load Balancer; an empty value removes the option
+	options := map[string]string{
+		ovndb.LBOptionAffinityTimeout: sessionAffinityTimeout(svc),
+		ovndb.LBOptionHairpinSNATIP:   r.hairpinSNATIP(),
+		ovndb.LBOptionTemplate:        "",
+		ovndb.LBOptionAddressFamily:   "",
+	}
+	if template {
+		// Backends are per-node template variables
+		options[ovndb.LBOptionTemplate] = "true"
+		options[ovndb.LBOptionAddressFamily] = templateAddressFamily(svc.Spec.ClusterIP)
+	}
+
+	_, err := r.ensureLoadBalancer(ctx, svc, LBKindClusterIP, protocol, vips, options)
+	return err
+}
+
+// nodePortVIP returns the VIP and backends of a NodePort.
 //
-// NodePort Load Balancers are created with VIPs for each node's IP address.
-// This allows external traffic to reach the Service through any node.
+// NodePort Load Balancers are OVN template Load Balancers with a single VIP
+// per NodePort, "^NODEIP_IPv4:<nodePort>", which every chassis expands to
+// its own node IP (see node_template.go). Adding or removing a node doesn't
+// change the Load Balancer.
 //
 // externalTrafficPolicy handling:
 // - Cluster: Traffic is load balanced to all backends (default)
 // - Local: Traffic is only sent to backends on the same node (preserves source IP)
+func nodePortVIP(svc *corev1.Service, protocol string, port corev1.ServicePort, backends string) (string, string) {
+	if svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		// Each node only routes to its local backends, written by
+		// ensureTemplateVars
+		backends = ovndb.BuildTemplateReference(
+			nodePortTemplateVarName(svc.Namespace, svc.Name, protocol, port.NodePort))
+	}
+
+	// The node IP family follows the ClusterIP
+	family := templateAddressFamily(svc.Spec.ClusterIP)
+	return buildTemplateVIP(nodeIPTemplateVar(family), family, int(port.NodePort)), backends
+}
+
+// ensureNodePortLoadBalancer creates or updates the NodePort Load Balancer
+// of a protocol, holding the VIPs built by nodePortVIP for all the NodePorts
+// of that protocol.
 func (r *ServiceReconciler) ensureNodePortLoadBalancer(
 	ctx context.Context,
 	svc *corev1.Service,
-	port corev1.ServicePort,
-	backends string,
+	protocol string,
+	vips map[string]string,
 ) error {
-	log := klog.FromContext(ctx).WithValues(
-		"service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-		"nodePort", port.NodePort,
-		"protocol", port.Protocol,
-	)
-
-	protocol := strings.ToLower(string(port.Protocol))
-	if protocol == "" {
-		protocol = ovndb.LoadBalancerProtocolTCP
+	family := templateAddressFamily(svc.Spec.ClusterIP)
+
+	// Build options for the Load Balancer; an empty value removes the option
+	options := map[string]string{
+		ovndb.LBOptionSkipSNAT:        "",
+		ovndb.LBOptionAffinityTimeout: sessionAffinityTimeout(svc),
+		ovndb.LBOptionHairpinSNATIP:   r.hairpinSNATIP(),
+		ovndb.LBOptionTemplate:        "true",
+		ovndb.LBOptionAddressFamily:   family,
+	}
+	if svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		// Skip SNAT to preserve source IP for Local policy
+		options[ovndb.LBOptionSkipSNAT] = "true"
 	}
 
-	// Build Load Balancer name for NodePort
-	lbName := buildLoadBalancerName(svc.Namespace, svc.Name, protocol, LBKindNodePort)
+	_, err := r.ensureLoadBalancer(ctx, svc, LBKindNodePort, protocol, vips, options)
+	return err
+}
 
-	// Build external IDs
-	externalIDs := map[string]string{
-		LBExternalIDService:   fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-		LBExternalIDNamespace: svc.Namespace,
-		LBExternalIDKind:      LBKindNodePort,
-		LBExternalIDOwner:     ServiceControllerName,
+// ensureExternalIPLoadBalancers creates or updates the Load Balancers of a
+// Service's spec.externalIPs, one per protocol holding the VIPs of all its
+// ports. The VIPs are replaced on every reconciliation, so removed external
+// IPs disappear; the Load Balancers themselves are deleted by
+// cleanupStaleLoadBalancers once the list is empty.
+//
+// externalTrafficPolicy is handled as for NodePort: with Local, SNAT is
+// skipped, and an external IP that is a node IP only gets the backends on
+// that node.
+func (r *ServiceReconciler) ensureExternalIPLoadBalancers(ctx context.Context, svc *corev1.Service, endpoints []EndpointInfo) error {
+	if len(svc.Spec.ExternalIPs) == 0 {
+		return nil
 	}
 
-	// Check externalTrafficPolicy
+	log := klog.FromContext(ctx).WithValues("service", fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
 	isLocalPolicy := svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal
 
-	// Get all node IPs for NodePort VIPs
-	nodeIPs, err := r.getNodeIPs(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get node IPs: %w", err)
+	var nodeIPs []string
+	if isLocalPolicy {
+		var err error
+		if nodeIPs, err = r.getNodeIPs(ctx); err != nil {
+			return fmt.Errorf("failed to get node IPs: %w", err)
+		}
 	}
 
-	// Build VIPs map with all node IPs
-	vips := map[string]string{}
-	if backends != "" {
-		if isLocalPolicy {
-			// For Local policy, we need to get endpoints per node
-			// and only route to local backends
-			nodeBackends, err := r.getNodeLocalBackends(ctx, svc, port)
-			if err != nil {
+	vipsByProtocol := map[string]map[string]string{}
+	for _, port := range svc.Spec.Ports {
+		protocol := serviceProtocol(port)
+		if vipsByProtocol[protocol] == nil {
+			vipsByProtocol[protocol] = map[string]string{}
+		}
+
+		backends := r.buildBackends(endpoints, port)
+		var nodeBackends map[string]string
+		if isLocalPolicy && backends != "" {
+			var err error
+			if nodeBackends, err = r.getNodeLocalBackends(ctx, svc, port); err != nil {
 				log.V(4).Info("Failed to get node-local backends, falling back to all backends", "error", err)
-				// Fall back to all backends
-				for _, nodeIP := range nodeIPs {
-					vip := ovndb.BuildVIP(nodeIP, int(port.NodePort))
-					vips[vip] = backends
-				}
-			} else {
-				for nodeIP, localBackends := range nodeBackends {
-					if localBackends != "" {
-						vip := ovndb.BuildVIP(nodeIP, int(port.NodePort))
-						vips[vip] = localBackends
-					}
-				}
+				nodeIPs = nil
 			}
-		} else {
-			// For Cluster policy, all nodes route to all backends
-			for _, nodeIP := range nodeIPs {
-				vip := ovndb.BuildVIP(nodeIP, int(port.NodePort))
-				vips[vip] = backends
+		}
+
+		for _, ip := range svc.Spec.ExternalIPs {
+			if b := externalIPBackends(ip, backends, nodeBackends, nodeIPs); b != "" {
+				vipsByProtocol[protocol][ovndb.BuildVIP(ip, int(port.Port))] = b
 			}
 		}
 	}
 
-	// Build options for the Load Balancer
-	options := map[string]string{}
+	options := map[string]string{
+		ovndb.LBOptionSkipSNAT:        "",
+		ovndb.LBOptionAffinityTimeout: sessionAffinityTimeout(svc),
+		ovndb.LBOptionHairpinSNATIP:   r.hairpinSNATIP(),
+	}
 	if isLocalPolicy {
 		// Skip SNAT to preserve source IP for Local policy
 		options[ovndb.LBOptionSkipSNAT] = "true"
 	}
 
-	// Check if Load Balancer exists
-	existingLB, err := r.lbOps.GetLoadBalancer(ctx, lbName)
-	if err != nil && !ovndb.IsNotFound(err) {
-		return fmt.Errorf("failed to get existing NodePort LB: %w", err)
-	}
-
-	if existingLB != nil {
-		// Update existing Load Balancer
-		log.V(4).Info("Updating existing NodePort Load Balancer", "name", lbName, "localPolicy", isLocalPolicy)
-
-		if err := r.lbOps.SetVips(ctx, lbName, vips); err != nil {
-			return fmt.Errorf("failed to update NodePort LB VIPs: %w", err)
-		}
-
-		// Update options if needed
-		if len(options) > 0 {
-			if err := r.lbOps.SetOptions(ctx, lbName, options); err != nil {
-				log.V(4).Info("Failed to update LB options", "error", err)
-			}
-		}
-
-		r.trackLoadBalancer(svc.Namespace, svc.Name, protocol+"_nodeport", existingLB.UUID)
-	} else {
-		// Create new Load Balancer
-		log.Info("Creating new NodePort Load Balancer", "name", lbName, "localPolicy", isLocalPolicy)
-
-		lb, err := r.lbOps.CreateLoadBalancer(ctx, lbName, protocol, vips, options, externalIDs)
-		if err != nil {
-			return fmt.Errorf("failed to create NodePort LB: %w", err)
-		}
-
-		r.trackLoadBalancer(svc.Namespace, svc.Name, protocol+"_nodeport", lb.UUID)
-
-		// Attach Load Balancer to all Logical Switches
-		if err := r.attachLoadBalancerToSwitches(ctx, lb.UUID); err != nil {
-			log.V(4).Info("Failed to attach NodePort LB to switches", "error", err)
+	for protocol, vips := range vipsByProtocol {
+		if _, err := r.ensureLoadBalancer(ctx, svc, LBKindExternalIP, protocol, vips, options); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// externalIPBackends returns the backends of an external IP VIP.
+//
+// nodeIPs is only set for the Local policy. An external IP that is one of
+// them only gets the backends on that node, like a NodePort VIP, and none
+// if the node has no local endpoint. Other external IPs get all backends.
+func externalIPBackends(ip, backends string, nodeBackends map[string]string, nodeIPs []string) string {
+	if containsString(nodeIPs, ip) {
+		return nodeBackends[ip]
+	}
+	return backends
+}
+
 // getNodeLocalBackends returns backends grouped by node IP for Local traffic policy.
 // This is used when externalTrafficPolicy is set to Local.
 func (r *ServiceReconciler) getNodeLocalBackends(ctx context.Context, svc *corev1.Service, port corev1.ServicePort) (map[string]string, error) {
@@ -629,10 +850,10 @@ func (r *ServiceReconciler) getNodeLocalBackends(ctx context.Context, svc *corev
 
 	// Group endpoints by node
 	nodeEndpoints := make(map[string][]string)
-	targetPort := port.TargetPort.IntValue()
 
 	for _, ep := range endpoints {
-		if ep.NodeName == "" {
+		targetPort := endpointTargetPort(ep, port)
+		if ep.NodeName == "" || targetPort == 0 {
 			continue
 		}
 
@@ -694,28 +915,6 @@ func (r *ServiceReconciler) getNodeIPs(ctx context.Context) ([]string, error) {
 	return ips, nil
 }
 
-// attachLoadBalancerToSwitches attaches a Load Balancer to all Logical Switches.
-func (r *ServiceReconciler) attachLoadBalancerToSwitches(ctx context.Context, lbUUID string) error {
-	// List all subnets to get their Logical Switches
-	subnetList := &networkv1.SubnetList{}
-	if err := r.client.List(ctx, subnetList); err != nil {
-		return fmt.Errorf("failed to list subnets: %w", err)
-	}
-
-	for _, subnet := range subnetList.Items {
-		if subnet.Status.Phase != networkv1.SubnetPhaseActive {
-			continue
-		}
-
-		lsName := subnet.GetLogicalSwitchName()
-		if err := r.lsOps.AddLoadBalancersToLogicalSwitch(ctx, lsName, lbUUID); err != nil {
-			klog.V(4).Infof("Failed to attach LB %s to switch %s: %v", lbUUID, lsName, err)
-		}
-	}
-
-	return nil
-}
-
 // trackLoadBalancer tracks a Load Balancer UUID for a Service.
 func (r *ServiceReconciler) trackLoadBalancer(namespace, name, protocol, uuid string) {
 	r.serviceLBsMu.Lock()
@@ -728,6 +927,18 @@ func (r *ServiceReconciler) trackLoadBalancer(namespace, name, protocol, uuid st
 	r.serviceLBs[key][protocol] = uuid
 }
 
+// untrackLoadBalancer stops tracking a deleted Load Balancer of a Service.
+func (r *ServiceReconciler) untrackLoadBalancer(namespace, name, protocol string) {
+	r.serviceLBsMu.Lock()
+	defer r.serviceLBsMu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	delete(r.serviceLBs[key], protocol)
+	if len(r.serviceLBs[key]) == 0 {
+		delete(r.serviceLBs, key)
+	}
+}
+
 // getTrackedLoadBalancers returns tracked Load Balancer UUIDs for a Service.
 func (r *ServiceReconciler) getTrackedLoadBalancers(namespace, name string) map[string]string {
 	r.serviceLBsMu.RLock()
@@ -750,15 +961,20 @@ func (r *ServiceReconciler) cleanupStaleLoadBalancers(ctx context.Context, svc *
 	// Get current protocols from Service
 	currentProtocols := make(map[string]bool)
 	for _, port := range svc.Spec.Ports {
-		protocol := strings.ToLower(string(port.Protocol))
-		if protocol == "" {
-			protocol = ovndb.LoadBalancerProtocolTCP
-		}
+		protocol := serviceProtocol(port)
 		currentProtocols[protocol] = true
 
 		// Also track NodePort protocols if applicable
 		if (svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer) && port.NodePort > 0 {
-			currentProtocols[protocol+"_nodeport"] = true
+			currentProtocols[lbTrackingKey(protocol, LBKindNodePort)] = true
+		}
+
+		// And the Load Balancers of external IPs
+		if len(svc.Spec.ExternalIPs) > 0 {
+			currentProtocols[lbTrackingKey(protocol, LBKindExternalIP)] = true
+		}
+		if managesLoadBalancerIngress(svc) && len(loadBalancerIngressIPs(svc)) > 0 {
+			currentProtocols[lbTrackingKey(protocol, LBKindLoadBalancer)] = true
 		}
 	}
 
@@ -769,17 +985,15 @@ func (r *ServiceReconciler) cleanupStaleLoadBalancers(ctx context.Context, svc *
 	}
 
 	// Delete Load Balancers for protocols that no longer exist
-	for protocol := range trackedLBs {
-		if !currentProtocols[protocol] {
-			kind := LBKindClusterIP
-			if strings.HasSuffix(protocol, "_nodeport") {
-				kind = LBKindNodePort
-				protocol = strings.TrimSuffix(protocol, "_nodeport")
-			}
+	for key := range trackedLBs {
+		if !currentProtocols[key] {
+			protocol, kind := parseLBTrackingKey(key)
 			lbName := buildLoadBalancerName(svc.Namespace, svc.Name, protocol, kind)
 			if err := r.lbOps.DeleteLoadBalancer(ctx, lbName); err != nil && !ovndb.IsNotFound(err) {
 				klog.V(4).Infof("Failed to delete stale LB %s: %v", lbName, err)
+				continue
 			}
+			r.untrackLoadBalancer(svc.Namespace, svc.Name, key)
 		}
 	}
 
@@ -804,12 +1018,8 @@ func (r *ServiceReconciler) handleDeletion(ctx context.Context, namespace, name
 	for _, lb := range lbs {
 		log.V(4).Info("Deleting Load Balancer", "name", lb.Name)
 
-		// First remove from all Logical Switches
-		if err := r.detachLoadBalancerFromSwitches(ctx, lb.UUID); err != nil {
-			log.V(4).Info("Failed to detach LB from switches", "error", err)
-		}
-
-		// Then delete the Load Balancer
+		// The Load Balancer Group and router references are weak, deleting
+		// the Load Balancer removes it from them
 		if err := r.lbOps.DeleteLoadBalancer(ctx, lb.Name); err != nil && !ovndb.IsNotFound(err) {
 			log.Error(err, "Failed to delete Load Balancer", "name", lb.Name)
 		}
@@ -820,41 +1030,57 @@ func (r *ServiceReconciler) handleDeletion(ctx context.Context, namespace, name
 	delete(r.serviceLBs, fmt.Sprintf("%s/%s", namespace, name))
 	r.serviceLBsMu.Unlock()
 
-	log.Info("Service deletion completed")
-	return ctrl.Result{}, nil
-}
-
-// detachLoadBalancerFromSwitches removes a Load Balancer from all Logical Switches.
-func (r *ServiceReconciler) detachLoadBalancerFromSwitches(ctx context.Context, lbUUID string) error {
-	// List all Logical Switches
-	switches, err := r.lsOps.ListLogicalSwitches(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to list switches: %w", err)
+	// Delete the per-node backends
+	if err := r.syncTemplateVars(ctx, templateVarPrefix(namespace, name), nil); err != nil {
+		log.V(4).Info("Failed to delete template variables", "error", err)
 	}
 
-	for _, ls := range switches {
-		// Check if this switch has the Load Balancer
-		for _, lb := range ls.LoadBalancer {
-			if lb == lbUUID {
-				if err := r.lsOps.RemoveLoadBalancersFromLogicalSwitch(ctx, ls.Name, lbUUID); err != nil {
-					klog.V(4).Infof("Failed to remove LB %s from switch %s: %v", lbUUID, ls.Name, err)
-				}
-				break
-			}
-		}
-	}
+	// Release the external IPs
+	r.lbIngressMu.Lock()
+	r.recordLoadBalancerIPs(fmt.Sprintf("%s/%s", namespace, name), nil)
+	r.lbIngressMu.Unlock()
 
-	return nil
+	log.Info("Service deletion completed")
+	return ctrl.Result{}, nil
 }
 
 // buildLoadBalancerName builds the Load Balancer name.
 func buildLoadBalancerName(namespace, name, protocol, kind string) string {
-	if kind == LBKindNodePort {
+	switch kind {
+	case LBKindNodePort:
 		return fmt.Sprintf("Service_%s/%s_%s_nodeport", namespace, name, protocol)
+	case LBKindExternalIP:
+		return fmt.Sprintf("Service_%s/%s_%s_externalip", namespace, name, protocol)
+	case LBKindLoadBalancer:
+		return fmt.Sprintf("Service_%s/%s_%s_lb", namespace, name, protocol)
 	}
 	return fmt.Sprintf("Service_%s/%s_%s", namespace, name, protocol)
 }
 
+// serviceProtocol returns the OVN Load Balancer protocol of a Service port.
+func serviceProtocol(port corev1.ServicePort) string {
+	switch port.Protocol {
+	case corev1.ProtocolUDP:
+		return ovndb.LoadBalancerProtocolUDP
+	case corev1.ProtocolSCTP:
+		return ovndb.LoadBalancerProtocolSCTP
+	default:
+		return ovndb.LoadBalancerProtocolTCP
+	}
+}
+
+// servicePortsByProtocol groups the ports of a Service by OVN Load Balancer
+// protocol. Ports sharing a number but not a protocol, like the TCP and UDP
+// ports of DNS, end up in different groups.
+func servicePortsByProtocol(svc *corev1.Service) map[string][]corev1.ServicePort {
+	result := make(map[string][]corev1.ServicePort)
+	for _, port := range svc.Spec.Ports {
+		protocol := serviceProtocol(port)
+		result[protocol] = append(result[protocol], port)
+	}
+	return result
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -863,6 +1089,15 @@ func (r *ServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&discoveryv1.EndpointSlice{},
 			handler.EnqueueRequestsFromMapFunc(r.endpointSliceToService),
 		).
+		Watches(
+			&networkv1.LoadBalancerIPPool{},
+			handler.EnqueueRequestsFromMapFunc(r.loadBalancerIPPoolToServices),
+		).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.nodeToServices),
+			builder.WithPredicates(nodeTopologyChanged),
+		).
 		Named(ServiceControllerName).
 		Complete(r)
 }
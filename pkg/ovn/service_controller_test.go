@@ -0,0 +1,173 @@
+package ovn
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+func TestSessionAffinityTimeout(t *testing.T) {
+	timeout := int32(600)
+
+	tests := []struct {
+		name     string
+		spec     corev1.ServiceSpec
+		expected string
+	}{
+		{"no affinity", corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityNone}, ""},
+		{"ClientIP with default timeout", corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}, "10800"},
+		{"ClientIP with timeout", corev1.ServiceSpec{
+			SessionAffinity: corev1.ServiceAffinityClientIP,
+			SessionAffinityConfig: &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+			},
+		}, "600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Spec: tt.spec}
+			if got := sessionAffinityTimeout(svc); got != tt.expected {
+				t.Errorf("sessionAffinityTimeout() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExternalIPBackends(t *testing.T) {
+	all := "10.244.1.5:8080,10.244.2.6:8080"
+	nodeBackends := map[string]string{"192.168.1.10": "10.244.1.5:8080"}
+	nodeIPs := []string{"192.168.1.10", "192.168.1.11"}
+
+	tests := []struct {
+		name     string
+		ip       string
+		nodeIPs  []string
+		expected string
+	}{
+		{"Cluster policy", "192.168.1.10", nil, all},
+		{"Local policy on a node with endpoints", "192.168.1.10", nodeIPs, "10.244.1.5:8080"},
+		{"Local policy on a node without endpoints", "192.168.1.11", nodeIPs, ""},
+		{"Local policy on a non-node IP", "203.0.113.10", nodeIPs, all},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := externalIPBackends(tt.ip, all, nodeBackends, tt.nodeIPs); got != tt.expected {
+				t.Errorf("externalIPBackends(%q) = %q, want %q", tt.ip, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEndpointTargetPort(t *testing.T) {
+	named := corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("http")}
+	numeric := corev1.ServicePort{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt(9100)}
+
+	tests := []struct {
+		name     string
+		ep       EndpointInfo
+		port     corev1.ServicePort
+		expected int
+	}{
+		{"named port resolved", EndpointInfo{Ports: map[string]int{"http": 8080}}, named, 8080},
+		{"named port on another number", EndpointInfo{Ports: map[string]int{"http": 8081}}, named, 8081},
+		{"named port not resolved", EndpointInfo{Ports: map[string]int{"metrics": 9100}}, named, 0},
+		{"numeric port from slice", EndpointInfo{Ports: map[string]int{"metrics": 9100}}, numeric, 9100},
+		{"numeric port without slice ports", EndpointInfo{}, numeric, 9100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := endpointTargetPort(tt.ep, tt.port); got != tt.expected {
+				t.Errorf("endpointTargetPort() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildLoadBalancerIndex(t *testing.T) {
+	udp := ovndb.LoadBalancerProtocolUDP
+	lb := func(uuid, service, kind string, protocol *string) *ovndb.LoadBalancer {
+		return &ovndb.LoadBalancer{
+			UUID:     uuid,
+			Protocol: protocol,
+			ExternalIDs: map[string]string{
+				LBExternalIDService: service,
+				LBExternalIDKind:    kind,
+				LBExternalIDOwner:   ServiceControllerName,
+			},
+		}
+	}
+
+	index := buildLoadBalancerIndex([]*ovndb.LoadBalancer{
+		lb("lb-1", "default/web", LBKindClusterIP, nil),
+		lb("lb-2", "default/web", LBKindNodePort, nil),
+		lb("lb-3", "default/web", LBKindLoadBalancer, &udp),
+		lb("lb-4", "kube-system/dns", LBKindExternalIP, &udp),
+		lb("lb-5", "", LBKindClusterIP, nil),
+	})
+
+	expected := map[string]map[string]string{
+		"default/web":     {"tcp": "lb-1", "tcp_nodeport": "lb-2", "udp_lb": "lb-3"},
+		"kube-system/dns": {"udp_externalip": "lb-4"},
+	}
+	if !reflect.DeepEqual(index, expected) {
+		t.Fatalf("buildLoadBalancerIndex() = %v, want %v", index, expected)
+	}
+
+	for _, tracked := range index {
+		for key := range tracked {
+			protocol, kind := parseLBTrackingKey(key)
+			if got := lbTrackingKey(protocol, kind); got != key {
+				t.Errorf("lbTrackingKey(parseLBTrackingKey(%q)) = %q", key, got)
+			}
+		}
+	}
+}
+
+func TestServicePortsByProtocol(t *testing.T) {
+	dns := corev1.ServicePort{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP}
+	dnsTCP := corev1.ServicePort{Name: "dns-tcp", Port: 53, Protocol: corev1.ProtocolTCP}
+	metrics := corev1.ServicePort{Name: "metrics", Port: 9153, Protocol: corev1.ProtocolTCP}
+	sctp := corev1.ServicePort{Name: "sctp", Port: 9999, Protocol: corev1.ProtocolSCTP}
+	unset := corev1.ServicePort{Name: "http", Port: 80}
+
+	tests := []struct {
+		name     string
+		ports    []corev1.ServicePort
+		expected map[string][]corev1.ServicePort
+	}{
+		{
+			name:  "mixed TCP and UDP on one port number",
+			ports: []corev1.ServicePort{dns, dnsTCP, metrics},
+			expected: map[string][]corev1.ServicePort{
+				ovndb.LoadBalancerProtocolUDP: {dns},
+				ovndb.LoadBalancerProtocolTCP: {dnsTCP, metrics},
+			},
+		},
+		{
+			name:     "SCTP",
+			ports:    []corev1.ServicePort{sctp},
+			expected: map[string][]corev1.ServicePort{ovndb.LoadBalancerProtocolSCTP: {sctp}},
+		},
+		{
+			name:     "protocol defaults to TCP",
+			ports:    []corev1.ServicePort{unset},
+			expected: map[string][]corev1.ServicePort{ovndb.LoadBalancerProtocolTCP: {unset}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Spec: corev1.ServiceSpec{Ports: tt.ports}}
+			if got := servicePortsByProtocol(svc); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("servicePortsByProtocol() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
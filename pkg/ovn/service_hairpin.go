@@ -0,0 +1,38 @@
+// Package ovn provides the hairpin SNAT handling of Services.
+//
+// A Pod reaching a Service VIP may be load balanced to itself (hairpin).
+// OVN then SNATs the packet so the reply goes back through the Load
+// Balancer; by default the source becomes the VIP. The controller sets an
+// explicit source instead, config.NetworkConfig.ServiceHairpinSNATIP:
+// - Every Service Load Balancer gets options:hairpin_snat_ip
+// - NB_Global options:hairpin_snat_ip records the cluster-wide address
+// - NetworkPolicy ingress ACLs allow the address (pods may reach themselves)
+//
+// Reference: OVN-Kubernetes pkg/ovn/controller/services/lb_config.go (hairpin_snat_ip)
+package ovn
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+// SyncHairpinSNATIP records the hairpin SNAT IP in the NB_Global options,
+// removing it when hairpin SNAT falls back to the OVN default. The Load
+// Balancers themselves are updated as Services are reconciled.
+//
+// It is meant to run once on the elected leader (see manager.RunnableFunc).
+func (r *ServiceReconciler) SyncHairpinSNATIP(ctx context.Context) error {
+	hairpinIP := r.hairpinSNATIP()
+	if err := r.nbgOps.SetOptions(ctx, map[string]string{
+		ovndb.NBGlobalOptionHairpinSNATIP: hairpinIP,
+	}); err != nil {
+		return fmt.Errorf("failed to set NB_Global hairpin SNAT IP: %w", err)
+	}
+
+	klog.Infof("Service hairpin SNAT IP synced: %q", hairpinIP)
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package ovn provides the Service Load Balancer state rebuild on controller
+// startup.
+//
+// The ServiceReconciler tracks the Load Balancer UUIDs of every Service in
+// memory, and cleanupStaleLoadBalancers only deletes Load Balancers it
+// tracks. After a controller restart or a leader failover the index starts
+// empty, so before any Service is reconciled it is rebuilt from OVN:
+//
+// 1. Service controller Load Balancers are indexed by their external_ids
+// 2. Load Balancers whose Service no longer exists are deleted
+//
+// The ServiceReconciler requeues every request until the rebuild has
+// finished.
+//
+// Reference: OVN-Kubernetes pkg/ovn/controller/services/repair.go
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+)
+
+const (
+	// lbSyncRequeueDelay is the requeue delay while the Load Balancer index
+	// is rebuilt
+	lbSyncRequeueDelay = 2 * time.Second
+)
+
+// SyncLoadBalancers rebuilds the Load Balancer index from OVN, deletes the
+// Load Balancers of Services that no longer exist and then unblocks Service
+// reconciliation.
+//
+// It is meant to run once on the elected leader (see manager.RunnableFunc).
+// A failed rebuild is returned to the manager, which stops the process.
+func (r *ServiceReconciler) SyncLoadBalancers(ctx context.Context) error {
+	lbs, err := r.lbOps.ListLoadBalancersWithPredicate(ctx, func(lb *ovndb.LoadBalancer) bool {
+		return lb.ExternalIDs[LBExternalIDOwner] == ServiceControllerName
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list Load Balancers: %w", err)
+	}
+
+	index := buildLoadBalancerIndex(lbs)
+	r.serviceLBsMu.Lock()
+	for key, tracked := range index {
+		if r.serviceLBs[key] == nil {
+			r.serviceLBs[key] = make(map[string]string)
+		}
+		for trackingKey, uuid := range tracked {
+			r.serviceLBs[key][trackingKey] = uuid
+		}
+	}
+	r.serviceLBsMu.Unlock()
+
+	stale := 0
+	for key := range index {
+		namespace, name, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+
+		svc := &corev1.Service{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, svc)
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Service %s: %w", key, err)
+		}
+
+		stale++
+		if _, err := r.handleDeletion(ctx, namespace, name); err != nil {
+			return fmt.Errorf("failed to delete Load Balancers of Service %s: %w", key, err)
+		}
+	}
+
+	r.lbIndexSynced.Store(true)
+	klog.Infof("Service Load Balancers synced: %d Services indexed, %d deleted Services cleaned up", len(index), stale)
+	return nil
+}
+
+// LoadBalancersSynced returns true once the Load Balancer index has been
+// rebuilt from OVN, and Services may be reconciled.
+func (r *ServiceReconciler) LoadBalancersSynced() bool {
+	return r.lbIndexSynced.Load()
+}
+
+// buildLoadBalancerIndex indexes Service Load Balancers the way
+// trackLoadBalancer does.
+//
+// Returns:
+//   - map[string]map[string]string: namespace/name -> tracking key -> LB UUID
+func buildLoadBalancerIndex(lbs []*ovndb.LoadBalancer) map[string]map[string]string {
+	index := make(map[string]map[string]string)
+	for _, lb := range lbs {
+		key := lb.ExternalIDs[LBExternalIDService]
+		if key == "" {
+			continue
+		}
+
+		protocol := ovndb.LoadBalancerProtocolTCP
+		if lb.Protocol != nil && *lb.Protocol != "" {
+			protocol = *lb.Protocol
+		}
+
+		if index[key] == nil {
+			index[key] = make(map[string]string)
+		}
+		index[key][lbTrackingKey(protocol, lb.ExternalIDs[LBExternalIDKind])] = lb.UUID
+	}
+	return index
+}
+
+// lbTrackingKey returns the key a Load Balancer of a kind is tracked under
+// (e.g., "tcp" for ClusterIP, "tcp_nodeport" for NodePort).
+func lbTrackingKey(protocol, kind string) string {
+	switch kind {
+	case LBKindNodePort:
+		return protocol + "_nodeport"
+	case LBKindExternalIP:
+		return protocol + "_externalip"
+	case LBKindLoadBalancer:
+		return protocol + "_lb"
+	default:
+		return protocol
+	}
+}
+
+// parseLBTrackingKey splits a tracking key into protocol and kind, the
+// reverse of lbTrackingKey.
+func parseLBTrackingKey(key string) (protocol, kind string) {
+	for suffix, kind := range map[string]string{
+		"_nodeport":   LBKindNodePort,
+		"_externalip": LBKindExternalIP,
+		"_lb":         LBKindLoadBalancer,
+	} {
+		if strings.HasSuffix(key, suffix) {
+			return strings.TrimSuffix(key, suffix), kind
+		}
+	}
+	return key, LBKindClusterIP
+}
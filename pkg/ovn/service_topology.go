@@ -0,0 +1,253 @@
+// Package ovn provides node-dependent Service backends.
+//
+// Some Services don't send a client to the same backends on every node:
+// - internalTrafficPolicy: Local only uses the endpoints on the client's node
+// - Topology Aware Routing prefers the endpoints hinted for the client's zone
+// - externalTrafficPolicy: Local NodePorts only use the endpoints on the node
+//
+// Pod Logical Switches span nodes, so this can't be expressed with per-switch
+// Load Balancers. Instead, the ClusterIP Load Balancer of such a Service is
+// an OVN template Load Balancer: each VIP points to a Chassis_Template_Var
+// variable ("^SVC_..."), and the row of every node's chassis holds the
+// backends of that node. NodePort Load Balancers are always templates (see
+// node_template.go); with the Local policy their backends are variables too.
+//
+// Reference: kube-proxy pkg/proxy/topology.go (CategorizeEndpoints)
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// usesNodeBackends returns true if the backends of a Service depend on the
+// node of the client.
+func usesNodeBackends(svc *corev1.Service, endpoints []EndpointInfo) bool {
+	return internalTrafficLocal(svc) || topologyHintsUsable(svc, endpoints)
+}
+
+// internalTrafficLocal returns true for internalTrafficPolicy: Local.
+func internalTrafficLocal(svc *corev1.Service) bool {
+	policy := svc.Spec.InternalTrafficPolicy
+	return policy != nil && *policy == corev1.ServiceInternalTrafficPolicyLocal
+}
+
+// topologyHintsUsable returns true if Topology Aware Routing is enabled on a
+// Service and every endpoint carries zone hints. As in kube-proxy, hints
+// are ignored unless all endpoints have them.
+func topologyHintsUsable(svc *corev1.Service, endpoints []EndpointInfo) bool {
+	mode := svc.Annotations[corev1.AnnotationTopologyMode]
+	if mode == "" {
+		mode = svc.Annotations[corev1.DeprecatedAnnotationTopologyAwareHints]
+	}
+	if mode == "" || strings.EqualFold(mode, "disabled") {
+		return false
+	}
+
+	if len(endpoints) == 0 {
+		return false
+	}
+	for _, ep := range endpoints {
+		if len(ep.ForZones) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeEndpoints returns the endpoints a client on a node is sent to.
+//
+// With internalTrafficPolicy: Local, only the endpoints on the node are
+// used. With usable topology hints, the endpoints hinted for the node's
+// zone are used, or all endpoints if none is. Otherwise all endpoints are
+// used.
+//
+// Parameters:
+//   - svc: The Service
+//   - endpoints: Ready endpoints of the Service
+//   - nodeName: Name of the client's node
+//   - zone: Zone of the client's node (topology.kubernetes.io/zone)
+//
+// Returns:
+//   - []EndpointInfo: Endpoints to use as backends on the node
+func nodeEndpoints(svc *corev1.Service, endpoints []EndpointInfo, nodeName, zone string) []EndpointInfo {
+	if internalTrafficLocal(svc) {
+		return localEndpoints(endpoints, nodeName)
+	}
+
+	if zone == "" || !topologyHintsUsable(svc, endpoints) {
+		return endpoints
+	}
+
+	var inZone []EndpointInfo
+	for _, ep := range endpoints {
+		if containsString(ep.ForZones, zone) {
+			inZone = append(inZone, ep)
+		}
+	}
+	if len(inZone) == 0 {
+		return endpoints
+	}
+	return inZone
+}
+
+// templateVarPrefix returns the prefix of a Service's template variables.
+// Template variable names must be valid OVN identifiers, so the Service is
+// hashed.
+func templateVarPrefix(namespace, name string) string {
+	h := fnv.New64a()
+	h.Write([]byte(namespace + "/" + name))
+	return fmt.Sprintf("SVC_%x_", h.Sum64())
+}
+
+// templateVarName returns the template variable holding the backends of a
+// Service port (e.g., "SVC_1f2e3d4c5b6a7988_tcp_80").
+func templateVarName(namespace, name, protocol string, port int32) string {
+	return fmt.Sprintf("%s%s_%d", templateVarPrefix(namespace, name), protocol, port)
+}
+
+// templateAddressFamily returns the address-family option of a template
+// Load Balancer for a VIP.
+func templateAddressFamily(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// nodePortLocal returns true for NodePort and LoadBalancer Services with
+// externalTrafficPolicy: Local, whose NodePorts only use the endpoints on
+// the node receiving the traffic.
+func nodePortLocal(svc *corev1.Service) bool {
+	if svc.Spec.Type != corev1.ServiceTypeNodePort && svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return false
+	}
+	return svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal
+}
+
+// localEndpoints returns the endpoints on a node.
+func localEndpoints(endpoints []EndpointInfo, nodeName string) []EndpointInfo {
+	var local []EndpointInfo
+	for _, ep := range endpoints {
+		if ep.NodeName == nodeName {
+			local = append(local, ep)
+		}
+	}
+	return local
+}
+
+// nodePortTemplateVarName returns the template variable holding the
+// node-local backends of a NodePort (e.g., "SVC_1f2e3d4c5b6a7988_tcp_nodeport_30080").
+func nodePortTemplateVarName(namespace, name, protocol string, nodePort int32) string {
+	return fmt.Sprintf("%s%s_nodeport_%d", templateVarPrefix(namespace, name), protocol, nodePort)
+}
+
+// ensureTemplateVars writes the per-node backends of a Service to the
+// Chassis_Template_Var rows of the nodes, and deletes the variables of the
+// Service that are no longer used (removed ports, removed nodes, or a
+// Service that no longer uses node backends).
+func (r *ServiceReconciler) ensureTemplateVars(ctx context.Context, svc *corev1.Service, endpoints []EndpointInfo) error {
+	desired := map[string]map[string]string{}
+	clusterIP := usesNodeBackends(svc, endpoints)
+	nodePort := nodePortLocal(svc)
+
+	if clusterIP || nodePort {
+		nodeList := &corev1.NodeList{}
+		if err := r.client.List(ctx, nodeList); err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+
+		for _, node := range nodeList.Items {
+			chassis := lookupNodeChassis(ctx, r.tvOps, node.Name)
+			eps := nodeEndpoints(svc, endpoints, node.Name, node.Labels[corev1.LabelTopologyZone])
+			local := localEndpoints(endpoints, node.Name)
+
+			vars := map[string]string{}
+			for _, port := range svc.Spec.Ports {
+				if clusterIP {
+					name := templateVarName(svc.Namespace, svc.Name, serviceProtocol(port), port.Port)
+					vars[name] = r.buildBackends(eps, port)
+				}
+				if nodePort && port.NodePort > 0 {
+					name := nodePortTemplateVarName(svc.Namespace, svc.Name, serviceProtocol(port), port.NodePort)
+					vars[name] = r.buildBackends(local, port)
+				}
+			}
+			desired[chassis] = vars
+		}
+	}
+
+	return r.syncTemplateVars(ctx, templateVarPrefix(svc.Namespace, svc.Name), desired)
+}
+
+// syncTemplateVars makes the template variables with a prefix match the
+// desired ones on every chassis.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - prefix: Prefix of the variables owned by the caller
+//   - desired: Chassis name -> variable name -> value
+func (r *ServiceReconciler) syncTemplateVars(ctx context.Context, prefix string, desired map[string]map[string]string) error {
+	for chassis, vars := range desired {
+		if err := r.tvOps.SetTemplateVariables(ctx, chassis, vars); err != nil {
+			return fmt.Errorf("failed to set template variables of chassis %s: %w", chassis, err)
+		}
+	}
+
+	rows, err := r.tvOps.ListChassisTemplateVars(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		var stale []string
+		for name := range row.Variables {
+			if _, ok := desired[row.Chassis][name]; !ok && strings.HasPrefix(name, prefix) {
+				stale = append(stale, name)
+			}
+		}
+		if err := r.tvOps.DeleteTemplateVariables(ctx, row.Chassis, stale...); err != nil {
+			return fmt.Errorf("failed to delete template variables of chassis %s: %w", row.Chassis, err)
+		}
+	}
+	return nil
+}
+
+// nodeToServices maps Node events to the Services with node-dependent
+// backends, including NodePorts with externalTrafficPolicy: Local.
+func (r *ServiceReconciler) nodeToServices(ctx context.Context, _ client.Object) []reconcile.Request {
+	svcList := &corev1.ServiceList{}
+	if err := r.client.List(ctx, svcList); err != nil {
+		klog.Errorf("Failed to list Services for Node event: %v", err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, svc := range svcList.Items {
+		if !internalTrafficLocal(&svc) && !nodePortLocal(&svc) && svc.Annotations[corev1.AnnotationTopologyMode] == "" &&
+			svc.Annotations[corev1.DeprecatedAnnotationTopologyAwareHints] == "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&svc),
+		})
+	}
+	return requests
+}
+
+// nodeTopologyChanged filters Node events down to node additions, removals
+// and zone changes, the only ones changing node backends.
+var nodeTopologyChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetLabels()[corev1.LabelTopologyZone] != e.ObjectNew.GetLabels()[corev1.LabelTopologyZone]
+	},
+}
@@ -0,0 +1,50 @@
+package ovn
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeEndpoints(t *testing.T) {
+	local := corev1.ServiceInternalTrafficPolicyLocal
+	cluster := &corev1.Service{}
+	itpLocal := &corev1.Service{Spec: corev1.ServiceSpec{InternalTrafficPolicy: &local}}
+	topology := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{corev1.AnnotationTopologyMode: "Auto"},
+	}}
+
+	a := EndpointInfo{Address: "10.244.1.5", NodeName: "node-1", ForZones: []string{"zone-a"}}
+	b := EndpointInfo{Address: "10.244.2.6", NodeName: "node-2", ForZones: []string{"zone-b"}}
+	unhinted := EndpointInfo{Address: "10.244.3.7", NodeName: "node-3"}
+	hinted := []EndpointInfo{a, b}
+
+	tests := []struct {
+		name      string
+		svc       *corev1.Service
+		endpoints []EndpointInfo
+		node      string
+		zone      string
+		expected  []EndpointInfo
+	}{
+		{"cluster policy", cluster, hinted, "node-1", "zone-a", hinted},
+		{"local policy", itpLocal, hinted, "node-2", "zone-a", []EndpointInfo{b}},
+		{"local policy without local endpoints", itpLocal, hinted, "node-3", "", nil},
+		{"topology hints for the zone", topology, hinted, "node-3", "zone-a", []EndpointInfo{a}},
+		{"topology without endpoints for the zone", topology, hinted, "node-3", "zone-c", hinted},
+		{"topology on a node without zone", topology, hinted, "node-3", "", hinted},
+		{"topology with an unhinted endpoint", topology, []EndpointInfo{a, unhinted}, "node-3", "zone-a", []EndpointInfo{a, unhinted}},
+		{"hints without topology annotation", cluster, hinted, "node-3", "zone-a", hinted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nodeEndpoints(tt.svc, tt.endpoints, tt.node, tt.zone)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("nodeEndpoints() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
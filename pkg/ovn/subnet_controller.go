@@ -9,6 +9,8 @@
 // The controller is responsible for:
 // - Creating/updating OVN Logical Switches for new Subnets
 // - Validating external Logical Switch references
+// - Attaching underlay Subnets to their provider network (localnet port)
+// - Configuring OVN native DHCP for Subnets with EnableDHCP
 // - Initializing IP allocators for each subnet
 // - Updating Subnet status with IP availability information
 // - Cleaning up OVN resources when Subnets are deleted
@@ -19,8 +21,10 @@ package ovn
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +38,8 @@ import (
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/allocator"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/config"
 	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/ovndb"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/types"
+	"github.com/jiayi-1994/zstack-ovn-kubernetes/pkg/util"
 )
 
 const (
@@ -58,9 +64,18 @@ type SubnetReconciler struct {
 	config       *config.Config
 	ovnClient    *ovndb.Client
 	lsOps        *ovndb.LogicalSwitchOps
+	lrOps        *ovndb.LogicalRouterOps
+	lrpOps       *ovndb.LogicalRouterPortOps
+	lspOps       *ovndb.LogicalSwitchPortOps
+	dhcpOps      *ovndb.DHCPOptionsOps
+	lbgOps       *ovndb.LoadBalancerGroupOps
 	zstackCompat *ovndb.ZStackCompatibility
-	allocators   map[string]*allocator.SubnetAllocator
+	allocators   map[string]*allocator.DualStackAllocator
 	allocatorsMu sync.RWMutex
+
+	// allocatorsSynced is set once the AllocatorSyncer rebuilt the
+	// allocators from cluster state; no IP is allocated before that
+	allocatorsSynced atomic.Bool
 }
 
 // NewSubnetReconciler creates a new SubnetReconciler.
@@ -78,8 +93,13 @@ func NewSubnetReconciler(
 		config:       cfg,
 		ovnClient:    ovnClient,
 		lsOps:        ovndb.NewLogicalSwitchOps(ovnClient),
+		lrOps:        ovndb.NewLogicalRouterOps(ovnClient),
+		lrpOps:       ovndb.NewLogicalRouterPortOps(ovnClient),
+		lspOps:       ovndb.NewLogicalSwitchPortOps(ovnClient),
+		dhcpOps:      ovndb.NewDHCPOptionsOps(ovnClient),
+		lbgOps:       ovndb.NewLoadBalancerGroupOps(ovnClient),
 		zstackCompat: ovndb.NewZStackCompatibility(ovnClient),
-		allocators:   make(map[string]*allocator.SubnetAllocator),
+		allocators:   make(map[string]*allocator.DualStackAllocator),
 	}
 }
 
@@ -131,6 +151,13 @@ func (r *SubnetReconciler) reconcileSubnet(ctx context.Context, subnet *networkv
 		return ctrl.Result{}, err
 	}
 
+	// Resize the allocator before touching OVN, so that a Subnet shrunk
+	// below its allocated IPs leaves the Logical Switch unchanged
+	if err := r.ensureIPAllocator(subnet); err != nil {
+		log.Error(err, "Failed to initialize IP allocator")
+		return ctrl.Result{}, err
+	}
+
 	lsName := subnet.GetLogicalSwitchName()
 	log.V(4).Info("Processing Logical Switch", "name", lsName)
 
@@ -142,10 +169,27 @@ func (r *SubnetReconciler) reconcileSubnet(ctx context.Context, subnet *networkv
 		if err := r.ensureLogicalSwitch(ctx, subnet, lsName); err != nil {
 			return ctrl.Result{}, err
 		}
+		if subnet.IsUnderlayMode() {
+			if err := r.ensureUnderlayNetwork(ctx, subnet, lsName); err != nil {
+				log.Error(err, "Failed to connect Logical Switch to provider network")
+				return ctrl.Result{}, err
+			}
+		} else {
+			if err := r.ensureClusterRouterPort(ctx, subnet, lsName); err != nil {
+				log.Error(err, "Failed to connect Logical Switch to cluster router")
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
-	if err := r.ensureIPAllocator(subnet); err != nil {
-		log.Error(err, "Failed to initialize IP allocator")
+	// Services reach the switch through the cluster Load Balancer Group
+	if err := r.ensureSwitchLoadBalancerGroup(ctx, lsName); err != nil {
+		log.Error(err, "Failed to attach Load Balancer group")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureDHCPOptions(ctx, subnet, lsName); err != nil {
+		log.Error(err, "Failed to configure DHCP")
 		return ctrl.Result{}, err
 	}
 
@@ -161,22 +205,65 @@ func (r *SubnetReconciler) reconcileSubnet(ctx context.Context, subnet *networkv
 }
 
 func (r *SubnetReconciler) validateSubnet(subnet *networkv1.Subnet) error {
-	if subnet.Spec.CIDR == "" {
+	cidrs := subnet.CIDRs()
+	if len(cidrs) == 0 {
 		return fmt.Errorf("CIDR is required")
 	}
 
-	_, err := allocator.NewSubnetAllocator(subnet.Spec.CIDR, subnet.Spec.ExcludeIPs)
+	alloc, err := allocator.NewDualStackAllocator(cidrs, subnet.Spec.ExcludeIPs)
 	if err != nil {
 		return fmt.Errorf("invalid subnet configuration: %w", err)
 	}
 
-	if subnet.Spec.Gateway == "" {
+	gateways := subnet.Gateways()
+	if len(gateways) == 0 {
 		return fmt.Errorf("gateway is required")
 	}
+	if len(gateways) != len(cidrs) {
+		return fmt.Errorf("expected one gateway per CIDR, got %d gateways for %d CIDRs", len(gateways), len(cidrs))
+	}
+	for i, cidr := range cidrs {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		gw := net.ParseIP(gateways[i])
+		if gw == nil || !ipNet.Contains(gw) {
+			return fmt.Errorf("gateway %s is not in CIDR %s", gateways[i], cidr)
+		}
+	}
+
+	for _, hcIP := range subnet.HealthCheckIPs() {
+		ip := net.ParseIP(hcIP)
+		if ip == nil || !alloc.Contains(ip) {
+			return fmt.Errorf("health check IP %s is not in CIDR %s", hcIP, subnet.Spec.CIDR)
+		}
+		if containsString(gateways, ip.String()) {
+			return fmt.Errorf("health check IP %s is a gateway", hcIP)
+		}
+	}
+
+	if subnet.IsUnderlayMode() && !subnet.IsExternalMode() && !r.config.Gateway.HasProvider(underlayProvider(subnet)) {
+		return fmt.Errorf("provider %s is not in gateway.providerNetworks", underlayProvider(subnet))
+	}
+
+	if protocol := subnetProtocol(alloc); subnet.Spec.Protocol != "" && subnet.Spec.Protocol != protocol {
+		return fmt.Errorf("protocol %s does not match CIDR %s (%s)", subnet.Spec.Protocol, subnet.Spec.CIDR, protocol)
+	}
 
 	return nil
 }
 
+// subnetProtocol returns the protocol matching the families of an allocator
+func subnetProtocol(alloc *allocator.DualStackAllocator) networkv1.SubnetProtocol {
+	families := alloc.Families()
+	switch {
+	case len(families) > 1:
+		return networkv1.SubnetProtocolDual
+	case families[0].IsIPv6():
+		return networkv1.SubnetProtocolIPv6
+	default:
+		return networkv1.SubnetProtocolIPv4
+	}
+}
+
 // verifyExternalLogicalSwitch verifies that an external Logical Switch exists.
 // In external mode, this validates that the referenced ZStack Logical Switch
 // exists and can be used by zstack-ovn-kubernetes.
@@ -225,8 +312,15 @@ func (r *SubnetReconciler) ensureLogicalSwitch(ctx context.Context, subnet *netw
 	log := klog.FromContext(ctx).WithValues("subnet", subnet.Name, "logicalSwitch", lsName)
 	log.V(4).Info("Ensuring Logical Switch exists")
 
-	otherConfig := map[string]string{
-		"subnet": subnet.Spec.CIDR,
+	// OVN only takes one IPv4 "subnet" for dynamic addresses. Only the
+	// primary IPv4 range is given; IPv6 addresses and those of the other
+	// ranges are always static.
+	otherConfig := map[string]string{}
+	for _, cidr := range subnet.CIDRs() {
+		if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() != nil {
+			otherConfig["subnet"] = cidr
+			break
+		}
 	}
 	if len(subnet.Spec.ExcludeIPs) > 0 {
 		otherConfig["exclude_ips"] = strings.Join(subnet.Spec.ExcludeIPs, " ")
@@ -296,29 +390,125 @@ func (r *SubnetReconciler) ensureLogicalSwitch(ctx context.Context, subnet *netw
 	return nil
 }
 
+// ensureClusterRouterPort attaches the Subnet's Logical Switch to the cluster
+// router. The router port owns every gateway in Spec.Gateway (one network per
+// CIDR), which is why the gateways are always excluded from the IP
+// allocator.
+func (r *SubnetReconciler) ensureClusterRouterPort(ctx context.Context, subnet *networkv1.Subnet, lsName string) error {
+	log := klog.FromContext(ctx).WithValues("subnet", subnet.Name, "logicalSwitch", lsName)
+
+	if err := r.lrOps.EnsureClusterRouter(ctx); err != nil {
+		return fmt.Errorf("failed to ensure cluster router: %w", err)
+	}
+	if err := r.ensureRouterLoadBalancerGroup(ctx, ovndb.ClusterRouterName); err != nil {
+		return fmt.Errorf("failed to attach Load Balancer group to cluster router: %w", err)
+	}
+
+	cidrs := subnet.CIDRs()
+	gateways := subnet.Gateways()
+	if len(cidrs) == 0 || len(gateways) != len(cidrs) {
+		return fmt.Errorf("expected one gateway per CIDR in subnet %s", subnet.Name)
+	}
+
+	networks := make([]string, 0, len(cidrs))
+	for i, cidr := range cidrs {
+		network, err := ovndb.BuildRouterPortNetwork(gateways[i], cidr)
+		if err != nil {
+			return fmt.Errorf("invalid gateway for router port: %w", err)
+		}
+		networks = append(networks, network)
+	}
+
+	mac := util.GenerateMAC(net.ParseIP(gateways[0]))
+	externalIDs := map[string]string{
+		ExternalIDSubnetName: subnet.Name,
+		ExternalIDManagedBy:  ExternalIDManagedByValue,
+	}
+
+	if err := r.lrpOps.ConnectLogicalSwitch(ctx, ovndb.ClusterRouterName, lsName, mac, networks, externalIDs); err != nil {
+		return fmt.Errorf("failed to connect Logical Switch to cluster router: %w", err)
+	}
+
+	// The Subnet may have been an underlay Subnet before
+	if err := r.lspOps.DeleteLogicalSwitchPort(ctx, lsName, ovndb.BuildLocalnetPortName(lsName)); err != nil {
+		return fmt.Errorf("failed to delete localnet port: %w", err)
+	}
+
+	log.V(4).Info("Logical Switch connected to cluster router", "router", ovndb.ClusterRouterName, "networks", networks)
+	return nil
+}
+
+// ensureUnderlayNetwork attaches the Subnet's Logical Switch to its provider
+// network through a localnet port tagged with Spec.VlanID. Pods of an
+// underlay Subnet sit directly on the physical network and use its router
+// as gateway, so the switch is detached from the cluster router.
+func (r *SubnetReconciler) ensureUnderlayNetwork(ctx context.Context, subnet *networkv1.Subnet, lsName string) error {
+	log := klog.FromContext(ctx).WithValues("subnet", subnet.Name, "logicalSwitch", lsName)
+
+	if err := r.lrpOps.DisconnectLogicalSwitch(ctx, ovndb.ClusterRouterName, lsName); err != nil {
+		return fmt.Errorf("failed to disconnect Logical Switch from cluster router: %w", err)
+	}
+
+	provider := underlayProvider(subnet)
+	externalIDs := map[string]string{
+		ExternalIDSubnetName: subnet.Name,
+		ExternalIDManagedBy:  ExternalIDManagedByValue,
+	}
+	if err := r.lspOps.EnsureLocalnetPort(ctx, lsName, provider, subnet.Spec.VlanID, externalIDs); err != nil {
+		return fmt.Errorf("failed to ensure localnet port: %w", err)
+	}
+
+	log.V(4).Info("Logical Switch connected to provider network", "provider", provider, "vlanID", subnet.Spec.VlanID)
+	return nil
+}
+
+// underlayProvider returns the provider network of an underlay Subnet
+func underlayProvider(subnet *networkv1.Subnet) string {
+	if subnet.Spec.Provider != "" {
+		return subnet.Spec.Provider
+	}
+	return types.DefaultProvider
+}
+
+// ensureIPAllocator creates the IP allocator of a Subnet, or resizes it when
+// the CIDRs or excluded IPs changed. Resizing keeps every allocation, so a
+// Subnet can grow (e.g. from /24 to /23, or with an extra range) but cannot
+// drop a range that still has IPs in use.
 func (r *SubnetReconciler) ensureIPAllocator(subnet *networkv1.Subnet) error {
 	r.allocatorsMu.Lock()
 	defer r.allocatorsMu.Unlock()
 
-	if _, exists := r.allocators[subnet.Name]; exists {
-		return nil
-	}
-
 	excludeIPs := make([]string, len(subnet.Spec.ExcludeIPs))
 	copy(excludeIPs, subnet.Spec.ExcludeIPs)
 
-	gatewayExcluded := false
-	for _, ip := range excludeIPs {
-		if ip == subnet.Spec.Gateway {
-			gatewayExcluded = true
-			break
+	// Gateways and health check source IPs are never allocated
+	reserved := append(subnet.Gateways(), subnet.HealthCheckIPs()...)
+	for _, reservedIP := range reserved {
+		if !containsString(excludeIPs, reservedIP) {
+			excludeIPs = append(excludeIPs, reservedIP)
 		}
 	}
-	if !gatewayExcluded {
-		excludeIPs = append(excludeIPs, subnet.Spec.Gateway)
+
+	if existing, exists := r.allocators[subnet.Name]; exists {
+		if existing.HasConfig(subnet.CIDRs(), excludeIPs) {
+			return nil
+		}
+
+		oldCIDRs, oldSize := existing.String(), existing.Size()
+		alloc, err := existing.Resize(subnet.CIDRs(), excludeIPs)
+		if err != nil {
+			return fmt.Errorf("failed to resize IP allocator: %w", err)
+		}
+
+		r.allocators[subnet.Name] = alloc
+		klog.Infof("Resized IP allocator for subnet %s from %s to %s: %d available IPs",
+			subnet.Name, oldCIDRs, alloc, alloc.Available())
+		r.recorder.Event(subnet, "Normal", "SubnetResized",
+			fmt.Sprintf("IP capacity changed from %d to %d (%s)", oldSize, alloc.Size(), alloc))
+		return nil
 	}
 
-	alloc, err := allocator.NewSubnetAllocator(subnet.Spec.CIDR, excludeIPs)
+	alloc, err := allocator.NewDualStackAllocator(subnet.CIDRs(), excludeIPs)
 	if err != nil {
 		return fmt.Errorf("failed to create IP allocator: %w", err)
 	}
@@ -333,8 +523,23 @@ func (r *SubnetReconciler) handleDeletion(ctx context.Context, subnet *networkv1
 	log := klog.FromContext(ctx).WithValues("subnet", subnet.Name)
 	log.Info("Handling Subnet deletion")
 
+	err := r.dhcpOps.DeleteDHCPOptionsWithPredicate(ctx, func(d *ovndb.DHCPOptions) bool {
+		return d.ExternalIDs[ExternalIDSubnetName] == subnet.Name && d.ExternalIDs[ExternalIDManagedBy] == ExternalIDManagedByValue
+	})
+	if err != nil {
+		log.Error(err, "Failed to delete DHCP options")
+		return ctrl.Result{}, err
+	}
+
 	if !subnet.IsExternalMode() {
 		lsName := subnet.GetLogicalSwitchName()
+
+		log.V(4).Info("Disconnecting Logical Switch from cluster router", "name", lsName)
+		if err := r.lrpOps.DisconnectLogicalSwitch(ctx, ovndb.ClusterRouterName, lsName); err != nil {
+			log.Error(err, "Failed to disconnect Logical Switch from cluster router")
+			return ctrl.Result{}, err
+		}
+
 		log.V(4).Info("Deleting Logical Switch", "name", lsName)
 
 		// Use ZStack compatibility module for safe deletion in external mode
@@ -397,9 +602,11 @@ func (r *SubnetReconciler) updateStatusActive(ctx context.Context, subnet *netwo
 
 	availableIPs := 0
 	usedIPs := 0
+	totalIPs := 0
 	if exists {
 		availableIPs = alloc.Available()
 		usedIPs = alloc.Used()
+		totalIPs = alloc.Size()
 	}
 
 	now := metav1.Now()
@@ -409,6 +616,7 @@ func (r *SubnetReconciler) updateStatusActive(ctx context.Context, subnet *netwo
 	subnet.Status.LogicalSwitch = lsName
 	subnet.Status.AvailableIPs = availableIPs
 	subnet.Status.UsedIPs = usedIPs
+	subnet.Status.TotalIPs = totalIPs
 	subnet.Status.LastUpdateTime = &now
 
 	subnet.Status.Conditions = updateCondition(subnet.Status.Conditions, metav1.Condition{
@@ -460,12 +668,23 @@ func (r *SubnetReconciler) updateStatusFailed(ctx context.Context, subnet *netwo
 }
 
 // GetAllocator returns the IP allocator for a subnet.
-func (r *SubnetReconciler) GetAllocator(subnetName string) *allocator.SubnetAllocator {
+func (r *SubnetReconciler) GetAllocator(subnetName string) *allocator.DualStackAllocator {
 	r.allocatorsMu.RLock()
 	defer r.allocatorsMu.RUnlock()
 	return r.allocators[subnetName]
 }
 
+// AllocatorsSynced returns true once the allocators have been rebuilt from
+// existing Pods and OVN ports, and IPs may be allocated.
+func (r *SubnetReconciler) AllocatorsSynced() bool {
+	return r.allocatorsSynced.Load()
+}
+
+// markAllocatorsSynced unblocks IP allocation after the allocator rebuild.
+func (r *SubnetReconciler) markAllocatorsSynced() {
+	r.allocatorsSynced.Store(true)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SubnetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -0,0 +1,374 @@
+// Package ovndb provides Address Set operations.
+//
+// This file implements CRUD operations for OVN Address Sets.
+// An Address Set is a named group of IP addresses that ACL match
+// expressions can reference as "$<name>", so the ACL itself does not
+// change when the set membership changes.
+//
+// In Kubernetes context:
+// - Each NetworkPolicy peer selector (podSelector/namespaceSelector) maps to one Address Set per IP family
+// - The sets hold the IPs of all Pods currently matched by the selector
+// - Pod and Namespace churn only updates the set addresses
+//
+// Key OVN Address Set fields:
+// - name: Unique identifier (must be a valid OVN identifier)
+// - addresses: IP addresses or CIDRs
+// - external_ids: External identifiers (owning NetworkPolicy, etc.)
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/address_set.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// External ID keys for Address Sets
+const (
+	// AddressSetExternalIDName is the key for the unhashed address set name
+	AddressSetExternalIDName = "k8s.ovn.org/name"
+)
+
+// Address Set name suffixes of the IP families
+const (
+	// AddressSetIPv4Suffix is appended to the name of an IPv4 Address Set
+	AddressSetIPv4Suffix = "_v4"
+
+	// AddressSetIPv6Suffix is appended to the name of an IPv6 Address Set
+	AddressSetIPv6Suffix = "_v6"
+)
+
+// AddressSetOps provides operations on OVN Address Sets
+type AddressSetOps struct {
+	client *Client
+}
+
+// NewAddressSetOps creates a new AddressSetOps
+func NewAddressSetOps(c *Client) *AddressSetOps {
+	return &AddressSetOps{client: c}
+}
+
+// CreateAddressSet creates a new Address Set
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Unique name for the set (see BuildAddressSetName)
+//   - addresses: IP addresses or CIDRs
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - *AddressSet: The created set with UUID populated
+//   - error: Creation error
+//
+// Example:
+//
+//	as, err := ops.CreateAddressSet(ctx, BuildAddressSetName("default/allow-web_ingress_0_0"),
+//	    []string{"10.244.1.5", "10.244.2.7"},
+//	    map[string]string{"k8s.ovn.org/policy": "default/allow-web"})
+func (o *AddressSetOps) CreateAddressSet(ctx context.Context, name string, addresses []string, externalIDs map[string]string) (*AddressSet, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	as := &AddressSet{
+		UUID:        BuildNamedUUID(name),
+		Name:        name,
+		Addresses:   addresses,
+		ExternalIDs: externalIDs,
+	}
+
+	ops, err := nbClient.Create(as)
+	if err != nil {
+		return nil, NewTransactionError("CreateAddressSet", err, name)
+	}
+
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > 0 {
+		as.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return as, nil
+}
+
+// GetAddressSet retrieves an Address Set by name
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the set to retrieve
+//
+// Returns:
+//   - *AddressSet: The found set
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *AddressSetOps) GetAddressSet(ctx context.Context, name string) (*AddressSet, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	as := &AddressSet{Name: name}
+	err := nbClient.Get(ctx, as)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("AddressSet", name)
+		}
+		return nil, NewTransactionError("GetAddressSet", err, name)
+	}
+
+	return as, nil
+}
+
+// ListAddressSetsWithPredicate lists Address Sets matching a predicate
+//
+// Example:
+//
+//	sets, err := ops.ListAddressSetsWithPredicate(ctx, func(as *AddressSet) bool {
+//	    return as.ExternalIDs["k8s.ovn.org/policy"] == "default/allow-web"
+//	})
+func (o *AddressSetOps) ListAddressSetsWithPredicate(ctx context.Context, predicate func(*AddressSet) bool) ([]*AddressSet, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var sets []*AddressSet
+	err := nbClient.WhereCache(func(as *AddressSet) bool {
+		return predicate(as)
+	}).List(ctx, &sets)
+	if err != nil {
+		return nil, NewTransactionError("ListAddressSetsWithPredicate", err, "")
+	}
+
+	return sets, nil
+}
+
+// ListAddressSetsByPolicy lists Address Sets owned by a specific NetworkPolicy
+func (o *AddressSetOps) ListAddressSetsByPolicy(ctx context.Context, namespace, policyName string) ([]*AddressSet, error) {
+	policyRef := fmt.Sprintf("%s/%s", namespace, policyName)
+	return o.ListAddressSetsWithPredicate(ctx, func(as *AddressSet) bool {
+		return as.ExternalIDs[ACLExternalIDPolicy] == policyRef
+	})
+}
+
+// CreateOrUpdateAddressSet creates an Address Set or replaces its addresses
+//
+// If the set exists, its addresses and external_ids are replaced.
+// If the set doesn't exist, it is created.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the set
+//   - addresses: Desired IP addresses
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - error: Operation error
+func (o *AddressSetOps) CreateOrUpdateAddressSet(ctx context.Context, name string, addresses []string, externalIDs map[string]string) error {
+	existing, err := o.GetAddressSet(ctx, name)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if existing == nil {
+		_, err = o.CreateAddressSet(ctx, name, addresses, externalIDs)
+		return err
+	}
+
+	if stringSetsEqual(existing.Addresses, addresses) && mapsEqualStr(existing.ExternalIDs, externalIDs) {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	existing.Addresses = addresses
+	if existing.Addresses == nil {
+		existing.Addresses = []string{}
+	}
+	existing.ExternalIDs = externalIDs
+	ops, err := nbClient.Where(existing).Update(existing, &existing.Addresses, &existing.ExternalIDs)
+	if err != nil {
+		return NewTransactionError("CreateOrUpdateAddressSet", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// AddAddresses adds IP addresses to an Address Set
+func (o *AddressSetOps) AddAddresses(ctx context.Context, name string, addresses ...string) error {
+	return o.mutateAddresses(ctx, "AddAddresses", name, ovsdb.MutateOperationInsert, addresses)
+}
+
+// RemoveAddresses removes IP addresses from an Address Set
+func (o *AddressSetOps) RemoveAddresses(ctx context.Context, name string, addresses ...string) error {
+	return o.mutateAddresses(ctx, "RemoveAddresses", name, ovsdb.MutateOperationDelete, addresses)
+}
+
+// mutateAddresses inserts or deletes addresses of an Address Set
+func (o *AddressSetOps) mutateAddresses(ctx context.Context, opName, name string, mutator ovsdb.Mutator, addresses []string) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	as := &AddressSet{Name: name}
+	ops, err := nbClient.Where(as).Mutate(as, model.Mutation{
+		Field:   &as.Addresses,
+		Mutator: mutator,
+		Value:   addresses,
+	})
+	if err != nil {
+		return NewTransactionError(opName, err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteAddressSet deletes an Address Set by name
+//
+// Returns:
+//   - error: Deletion error (nil if set doesn't exist)
+func (o *AddressSetOps) DeleteAddressSet(ctx context.Context, name string) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	as := &AddressSet{Name: name}
+	ops, err := nbClient.Where(as).Delete()
+	if err != nil {
+		return NewTransactionError("DeleteAddressSet", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteAddressSetsWithPredicate deletes all Address Sets matching a predicate
+// in a single transaction
+func (o *AddressSetOps) DeleteAddressSetsWithPredicate(ctx context.Context, predicate func(*AddressSet) bool) error {
+	sets, err := o.ListAddressSetsWithPredicate(ctx, predicate)
+	if err != nil {
+		return err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	var ops []ovsdb.Operation
+	for _, as := range sets {
+		deleteOps, err := nbClient.Where(as).Delete()
+		if err != nil {
+			return NewTransactionError("DeleteAddressSetsWithPredicate", err, as.Name)
+		}
+		ops = append(ops, deleteOps...)
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// BuildAddressSetName builds a valid OVN Address Set name from an arbitrary key
+//
+// OVN identifiers may only contain letters, digits and underscores, so the
+// key is hashed. The unhashed key should be stored in external_ids under
+// AddressSetExternalIDName for debugging.
+//
+// Format: a<fnv64 hash of key>
+func BuildAddressSetName(key string) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("a%d", h.Sum64())
+}
+
+// BuildAddressSetNames builds the names of the IPv4 and IPv6 Address Sets
+// of a key. A set only holds addresses of one family, since a match
+// compares it against either ip4 or ip6 fields.
+//
+// Format: a<fnv64 hash of key>_v4, a<fnv64 hash of key>_v6
+func BuildAddressSetNames(key string) (string, string) {
+	name := BuildAddressSetName(key)
+	return name + AddressSetIPv4Suffix, name + AddressSetIPv6Suffix
+}
+
+// IsIPv6AddressSet reports whether an Address Set name or reference
+// belongs to an IPv6 Address Set built by BuildAddressSetNames
+func IsIPv6AddressSet(name string) bool {
+	return strings.HasSuffix(name, AddressSetIPv6Suffix)
+}
+
+// BuildAddressSetReference builds the match expression reference to an Address Set
+// Format: $<name>
+func BuildAddressSetReference(name string) string {
+	return "$" + name
+}
+
+// stringSetsEqual compares two string slices ignoring order
+func stringSetsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		if seen[s] == 0 {
+			return false
+		}
+		seen[s]--
+	}
+	return true
+}
+
+// mapsEqualStr compares two string maps for equality
+func mapsEqualStr(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
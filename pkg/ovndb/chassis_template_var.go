@@ -0,0 +1,251 @@
+// Package ovndb provides Chassis Template Variable operations.
+//
+// This file implements operations for OVN Chassis_Template_Var rows.
+// A template load balancer uses "^NAME" in its VIPs or backends, and each
+// chassis expands NAME to the value in its own Chassis_Template_Var row. One
+// load balancer row can therefore hold different backends per node.
+//
+// In Kubernetes context:
+// - One row per node, keyed by the OVN chassis name of the node
+// - Services with node-dependent backends store them as variables
+//
+// Key OVN Chassis_Template_Var fields:
+// - chassis: Chassis name (ovn-controller system-id)
+// - variables: Variable name to value map
+// - external_ids: External identifiers
+//
+// Reference: ovn-nb(5) Chassis_Template_Var, OVN-Kubernetes pkg/libovsdb/ops/template_var.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// ChassisTemplateVarOps provides operations on OVN Chassis Template Variables
+type ChassisTemplateVarOps struct {
+	client *Client
+}
+
+// NewChassisTemplateVarOps creates a new ChassisTemplateVarOps
+func NewChassisTemplateVarOps(c *Client) *ChassisTemplateVarOps {
+	return &ChassisTemplateVarOps{client: c}
+}
+
+// GetChassisTemplateVar retrieves the template variables of a chassis
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - chassis: Chassis name
+//
+// Returns:
+//   - *ChassisTemplateVar: The found row
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *ChassisTemplateVarOps) GetChassisTemplateVar(ctx context.Context, chassis string) (*ChassisTemplateVar, error) {
+	if chassis == "" {
+		return nil, NewValidationError("chassis", chassis, "chassis is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	tv := &ChassisTemplateVar{Chassis: chassis}
+	err := nbClient.Get(ctx, tv)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("ChassisTemplateVar", chassis)
+		}
+		return nil, NewTransactionError("GetChassisTemplateVar", err, chassis)
+	}
+
+	return tv, nil
+}
+
+// ListChassisTemplateVars lists the template variables of all chassis
+func (o *ChassisTemplateVarOps) ListChassisTemplateVars(ctx context.Context) ([]*ChassisTemplateVar, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var tvs []*ChassisTemplateVar
+	if err := nbClient.List(ctx, &tvs); err != nil {
+		return nil, NewTransactionError("ListChassisTemplateVars", err, "")
+	}
+
+	return tvs, nil
+}
+
+// SetTemplateVariables sets template variables of a chassis
+//
+// Variables not listed are left untouched. The chassis row is created if it
+// doesn't exist. Nothing is written if every variable already has the
+// desired value.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - chassis: Chassis name
+//   - vars: Variable name to value map
+//
+// Returns:
+//   - error: Operation error
+func (o *ChassisTemplateVarOps) SetTemplateVariables(ctx context.Context, chassis string, vars map[string]string) error {
+	if len(vars) == 0 {
+		return nil
+	}
+
+	existing, err := o.GetChassisTemplateVar(ctx, chassis)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	if existing == nil {
+		tv := &ChassisTemplateVar{
+			UUID:      BuildNamedUUID(chassis),
+			Chassis:   chassis,
+			Variables: vars,
+		}
+		ops, err := nbClient.Create(tv)
+		if err != nil {
+			return NewTransactionError("SetTemplateVariables", err, chassis)
+		}
+		_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+		return err
+	}
+
+	// Map inserts don't overwrite existing keys, so changed variables are
+	// deleted first within the same transaction
+	stale := map[string]string{}
+	changed := map[string]string{}
+	for name, value := range vars {
+		current, ok := existing.Variables[name]
+		if ok && current == value {
+			continue
+		}
+		if ok {
+			stale[name] = current
+		}
+		changed[name] = value
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	tv := &ChassisTemplateVar{Chassis: chassis}
+	var mutations []model.Mutation
+	if len(stale) > 0 {
+		mutations = append(mutations, model.Mutation{
+			Field:   &tv.Variables,
+			Mutator: ovsdb.MutateOperationDelete,
+			Value:   stale,
+		})
+	}
+	mutations = append(mutations, model.Mutation{
+		Field:   &tv.Variables,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   changed,
+	})
+
+	ops, err := nbClient.Where(tv).Mutate(tv, mutations...)
+	if err != nil {
+		return NewTransactionError("SetTemplateVariables", err, chassis)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteTemplateVariables deletes template variables of a chassis
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - chassis: Chassis name
+//   - names: Names of the variables to delete
+//
+// Returns:
+//   - error: Operation error, nil if the chassis has no such variable
+func (o *ChassisTemplateVarOps) DeleteTemplateVariables(ctx context.Context, chassis string, names ...string) error {
+	existing, err := o.GetChassisTemplateVar(ctx, chassis)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	stale := map[string]string{}
+	for _, name := range names {
+		if value, ok := existing.Variables[name]; ok {
+			stale[name] = value
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	tv := &ChassisTemplateVar{Chassis: chassis}
+	ops, err := nbClient.Where(tv).Mutate(tv, model.Mutation{
+		Field:   &tv.Variables,
+		Mutator: ovsdb.MutateOperationDelete,
+		Value:   stale,
+	})
+	if err != nil {
+		return NewTransactionError("DeleteTemplateVariables", err, chassis)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// LookupChassisName returns the chassis name of a node from the Southbound
+// Chassis table
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - hostname: Hostname reported by ovn-controller (the node name)
+//
+// Returns:
+//   - string: Chassis name
+//   - error: ObjectNotFoundError if no chassis has this hostname
+func (o *ChassisTemplateVarOps) LookupChassisName(ctx context.Context, hostname string) (string, error) {
+	sbClient := o.client.SBClient()
+	if sbClient == nil {
+		return "", fmt.Errorf("SB client is not connected")
+	}
+
+	var chassis []*Chassis
+	err := sbClient.WhereCache(func(ch *Chassis) bool {
+		return ch.Hostname == hostname
+	}).List(ctx, &chassis)
+	if err != nil {
+		return "", NewTransactionError("LookupChassisName", err, hostname)
+	}
+	if len(chassis) == 0 {
+		return "", NewObjectNotFoundError("Chassis", hostname)
+	}
+
+	return chassis[0].Name, nil
+}
+
+// BuildTemplateReference builds the reference to a template variable used
+// in load balancer VIPs and backends
+// Format: ^<name>
+func BuildTemplateReference(name string) string {
+	return "^" + name
+}
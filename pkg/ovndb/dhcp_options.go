@@ -0,0 +1,194 @@
+// Package ovndb provides DHCP Options operations.
+//
+// This file implements CRUD operations for OVN DHCP_Options rows.
+// A DHCP_Options row holds the options ovn-controller hands out for one CIDR.
+// Logical Switch Ports opt in by referencing a row from dhcpv4_options or
+// dhcpv6_options; the reference is weak, so deleting a row detaches it from
+// every port.
+//
+// In Kubernetes context:
+// - Each CIDR of a Subnet with DHCP enabled has one row
+// - Pods, and VMs attached to the same switch, get their address by DHCP
+//
+// Key OVN DHCP_Options fields:
+// - cidr: The network the options apply to
+// - options: DHCP options (server_id, server_mac, router, lease_time, etc.)
+// - external_ids: External identifiers (owning Subnet, etc.)
+//
+// DHCP_Options has no name column, so rows are identified by their cidr and
+// external_ids.
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/dhcp.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// DHCPv4 option keys
+const (
+	// DHCPv4OptionServerID is the IP address of the DHCP server
+	DHCPv4OptionServerID = "server_id"
+
+	// DHCPv4OptionServerMAC is the MAC address of the DHCP server
+	DHCPv4OptionServerMAC = "server_mac"
+
+	// DHCPv4OptionRouter is the default gateway
+	DHCPv4OptionRouter = "router"
+
+	// DHCPv4OptionLeaseTime is the lease time in seconds
+	DHCPv4OptionLeaseTime = "lease_time"
+
+	// DHCPv4OptionMTU is the interface MTU
+	DHCPv4OptionMTU = "mtu"
+
+	// DHCPv4OptionDNSServer is the set of DNS servers ("{ip1,ip2}")
+	DHCPv4OptionDNSServer = "dns_server"
+)
+
+// DHCPv6 option keys
+const (
+	// DHCPv6OptionServerID is the MAC address of the DHCPv6 server
+	DHCPv6OptionServerID = "server_id"
+
+	// DHCPv6OptionDNSServer is the set of DNS servers ("{ip1,ip2}")
+	DHCPv6OptionDNSServer = "dns_server"
+)
+
+// DHCPOptionsOps provides operations on OVN DHCP_Options rows
+type DHCPOptionsOps struct {
+	client *Client
+}
+
+// NewDHCPOptionsOps creates a new DHCPOptionsOps
+func NewDHCPOptionsOps(c *Client) *DHCPOptionsOps {
+	return &DHCPOptionsOps{client: c}
+}
+
+// ListDHCPOptionsWithPredicate lists DHCP_Options rows matching a predicate
+//
+// Example:
+//
+//	rows, err := ops.ListDHCPOptionsWithPredicate(ctx, func(d *DHCPOptions) bool {
+//	    return d.ExternalIDs["zstack.io/subnet"] == "default"
+//	})
+func (o *DHCPOptionsOps) ListDHCPOptionsWithPredicate(ctx context.Context, predicate func(*DHCPOptions) bool) ([]*DHCPOptions, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var rows []*DHCPOptions
+	err := nbClient.WhereCache(func(d *DHCPOptions) bool {
+		return predicate(d)
+	}).List(ctx, &rows)
+	if err != nil {
+		return nil, NewTransactionError("ListDHCPOptionsWithPredicate", err, "")
+	}
+
+	return rows, nil
+}
+
+// CreateOrUpdateDHCPOptions creates the DHCP_Options row of a CIDR or
+// replaces its options
+//
+// The row is the one with the same cidr and external_ids. If it exists, its
+// options are replaced; otherwise it is created.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cidr: Network the options apply to (e.g., "10.244.1.0/24")
+//   - options: Desired DHCP options
+//   - externalIDs: External identifiers, identifying the row
+//
+// Returns:
+//   - *DHCPOptions: The created or updated row with UUID populated
+//   - error: Operation error
+func (o *DHCPOptionsOps) CreateOrUpdateDHCPOptions(ctx context.Context, cidr string, options, externalIDs map[string]string) (*DHCPOptions, error) {
+	if cidr == "" {
+		return nil, NewValidationError("cidr", cidr, "CIDR is required")
+	}
+
+	rows, err := o.ListDHCPOptionsWithPredicate(ctx, func(d *DHCPOptions) bool {
+		return d.Cidr == cidr && mapsEqualStr(d.ExternalIDs, externalIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	if len(rows) > 0 {
+		existing := rows[0]
+		if mapsEqualStr(existing.Options, options) {
+			return existing, nil
+		}
+		existing.Options = options
+		ops, err := nbClient.Where(existing).Update(existing, &existing.Options)
+		if err != nil {
+			return nil, NewTransactionError("CreateOrUpdateDHCPOptions", err, cidr)
+		}
+		if _, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout()); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	d := &DHCPOptions{
+		UUID:        BuildNamedUUID("dhcp"),
+		Cidr:        cidr,
+		Options:     options,
+		ExternalIDs: externalIDs,
+	}
+	ops, err := nbClient.Create(d)
+	if err != nil {
+		return nil, NewTransactionError("CreateOrUpdateDHCPOptions", err, cidr)
+	}
+
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > 0 {
+		d.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return d, nil
+}
+
+// DeleteDHCPOptionsWithPredicate deletes all DHCP_Options rows matching a
+// predicate in a single transaction
+func (o *DHCPOptionsOps) DeleteDHCPOptionsWithPredicate(ctx context.Context, predicate func(*DHCPOptions) bool) error {
+	rows, err := o.ListDHCPOptionsWithPredicate(ctx, predicate)
+	if err != nil {
+		return err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	var ops []ovsdb.Operation
+	for _, d := range rows {
+		deleteOps, err := nbClient.Where(d).Delete()
+		if err != nil {
+			return NewTransactionError("DeleteDHCPOptionsWithPredicate", err, d.Cidr)
+		}
+		ops = append(ops, deleteOps...)
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
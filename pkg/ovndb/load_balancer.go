@@ -52,6 +52,31 @@ const (
 
 	// LBOptionAffinityTimeout sets session affinity timeout in seconds
 	LBOptionAffinityTimeout = "affinity_timeout"
+
+	// LBOptionTemplate marks a load balancer whose VIPs or backends reference
+	// Chassis_Template_Var variables
+	LBOptionTemplate = "template"
+
+	// LBOptionAddressFamily is the address family of a template load
+	// balancer ("ipv4" or "ipv6")
+	LBOptionAddressFamily = "address-family"
+)
+
+// Load Balancer health check option keys
+const (
+	// LBHealthCheckOptionInterval is the interval between probes in seconds
+	LBHealthCheckOptionInterval = "interval"
+
+	// LBHealthCheckOptionTimeout is the probe timeout in seconds
+	LBHealthCheckOptionTimeout = "timeout"
+
+	// LBHealthCheckOptionSuccessCount is the number of successful probes
+	// bringing a backend back
+	LBHealthCheckOptionSuccessCount = "success_count"
+
+	// LBHealthCheckOptionFailureCount is the number of failed probes
+	// removing a backend
+	LBHealthCheckOptionFailureCount = "failure_count"
 )
 
 // External ID keys for Load Balancers
@@ -477,6 +502,111 @@ func (o *LoadBalancerOps) SetExternalIDs(ctx context.Context, name string, ids m
 	return o.UpdateLoadBalancer(ctx, lb, &lb.ExternalIDs)
 }
 
+// SetHealthChecks sets the health checks and ip_port_mappings of a Load
+// Balancer
+//
+// Health checks are identified by VIP: existing rows are kept or updated,
+// missing ones are created, and rows of other VIPs are dropped from the
+// Load Balancer. Load_Balancer_Health_Check is not a root table, so OVSDB
+// deletes the dropped rows. Nothing is written if the Load Balancer is up
+// to date.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the load balancer
+//   - checks: Health check options per VIP, empty to disable health checks
+//   - ipPortMappings: Backend IP to "<port name>:<source IP>" mapping, naming
+//     the Logical Switch Port of each backend and the probe source IP
+//
+// Returns:
+//   - error: Update error
+//
+// Example:
+//
+//	err := ops.SetHealthChecks(ctx, "Service_default/nginx_tcp",
+//	    map[string]map[string]string{"10.96.0.100:80": {"interval": "5"}},
+//	    map[string]string{"10.244.1.5": "default_nginx-1:10.244.1.254"})
+func (o *LoadBalancerOps) SetHealthChecks(ctx context.Context, name string, checks map[string]map[string]string, ipPortMappings map[string]string) error {
+	lb, err := o.GetLoadBalancer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	existing := make(map[string]*LoadBalancerHealthCheck, len(lb.HealthCheck))
+	for _, uuid := range lb.HealthCheck {
+		hc := &LoadBalancerHealthCheck{UUID: uuid}
+		if err := nbClient.Get(ctx, hc); err != nil {
+			continue
+		}
+		existing[hc.Vip] = hc
+	}
+
+	var ops []ovsdb.Operation
+	healthChecks := make([]string, 0, len(checks))
+	for vip, options := range checks {
+		if hc, ok := existing[vip]; ok {
+			healthChecks = append(healthChecks, hc.UUID)
+			if mapsEqualStr(hc.Options, options) {
+				continue
+			}
+			hc.Options = options
+			updateOps, err := nbClient.Where(hc).Update(hc, &hc.Options)
+			if err != nil {
+				return NewTransactionError("SetHealthChecks", err, name)
+			}
+			ops = append(ops, updateOps...)
+			continue
+		}
+
+		hc := &LoadBalancerHealthCheck{
+			UUID:    BuildNamedUUID(fmt.Sprintf("lbhc%d", len(healthChecks))),
+			Vip:     vip,
+			Options: options,
+		}
+		createOps, err := nbClient.Create(hc)
+		if err != nil {
+			return NewTransactionError("SetHealthChecks", err, name)
+		}
+		ops = append(ops, createOps...)
+		healthChecks = append(healthChecks, hc.UUID)
+	}
+
+	if ipPortMappings == nil {
+		ipPortMappings = map[string]string{}
+	}
+	if len(ops) == 0 && len(healthChecks) == len(lb.HealthCheck) && mapsEqualStr(lb.IPPortMappings, ipPortMappings) {
+		return nil
+	}
+
+	lb.HealthCheck = healthChecks
+	lb.IPPortMappings = ipPortMappings
+	updateOps, err := nbClient.Where(lb).Update(lb, &lb.HealthCheck, &lb.IPPortMappings)
+	if err != nil {
+		return NewTransactionError("SetHealthChecks", err, name)
+	}
+	ops = append(ops, updateOps...)
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// BuildIPPortMapping builds an ip_port_mappings entry for a backend
+//
+// Returns:
+//   - key: Backend IP, in brackets for IPv6
+//   - value: "<port name>:<source IP>", the source IP in brackets for IPv6
+func BuildIPPortMapping(backendIP, portName, sourceIP string) (key, value string) {
+	if strings.Contains(backendIP, ":") {
+		return "[" + backendIP + "]", portName + ":[" + sourceIP + "]"
+	}
+	return backendIP, portName + ":" + sourceIP
+}
+
 // BuildVIP builds a VIP string from IP and port
 // Handles both IPv4 and IPv6 addresses
 func BuildVIP(ip string, port int) string {
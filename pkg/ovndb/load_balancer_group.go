@@ -0,0 +1,174 @@
+// Package ovndb provides Load Balancer Group operations.
+//
+// This file implements operations for OVN Load Balancer Groups.
+// A Load Balancer Group is a named set of Load Balancers. Logical Switches
+// and Logical Routers reference the group through their load_balancer_group
+// column, and every Load Balancer in the group applies to all of them.
+//
+// In Kubernetes context:
+// - One cluster-wide group is referenced by every Subnet switch and the cluster router
+// - Service Load Balancers are only added to the group, never to the switches
+// - Adding a Service is one mutation, independent of the number of switches
+//
+// Key OVN Load Balancer Group fields:
+// - name: Unique identifier
+// - load_balancer: Load Balancer UUIDs (weak references)
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/lbgroup.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+const (
+	// ClusterLoadBalancerGroupName is the name of the group holding the
+	// Service Load Balancers of the cluster
+	ClusterLoadBalancerGroupName = "cluster_load_balancer_group"
+)
+
+// LoadBalancerGroupOps provides operations on OVN Load Balancer Groups
+type LoadBalancerGroupOps struct {
+	client *Client
+}
+
+// NewLoadBalancerGroupOps creates a new LoadBalancerGroupOps
+func NewLoadBalancerGroupOps(c *Client) *LoadBalancerGroupOps {
+	return &LoadBalancerGroupOps{client: c}
+}
+
+// CreateLoadBalancerGroup creates a new, empty Load Balancer Group
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Unique name for the group
+//
+// Returns:
+//   - *LoadBalancerGroup: The created group with UUID populated
+//   - error: Creation error
+func (o *LoadBalancerGroupOps) CreateLoadBalancerGroup(ctx context.Context, name string) (*LoadBalancerGroup, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	group := &LoadBalancerGroup{
+		UUID: BuildNamedUUID(name),
+		Name: name,
+	}
+
+	ops, err := nbClient.Create(group)
+	if err != nil {
+		return nil, NewTransactionError("CreateLoadBalancerGroup", err, name)
+	}
+
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > 0 {
+		group.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return group, nil
+}
+
+// GetLoadBalancerGroup retrieves a Load Balancer Group by name
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the group to retrieve
+//
+// Returns:
+//   - *LoadBalancerGroup: The found group
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *LoadBalancerGroupOps) GetLoadBalancerGroup(ctx context.Context, name string) (*LoadBalancerGroup, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	group := &LoadBalancerGroup{Name: name}
+	err := nbClient.Get(ctx, group)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("LoadBalancerGroup", name)
+		}
+		return nil, NewTransactionError("GetLoadBalancerGroup", err, name)
+	}
+
+	return group, nil
+}
+
+// EnsureLoadBalancerGroup returns a Load Balancer Group, creating it if it
+// doesn't exist
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the group
+//
+// Returns:
+//   - *LoadBalancerGroup: The existing or created group
+//   - error: Operation error
+func (o *LoadBalancerGroupOps) EnsureLoadBalancerGroup(ctx context.Context, name string) (*LoadBalancerGroup, error) {
+	group, err := o.GetLoadBalancerGroup(ctx, name)
+	if err == nil {
+		return group, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+	return o.CreateLoadBalancerGroup(ctx, name)
+}
+
+// AddLoadBalancers adds Load Balancers to a Load Balancer Group
+func (o *LoadBalancerGroupOps) AddLoadBalancers(ctx context.Context, name string, lbUUIDs ...string) error {
+	return o.mutateLoadBalancerGroup(ctx, "AddLoadBalancers", name, ovsdb.MutateOperationInsert, lbUUIDs)
+}
+
+// RemoveLoadBalancers removes Load Balancers from a Load Balancer Group
+func (o *LoadBalancerGroupOps) RemoveLoadBalancers(ctx context.Context, name string, lbUUIDs ...string) error {
+	return o.mutateLoadBalancerGroup(ctx, "RemoveLoadBalancers", name, ovsdb.MutateOperationDelete, lbUUIDs)
+}
+
+// mutateLoadBalancerGroup inserts or deletes UUIDs in the load_balancer column
+func (o *LoadBalancerGroupOps) mutateLoadBalancerGroup(ctx context.Context, opName, name string, mutator ovsdb.Mutator, uuids []string) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+	if len(uuids) == 0 {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	group := &LoadBalancerGroup{Name: name}
+	ops, err := nbClient.Where(group).Mutate(group, model.Mutation{
+		Field:   &group.LoadBalancer,
+		Mutator: mutator,
+		Value:   uuids,
+	})
+	if err != nil {
+		return NewTransactionError(opName, err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
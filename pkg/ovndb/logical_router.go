@@ -0,0 +1,583 @@
+// Package ovndb provides Logical Router operations.
+//
+// This file implements CRUD operations for OVN Logical Routers.
+// A Logical Router is a virtual L3 router that forwards traffic between
+// the Logical Switches attached to it through Logical Router Ports.
+//
+// In Kubernetes context:
+// - A single cluster router (ovn_cluster_router) connects all node and Subnet switches
+// - Each attached switch has one router port carrying the switch's gateway IP
+// - Pods reach other switches by sending traffic to their gateway IP
+//
+// Key OVN Logical Router fields:
+// - name: Unique identifier for the router
+// - ports: List of Logical Router Port UUIDs
+// - static_routes: List of static route UUIDs
+// - nat: List of NAT rule UUIDs
+// - load_balancer: List of Load Balancer UUIDs for services
+// - options: Router options (chassis, lb_force_snat_ip, etc.)
+// - external_ids: External identifiers for integration
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/router.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+const (
+	// ClusterRouterName is the name of the distributed router that connects
+	// all node and Subnet Logical Switches
+	ClusterRouterName = "ovn_cluster_router"
+
+	// LogicalRouterOptionChassis binds a gateway router to a chassis
+	LogicalRouterOptionChassis = "chassis"
+)
+
+// ClusterRouterExternalIDs returns the external IDs of the cluster router.
+// Every controller that creates the router writes the same set, so the
+// controllers do not overwrite each other's IDs.
+func ClusterRouterExternalIDs() map[string]string {
+	return map[string]string{
+		"zstack.io/type":       "cluster-router",
+		"zstack.io/managed-by": "zstack-ovn-kubernetes",
+	}
+}
+
+// LogicalRouterOps provides operations on OVN Logical Routers
+type LogicalRouterOps struct {
+	client *Client
+}
+
+// NewLogicalRouterOps creates a new LogicalRouterOps
+func NewLogicalRouterOps(c *Client) *LogicalRouterOps {
+	return &LogicalRouterOps{client: c}
+}
+
+// CreateLogicalRouter creates a new Logical Router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Unique name for the router
+//   - options: Router options
+//   - externalIDs: External identifiers for integration
+//
+// Returns:
+//   - *LogicalRouter: The created router with UUID populated
+//   - error: Creation error
+//
+// Example:
+//
+//	lr, err := ops.CreateLogicalRouter(ctx, ClusterRouterName, nil,
+//	    map[string]string{"zstack.io/managed-by": "zstack-ovn-kubernetes"})
+func (o *LogicalRouterOps) CreateLogicalRouter(ctx context.Context, name string, options, externalIDs map[string]string) (*LogicalRouter, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	enabled := true
+	lr := &LogicalRouter{
+		UUID:        BuildNamedUUID(name),
+		Name:        name,
+		Options:     options,
+		ExternalIDs: externalIDs,
+		Enabled:     &enabled,
+	}
+
+	ops, err := nbClient.Create(lr)
+	if err != nil {
+		return nil, NewTransactionError("CreateLogicalRouter", err, name)
+	}
+
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the real UUID from the result
+	if len(results) > 0 {
+		lr.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return lr, nil
+}
+
+// GetLogicalRouter retrieves a Logical Router by name
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router to retrieve
+//
+// Returns:
+//   - *LogicalRouter: The found router
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *LogicalRouterOps) GetLogicalRouter(ctx context.Context, name string) (*LogicalRouter, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	err := nbClient.Get(ctx, lr)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("LogicalRouter", name)
+		}
+		return nil, NewTransactionError("GetLogicalRouter", err, name)
+	}
+
+	return lr, nil
+}
+
+// GetLogicalRouterByUUID retrieves a Logical Router by UUID
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - uuid: UUID of the router to retrieve
+//
+// Returns:
+//   - *LogicalRouter: The found router
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *LogicalRouterOps) GetLogicalRouterByUUID(ctx context.Context, uuid string) (*LogicalRouter, error) {
+	if uuid == "" {
+		return nil, NewValidationError("uuid", uuid, "uuid is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{UUID: uuid}
+	err := nbClient.Get(ctx, lr)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("LogicalRouter", uuid)
+		}
+		return nil, NewTransactionError("GetLogicalRouterByUUID", err, uuid)
+	}
+
+	return lr, nil
+}
+
+// ListLogicalRouters lists all Logical Routers
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - []*LogicalRouter: List of all routers
+//   - error: Query error
+func (o *LogicalRouterOps) ListLogicalRouters(ctx context.Context) ([]*LogicalRouter, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var routers []*LogicalRouter
+	err := nbClient.List(ctx, &routers)
+	if err != nil {
+		return nil, NewTransactionError("ListLogicalRouters", err, "")
+	}
+
+	return routers, nil
+}
+
+// ListLogicalRoutersWithPredicate lists Logical Routers matching a predicate
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - predicate: Function to filter routers
+//
+// Returns:
+//   - []*LogicalRouter: List of matching routers
+//   - error: Query error
+//
+// Example:
+//
+//	routers, err := ops.ListLogicalRoutersWithPredicate(ctx, func(lr *LogicalRouter) bool {
+//	    return lr.ExternalIDs["zstack.io/managed-by"] == "zstack-ovn-kubernetes"
+//	})
+func (o *LogicalRouterOps) ListLogicalRoutersWithPredicate(ctx context.Context, predicate func(*LogicalRouter) bool) ([]*LogicalRouter, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var routers []*LogicalRouter
+	err := nbClient.WhereCache(func(lr *LogicalRouter) bool {
+		return predicate(lr)
+	}).List(ctx, &routers)
+	if err != nil {
+		return nil, NewTransactionError("ListLogicalRoutersWithPredicate", err, "")
+	}
+
+	return routers, nil
+}
+
+// UpdateLogicalRouter updates a Logical Router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - lr: Logical Router with updated fields
+//   - fields: Fields to update (if empty, updates all non-zero fields)
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) UpdateLogicalRouter(ctx context.Context, lr *LogicalRouter, fields ...interface{}) error {
+	if lr == nil || lr.Name == "" {
+		return NewValidationError("lr", lr, "logical router with name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	// If no specific fields provided, update all mutable fields
+	if len(fields) == 0 {
+		fields = getLogicalRouterMutableFields(lr)
+	}
+
+	ops, err := nbClient.Where(lr).Update(lr, fields...)
+	if err != nil {
+		return NewTransactionError("UpdateLogicalRouter", err, lr.Name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteLogicalRouter deletes a Logical Router by name
+// Router ports referenced only by this router are garbage collected by OVSDB.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router to delete
+//
+// Returns:
+//   - error: Deletion error (nil if router doesn't exist)
+func (o *LogicalRouterOps) DeleteLogicalRouter(ctx context.Context, name string) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	ops, err := nbClient.Where(lr).Delete()
+	if err != nil {
+		return NewTransactionError("DeleteLogicalRouter", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteLogicalRouterOps returns operations to delete a Logical Router
+// This is useful for building composite transactions
+//
+// Parameters:
+//   - name: Name of the router to delete
+//
+// Returns:
+//   - []ovsdb.Operation: Delete operations
+//   - error: Operation building error
+func (o *LogicalRouterOps) DeleteLogicalRouterOps(name string) ([]ovsdb.Operation, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	return nbClient.Where(lr).Delete()
+}
+
+// CreateOrUpdateLogicalRouter creates or updates a Logical Router
+//
+// If the router exists, it updates the specified fields.
+// If the router doesn't exist, it creates a new one.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - lr: Logical Router to create or update
+//
+// Returns:
+//   - error: Operation error
+func (o *LogicalRouterOps) CreateOrUpdateLogicalRouter(ctx context.Context, lr *LogicalRouter) error {
+	if lr == nil || lr.Name == "" {
+		return NewValidationError("lr", lr, "logical router with name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	// Check if router exists
+	existing, err := o.GetLogicalRouter(ctx, lr.Name)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if existing != nil {
+		// Nothing to update if no mutable fields are set
+		if len(getLogicalRouterMutableFields(lr)) == 0 {
+			return nil
+		}
+		lr.UUID = existing.UUID
+		return o.UpdateLogicalRouter(ctx, lr)
+	}
+
+	// Create new router
+	_, err = o.CreateLogicalRouter(ctx, lr.Name, lr.Options, lr.ExternalIDs)
+	return err
+}
+
+// EnsureClusterRouter creates the cluster router if it does not exist and
+// merges ClusterRouterExternalIDs into its external IDs. Nothing is written
+// if the router already has every ID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - error: Operation error
+func (o *LogicalRouterOps) EnsureClusterRouter(ctx context.Context) error {
+	ids := ClusterRouterExternalIDs()
+
+	existing, err := o.GetLogicalRouter(ctx, ClusterRouterName)
+	if err != nil {
+		if !IsNotFound(err) {
+			return err
+		}
+		_, err = o.CreateLogicalRouter(ctx, ClusterRouterName, nil, ids)
+		return err
+	}
+
+	for k, v := range ids {
+		if existing.ExternalIDs[k] != v {
+			return o.SetExternalIDs(ctx, ClusterRouterName, ids)
+		}
+	}
+	return nil
+}
+
+// IsGatewayRouter reports whether a Logical Router is bound to a chassis,
+// which is needed to answer ARP and neighbor discovery from the physical
+// network. A router is bound if it is a gateway router (options:chassis)
+// or has a distributed gateway port (gateway_chassis or ha_chassis_group).
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//
+// Returns:
+//   - bool: true if the router is bound to a chassis
+//   - error: ObjectNotFoundError if the router does not exist, or other error
+func (o *LogicalRouterOps) IsGatewayRouter(ctx context.Context, name string) (bool, error) {
+	lr, err := o.GetLogicalRouter(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if lr.Options[LogicalRouterOptionChassis] != "" {
+		return true, nil
+	}
+
+	lrpOps := NewLogicalRouterPortOps(o.client)
+	for _, uuid := range lr.Ports {
+		lrp, err := lrpOps.GetLogicalRouterPortByUUID(ctx, uuid)
+		if err != nil {
+			if IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if len(lrp.GatewayChassis) > 0 || lrp.HaChassisGroup != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetOptions sets options on a Logical Router
+// Empty values will delete the corresponding keys
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//   - options: Options to set (empty values delete keys)
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) SetOptions(ctx context.Context, name string, options map[string]string) error {
+	lr, err := o.GetLogicalRouter(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if lr.Options == nil {
+		lr.Options = make(map[string]string)
+	}
+
+	for k, v := range options {
+		if v == "" {
+			delete(lr.Options, k)
+		} else {
+			lr.Options[k] = v
+		}
+	}
+
+	return o.UpdateLogicalRouter(ctx, lr, &lr.Options)
+}
+
+// SetExternalIDs sets external_ids values on a Logical Router
+// Empty values will delete the corresponding keys
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//   - ids: External IDs to set (empty values delete keys)
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) SetExternalIDs(ctx context.Context, name string, ids map[string]string) error {
+	lr, err := o.GetLogicalRouter(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if lr.ExternalIDs == nil {
+		lr.ExternalIDs = make(map[string]string)
+	}
+
+	for k, v := range ids {
+		if v == "" {
+			delete(lr.ExternalIDs, k)
+		} else {
+			lr.ExternalIDs[k] = v
+		}
+	}
+
+	return o.UpdateLogicalRouter(ctx, lr, &lr.ExternalIDs)
+}
+
+// AddLoadBalancersToLogicalRouter adds Load Balancers to a Logical Router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//   - lbUUIDs: UUIDs of Load Balancers to add
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) AddLoadBalancersToLogicalRouter(ctx context.Context, name string, lbUUIDs ...string) error {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	ops, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+		Field:   &lr.LoadBalancer,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   lbUUIDs,
+	})
+	if err != nil {
+		return NewTransactionError("AddLoadBalancersToLogicalRouter", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// RemoveLoadBalancersFromLogicalRouter removes Load Balancers from a Logical Router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//   - lbUUIDs: UUIDs of Load Balancers to remove
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) RemoveLoadBalancersFromLogicalRouter(ctx context.Context, name string, lbUUIDs ...string) error {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	ops, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+		Field:   &lr.LoadBalancer,
+		Mutator: ovsdb.MutateOperationDelete,
+		Value:   lbUUIDs,
+	})
+	if err != nil {
+		return NewTransactionError("RemoveLoadBalancersFromLogicalRouter", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// AddLoadBalancerGroupsToLogicalRouter adds Load Balancer Groups to a Logical Router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the router
+//   - groupUUIDs: UUIDs of Load Balancer Groups to add
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterOps) AddLoadBalancerGroupsToLogicalRouter(ctx context.Context, name string, groupUUIDs ...string) error {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: name}
+	ops, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+		Field:   &lr.LoadBalancerGroup,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   groupUUIDs,
+	})
+	if err != nil {
+		return NewTransactionError("AddLoadBalancerGroupsToLogicalRouter", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// getLogicalRouterMutableFields returns the mutable fields of a LogicalRouter
+func getLogicalRouterMutableFields(lr *LogicalRouter) []interface{} {
+	fields := []interface{}{}
+	if lr.Options != nil {
+		fields = append(fields, &lr.Options)
+	}
+	if lr.ExternalIDs != nil {
+		fields = append(fields, &lr.ExternalIDs)
+	}
+	return fields
+}
@@ -0,0 +1,660 @@
+// Package ovndb provides Logical Router Port operations.
+//
+// This file implements CRUD operations for OVN Logical Router Ports.
+// A Logical Router Port attaches a Logical Router to a Logical Switch
+// (through a peer switch port of type "router") or to another router.
+//
+// In Kubernetes context:
+// - Every node switch and Subnet switch is attached to the cluster router
+// - Router port name format: rtos-<switch-name> (router to switch)
+// - Switch port name format: stor-<switch-name> (switch to router)
+// - The router port carries the switch gateway IP (e.g., "10.244.1.1/24")
+//
+// Key OVN Logical Router Port fields:
+// - name: Unique identifier
+// - mac: MAC address of the router interface
+// - networks: IP addresses with prefix length (e.g., "10.244.1.1/24")
+// - peer: Peer router port (for router-to-router links)
+// - options: Port-specific options
+// - external_ids: External identifiers for integration
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/router.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// Router port naming and option constants
+const (
+	// RouterToSwitchPrefix is the name prefix of router ports facing a switch
+	RouterToSwitchPrefix = "rtos-"
+
+	// SwitchToRouterPrefix is the name prefix of switch ports facing a router
+	SwitchToRouterPrefix = "stor-"
+
+	// OptionRouterPort is the switch port option naming the peer router port
+	OptionRouterPort = "router-port"
+
+	// RouterPortAddresses is the addresses value for switch ports of type router
+	RouterPortAddresses = "router"
+)
+
+// LogicalRouterPortOps provides operations on OVN Logical Router Ports
+type LogicalRouterPortOps struct {
+	client *Client
+}
+
+// NewLogicalRouterPortOps creates a new LogicalRouterPortOps
+func NewLogicalRouterPortOps(c *Client) *LogicalRouterPortOps {
+	return &LogicalRouterPortOps{client: c}
+}
+
+// CreateLogicalRouterPort creates a new Logical Router Port and adds it to a router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - routerName: Name of the Logical Router to add the port to
+//   - portName: Unique name for the port
+//   - mac: MAC address (format: "0a:58:0a:f4:01:01")
+//   - networks: IP addresses with prefix (format: ["10.244.1.1/24"])
+//   - options: Port options
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - *LogicalRouterPort: The created port with UUID populated
+//   - error: Creation error
+//
+// Example:
+//
+//	lrp, err := ops.CreateLogicalRouterPort(ctx, ClusterRouterName, "rtos-node-worker1",
+//	    "0a:58:0a:f4:01:01", []string{"10.244.1.1/24"}, nil, nil)
+func (o *LogicalRouterPortOps) CreateLogicalRouterPort(
+	ctx context.Context,
+	routerName, portName, mac string,
+	networks []string,
+	options, externalIDs map[string]string,
+) (*LogicalRouterPort, error) {
+	if routerName == "" {
+		return nil, NewValidationError("routerName", routerName, "router name is required")
+	}
+	if portName == "" {
+		return nil, NewValidationError("portName", portName, "port name is required")
+	}
+	if mac == "" {
+		return nil, NewValidationError("mac", mac, "MAC address is required")
+	}
+	if len(networks) == 0 {
+		return nil, NewValidationError("networks", networks, "at least one network is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lrp := &LogicalRouterPort{
+		UUID:        BuildNamedUUID(portName),
+		Name:        portName,
+		MAC:         mac,
+		Networks:    networks,
+		Options:     options,
+		ExternalIDs: externalIDs,
+	}
+
+	// Create the port
+	createOps, err := nbClient.Create(lrp)
+	if err != nil {
+		return nil, NewTransactionError("CreateLogicalRouterPort", err, portName)
+	}
+
+	// Add port to router using mutation
+	lr := &LogicalRouter{Name: routerName}
+	mutateOps, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+		Field:   &lr.Ports,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   []string{lrp.UUID},
+	})
+	if err != nil {
+		return nil, NewTransactionError("CreateLogicalRouterPort", err, portName)
+	}
+
+	// Execute both operations atomically
+	ops := append(createOps, mutateOps...)
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the real UUID from the result
+	if len(results) > 0 {
+		lrp.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return lrp, nil
+}
+
+// GetLogicalRouterPort retrieves a Logical Router Port by name
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the port to retrieve
+//
+// Returns:
+//   - *LogicalRouterPort: The found port
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *LogicalRouterPortOps) GetLogicalRouterPort(ctx context.Context, name string) (*LogicalRouterPort, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lrp := &LogicalRouterPort{Name: name}
+	err := nbClient.Get(ctx, lrp)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("LogicalRouterPort", name)
+		}
+		return nil, NewTransactionError("GetLogicalRouterPort", err, name)
+	}
+
+	return lrp, nil
+}
+
+// GetLogicalRouterPortByUUID retrieves a Logical Router Port by UUID
+func (o *LogicalRouterPortOps) GetLogicalRouterPortByUUID(ctx context.Context, uuid string) (*LogicalRouterPort, error) {
+	if uuid == "" {
+		return nil, NewValidationError("uuid", uuid, "uuid is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lrp := &LogicalRouterPort{UUID: uuid}
+	err := nbClient.Get(ctx, lrp)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("LogicalRouterPort", uuid)
+		}
+		return nil, NewTransactionError("GetLogicalRouterPortByUUID", err, uuid)
+	}
+
+	return lrp, nil
+}
+
+// ListLogicalRouterPorts lists all Logical Router Ports
+func (o *LogicalRouterPortOps) ListLogicalRouterPorts(ctx context.Context) ([]*LogicalRouterPort, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var ports []*LogicalRouterPort
+	err := nbClient.List(ctx, &ports)
+	if err != nil {
+		return nil, NewTransactionError("ListLogicalRouterPorts", err, "")
+	}
+
+	return ports, nil
+}
+
+// ListLogicalRouterPortsWithPredicate lists ports matching a predicate
+//
+// Example:
+//
+//	ports, err := ops.ListLogicalRouterPortsWithPredicate(ctx, func(lrp *LogicalRouterPort) bool {
+//	    return strings.HasPrefix(lrp.Name, RouterToSwitchPrefix)
+//	})
+func (o *LogicalRouterPortOps) ListLogicalRouterPortsWithPredicate(ctx context.Context, predicate func(*LogicalRouterPort) bool) ([]*LogicalRouterPort, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var ports []*LogicalRouterPort
+	err := nbClient.WhereCache(func(lrp *LogicalRouterPort) bool {
+		return predicate(lrp)
+	}).List(ctx, &ports)
+	if err != nil {
+		return nil, NewTransactionError("ListLogicalRouterPortsWithPredicate", err, "")
+	}
+
+	return ports, nil
+}
+
+// UpdateLogicalRouterPort updates a Logical Router Port
+func (o *LogicalRouterPortOps) UpdateLogicalRouterPort(ctx context.Context, lrp *LogicalRouterPort, fields ...interface{}) error {
+	if lrp == nil || lrp.Name == "" {
+		return NewValidationError("lrp", lrp, "logical router port with name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	if len(fields) == 0 {
+		fields = getLogicalRouterPortMutableFields(lrp)
+	}
+
+	ops, err := nbClient.Where(lrp).Update(lrp, fields...)
+	if err != nil {
+		return NewTransactionError("UpdateLogicalRouterPort", err, lrp.Name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeleteLogicalRouterPort deletes a Logical Router Port and removes it from its router
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - routerName: Name of the Logical Router containing the port
+//   - portName: Name of the port to delete
+//
+// Returns:
+//   - error: Deletion error (nil if port doesn't exist)
+func (o *LogicalRouterPortOps) DeleteLogicalRouterPort(ctx context.Context, routerName, portName string) error {
+	if routerName == "" {
+		return NewValidationError("routerName", routerName, "router name is required")
+	}
+	if portName == "" {
+		return NewValidationError("portName", portName, "port name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	// Get the port to find its UUID
+	lrp, err := o.GetLogicalRouterPort(ctx, portName)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil // Port doesn't exist, nothing to delete
+		}
+		return err
+	}
+
+	ops, err := o.deleteLogicalRouterPortOps(routerName, lrp)
+	if err != nil {
+		return err
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// SetNetworks updates the networks and MAC address of a Logical Router Port
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - portName: Name of the port
+//   - mac: MAC address
+//   - networks: IP addresses with prefix
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalRouterPortOps) SetNetworks(ctx context.Context, portName, mac string, networks []string) error {
+	lrp, err := o.GetLogicalRouterPort(ctx, portName)
+	if err != nil {
+		return err
+	}
+
+	lrp.MAC = mac
+	lrp.Networks = networks
+	return o.UpdateLogicalRouterPort(ctx, lrp, &lrp.MAC, &lrp.Networks)
+}
+
+// SetOptions sets options on a Logical Router Port
+// Empty values will delete the corresponding keys
+func (o *LogicalRouterPortOps) SetOptions(ctx context.Context, portName string, options map[string]string) error {
+	lrp, err := o.GetLogicalRouterPort(ctx, portName)
+	if err != nil {
+		return err
+	}
+
+	if lrp.Options == nil {
+		lrp.Options = make(map[string]string)
+	}
+
+	for k, v := range options {
+		if v == "" {
+			delete(lrp.Options, k)
+		} else {
+			lrp.Options[k] = v
+		}
+	}
+
+	return o.UpdateLogicalRouterPort(ctx, lrp, &lrp.Options)
+}
+
+// SetExternalIDs sets external_ids on a Logical Router Port
+// Empty values will delete the corresponding keys
+func (o *LogicalRouterPortOps) SetExternalIDs(ctx context.Context, portName string, ids map[string]string) error {
+	lrp, err := o.GetLogicalRouterPort(ctx, portName)
+	if err != nil {
+		return err
+	}
+
+	if lrp.ExternalIDs == nil {
+		lrp.ExternalIDs = make(map[string]string)
+	}
+
+	for k, v := range ids {
+		if v == "" {
+			delete(lrp.ExternalIDs, k)
+		} else {
+			lrp.ExternalIDs[k] = v
+		}
+	}
+
+	return o.UpdateLogicalRouterPort(ctx, lrp, &lrp.ExternalIDs)
+}
+
+// ConnectLogicalSwitch attaches a Logical Switch to a Logical Router
+//
+// This creates (or updates) the router port rtos-<switch> carrying the
+// gateway networks and the peer switch port stor-<switch> of type "router".
+// All changes are applied in a single transaction. The operation is
+// idempotent and may be called on every reconcile.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - routerName: Name of the Logical Router
+//   - switchName: Name of the Logical Switch to attach
+//   - mac: MAC address of the router port
+//   - networks: Gateway IPs with prefix (format: ["10.244.1.1/24"])
+//   - externalIDs: External identifiers set on both ports
+//
+// Returns:
+//   - error: Operation error
+//
+// Example:
+//
+//	err := ops.ConnectLogicalSwitch(ctx, ClusterRouterName, "node-worker1",
+//	    "0a:58:0a:f4:01:01", []string{"10.244.1.1/24"}, nil)
+func (o *LogicalRouterPortOps) ConnectLogicalSwitch(
+	ctx context.Context,
+	routerName, switchName, mac string,
+	networks []string,
+	externalIDs map[string]string,
+) error {
+	if routerName == "" {
+		return NewValidationError("routerName", routerName, "router name is required")
+	}
+	if switchName == "" {
+		return NewValidationError("switchName", switchName, "switch name is required")
+	}
+	if mac == "" {
+		return NewValidationError("mac", mac, "MAC address is required")
+	}
+	if len(networks) == 0 {
+		return NewValidationError("networks", networks, "at least one network is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lrpName := BuildRouterPortName(switchName)
+	lspName := BuildSwitchRouterPortName(switchName)
+	var ops []ovsdb.Operation
+
+	// Router side: create the port or bring MAC/networks up to date
+	existingLRP, err := o.GetLogicalRouterPort(ctx, lrpName)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if existingLRP == nil {
+		lrp := &LogicalRouterPort{
+			UUID:        BuildNamedUUID(lrpName),
+			Name:        lrpName,
+			MAC:         mac,
+			Networks:    networks,
+			ExternalIDs: externalIDs,
+		}
+		createOps, err := nbClient.Create(lrp)
+		if err != nil {
+			return NewTransactionError("ConnectLogicalSwitch", err, lrpName)
+		}
+		lr := &LogicalRouter{Name: routerName}
+		mutateOps, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+			Field:   &lr.Ports,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{lrp.UUID},
+		})
+		if err != nil {
+			return NewTransactionError("ConnectLogicalSwitch", err, lrpName)
+		}
+		ops = append(ops, createOps...)
+		ops = append(ops, mutateOps...)
+	} else if existingLRP.MAC != mac || !stringSlicesEqual(existingLRP.Networks, networks) {
+		existingLRP.MAC = mac
+		existingLRP.Networks = networks
+		updateOps, err := nbClient.Where(existingLRP).Update(existingLRP, &existingLRP.MAC, &existingLRP.Networks)
+		if err != nil {
+			return NewTransactionError("ConnectLogicalSwitch", err, lrpName)
+		}
+		ops = append(ops, updateOps...)
+	}
+
+	// Switch side: the router-type port pointing at the router port
+	lsp := &LogicalSwitchPort{Name: lspName}
+	err = nbClient.Get(ctx, lsp)
+	if err != nil && err != client.ErrNotFound {
+		return NewTransactionError("ConnectLogicalSwitch", err, lspName)
+	}
+
+	if err == client.ErrNotFound {
+		enabled := true
+		lsp = &LogicalSwitchPort{
+			UUID:        BuildNamedUUID(lspName),
+			Name:        lspName,
+			Type:        PortTypeRouter,
+			Addresses:   []string{RouterPortAddresses},
+			Options:     map[string]string{OptionRouterPort: lrpName},
+			ExternalIDs: externalIDs,
+			Enabled:     &enabled,
+		}
+		createOps, err := nbClient.Create(lsp)
+		if err != nil {
+			return NewTransactionError("ConnectLogicalSwitch", err, lspName)
+		}
+		ls := &LogicalSwitch{Name: switchName}
+		mutateOps, err := nbClient.Where(ls).Mutate(ls, model.Mutation{
+			Field:   &ls.Ports,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{lsp.UUID},
+		})
+		if err != nil {
+			return NewTransactionError("ConnectLogicalSwitch", err, lspName)
+		}
+		ops = append(ops, createOps...)
+		ops = append(ops, mutateOps...)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DisconnectLogicalSwitch detaches a Logical Switch from a Logical Router
+//
+// Both the router port rtos-<switch> and the switch port stor-<switch>
+// are removed in a single transaction. Missing ports are ignored.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - routerName: Name of the Logical Router
+//   - switchName: Name of the Logical Switch to detach
+//
+// Returns:
+//   - error: Operation error (nil if already disconnected)
+func (o *LogicalRouterPortOps) DisconnectLogicalSwitch(ctx context.Context, routerName, switchName string) error {
+	if routerName == "" {
+		return NewValidationError("routerName", routerName, "router name is required")
+	}
+	if switchName == "" {
+		return NewValidationError("switchName", switchName, "switch name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	lrpName := BuildRouterPortName(switchName)
+	lspName := BuildSwitchRouterPortName(switchName)
+	var ops []ovsdb.Operation
+
+	lrp, err := o.GetLogicalRouterPort(ctx, lrpName)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	if lrp != nil {
+		deleteOps, err := o.deleteLogicalRouterPortOps(routerName, lrp)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, deleteOps...)
+	}
+
+	lsp := &LogicalSwitchPort{Name: lspName}
+	err = nbClient.Get(ctx, lsp)
+	if err != nil && err != client.ErrNotFound {
+		return NewTransactionError("DisconnectLogicalSwitch", err, lspName)
+	}
+	if err == nil {
+		ls := &LogicalSwitch{Name: switchName}
+		mutateOps, err := nbClient.Where(ls).Mutate(ls, model.Mutation{
+			Field:   &ls.Ports,
+			Mutator: ovsdb.MutateOperationDelete,
+			Value:   []string{lsp.UUID},
+		})
+		if err != nil {
+			return NewTransactionError("DisconnectLogicalSwitch", err, lspName)
+		}
+		deleteOps, err := nbClient.Where(lsp).Delete()
+		if err != nil {
+			return NewTransactionError("DisconnectLogicalSwitch", err, lspName)
+		}
+		ops = append(ops, mutateOps...)
+		ops = append(ops, deleteOps...)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// deleteLogicalRouterPortOps builds operations that detach a port from its
+// router and delete it
+func (o *LogicalRouterPortOps) deleteLogicalRouterPortOps(routerName string, lrp *LogicalRouterPort) ([]ovsdb.Operation, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	lr := &LogicalRouter{Name: routerName}
+	mutateOps, err := nbClient.Where(lr).Mutate(lr, model.Mutation{
+		Field:   &lr.Ports,
+		Mutator: ovsdb.MutateOperationDelete,
+		Value:   []string{lrp.UUID},
+	})
+	if err != nil {
+		return nil, NewTransactionError("DeleteLogicalRouterPort", err, lrp.Name)
+	}
+
+	deleteOps, err := nbClient.Where(lrp).Delete()
+	if err != nil {
+		return nil, NewTransactionError("DeleteLogicalRouterPort", err, lrp.Name)
+	}
+
+	return append(mutateOps, deleteOps...), nil
+}
+
+// BuildRouterPortName builds the router port name for a switch
+// Format: rtos-<switchName>
+func BuildRouterPortName(switchName string) string {
+	return RouterToSwitchPrefix + switchName
+}
+
+// BuildSwitchRouterPortName builds the switch-side router port name
+// Format: stor-<switchName>
+func BuildSwitchRouterPortName(switchName string) string {
+	return SwitchToRouterPrefix + switchName
+}
+
+// BuildRouterPortNetwork builds a router port network from a gateway IP and
+// the CIDR it belongs to
+//
+// Parameters:
+//   - gateway: Gateway IP (e.g., "10.244.1.1")
+//   - cidr: Subnet CIDR (e.g., "10.244.1.0/24")
+//
+// Returns:
+//   - string: Gateway with prefix length (e.g., "10.244.1.1/24")
+//   - error: Parse error, or gateway outside the CIDR
+func BuildRouterPortNetwork(gateway, cidr string) (string, error) {
+	gwIP := net.ParseIP(gateway)
+	if gwIP == nil {
+		return "", fmt.Errorf("invalid gateway IP: %s", gateway)
+	}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDR %s: %w", cidr, err)
+	}
+
+	if !ipNet.Contains(gwIP) {
+		return "", fmt.Errorf("gateway %s is not within %s", gateway, cidr)
+	}
+
+	ones, _ := ipNet.Mask.Size()
+	return fmt.Sprintf("%s/%d", gwIP.String(), ones), nil
+}
+
+// stringSlicesEqual compares two string slices element by element
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getLogicalRouterPortMutableFields returns the mutable fields of a LogicalRouterPort
+func getLogicalRouterPortMutableFields(lrp *LogicalRouterPort) []interface{} {
+	fields := []interface{}{}
+	if lrp.MAC != "" {
+		fields = append(fields, &lrp.MAC)
+	}
+	if lrp.Networks != nil {
+		fields = append(fields, &lrp.Networks)
+	}
+	if lrp.Options != nil {
+		fields = append(fields, &lrp.Options)
+	}
+	if lrp.ExternalIDs != nil {
+		fields = append(fields, &lrp.ExternalIDs)
+	}
+	return fields
+}
@@ -0,0 +1,82 @@
+// Package ovndb provides tests for Logical Router Port helpers.
+package ovndb
+
+import (
+	"testing"
+)
+
+// TestBuildRouterPortNetwork tests building router port networks from a gateway and CIDR.
+func TestBuildRouterPortNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		gateway  string
+		cidr     string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "node subnet gateway",
+			gateway:  "10.244.1.1",
+			cidr:     "10.244.1.0/24",
+			expected: "10.244.1.1/24",
+		},
+		{
+			name:     "custom subnet gateway",
+			gateway:  "192.168.100.254",
+			cidr:     "192.168.100.0/24",
+			expected: "192.168.100.254/24",
+		},
+		{
+			name:     "IPv6 gateway",
+			gateway:  "fd00:10:244::1",
+			cidr:     "fd00:10:244::/64",
+			expected: "fd00:10:244::1/64",
+		},
+		{
+			name:    "gateway outside CIDR",
+			gateway: "10.245.0.1",
+			cidr:    "10.244.1.0/24",
+			wantErr: true,
+		},
+		{
+			name:    "invalid gateway",
+			gateway: "not-an-ip",
+			cidr:    "10.244.1.0/24",
+			wantErr: true,
+		},
+		{
+			name:    "invalid CIDR",
+			gateway: "10.244.1.1",
+			cidr:    "10.244.1.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildRouterPortNetwork(tt.gateway, tt.cidr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("BuildRouterPortNetwork(%q, %q) expected error, got %q", tt.gateway, tt.cidr, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildRouterPortNetwork(%q, %q) unexpected error: %v", tt.gateway, tt.cidr, err)
+			}
+			if result != tt.expected {
+				t.Errorf("BuildRouterPortNetwork(%q, %q) = %q, want %q", tt.gateway, tt.cidr, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBuildRouterPortNames tests the router and switch port naming helpers.
+func TestBuildRouterPortNames(t *testing.T) {
+	if got := BuildRouterPortName("node-worker1"); got != "rtos-node-worker1" {
+		t.Errorf("BuildRouterPortName() = %q, want %q", got, "rtos-node-worker1")
+	}
+	if got := BuildSwitchRouterPortName("subnet-default"); got != "stor-subnet-default" {
+		t.Errorf("BuildSwitchRouterPortName() = %q, want %q", got, "stor-subnet-default")
+	}
+}
@@ -507,6 +507,35 @@ func (o *LogicalSwitchOps) RemoveLoadBalancersFromLogicalSwitch(ctx context.Cont
 	return err
 }
 
+// AddLoadBalancerGroupsToLogicalSwitch adds Load Balancer Groups to a Logical Switch
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the switch
+//   - groupUUIDs: UUIDs of Load Balancer Groups to add
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalSwitchOps) AddLoadBalancerGroupsToLogicalSwitch(ctx context.Context, name string, groupUUIDs ...string) error {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	ls := &LogicalSwitch{Name: name}
+	ops, err := nbClient.Where(ls).Mutate(ls, model.Mutation{
+		Field:   &ls.LoadBalancerGroup,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   groupUUIDs,
+	})
+	if err != nil {
+		return NewTransactionError("AddLoadBalancerGroupsToLogicalSwitch", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
 // getLogicalSwitchMutableFields returns the mutable fields of a LogicalSwitch
 func getLogicalSwitchMutableFields(ls *LogicalSwitch) []interface{} {
 	fields := []interface{}{}
@@ -28,6 +28,7 @@ package ovndb
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/ovn-org/libovsdb/client"
@@ -80,6 +81,12 @@ const (
 	OptionNetworkName = "network_name"
 )
 
+// LocalnetPortPrefix is the name prefix of localnet ports
+const LocalnetPortPrefix = "ln-"
+
+// LocalnetPortAddresses lets a localnet port forward traffic for any MAC
+const LocalnetPortAddresses = "unknown"
+
 // LogicalSwitchPortOps provides operations on OVN Logical Switch Ports
 type LogicalSwitchPortOps struct {
 	client *Client
@@ -263,6 +270,86 @@ func (o *LogicalSwitchPortOps) CreateLogicalSwitchPortWithOptions(
 	return lsp, nil
 }
 
+// EnsureLocalnetPort creates or updates the localnet port of a Logical Switch
+//
+// The localnet port bridges the switch to a physical network. ovn-controller
+// attaches it to the OVS bridge mapped to networkName by ovn-bridge-mappings,
+// tagging traffic with vlanID.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - switchName: Name of the Logical Switch
+//   - networkName: Physical network name (the provider in ovn-bridge-mappings)
+//   - vlanID: VLAN tag, or 0 for untagged traffic
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - error: Operation error
+func (o *LogicalSwitchPortOps) EnsureLocalnetPort(
+	ctx context.Context,
+	switchName, networkName string,
+	vlanID int,
+	externalIDs map[string]string,
+) error {
+	if switchName == "" {
+		return NewValidationError("switchName", switchName, "switch name is required")
+	}
+	if networkName == "" {
+		return NewValidationError("networkName", networkName, "network name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	portName := BuildLocalnetPortName(switchName)
+	options := map[string]string{OptionNetworkName: networkName}
+	var tag *int
+	if vlanID > 0 {
+		tag = &vlanID
+	}
+
+	existing, err := o.GetLogicalSwitchPort(ctx, portName)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if existing == nil {
+		lsp := &LogicalSwitchPort{
+			UUID:        BuildNamedUUID(portName),
+			Name:        portName,
+			Type:        PortTypeLocalnet,
+			Addresses:   []string{LocalnetPortAddresses},
+			Options:     options,
+			ExternalIDs: externalIDs,
+			TagRequest:  tag,
+		}
+		createOps, err := nbClient.Create(lsp)
+		if err != nil {
+			return NewTransactionError("EnsureLocalnetPort", err, portName)
+		}
+		ls := &LogicalSwitch{Name: switchName}
+		mutateOps, err := nbClient.Where(ls).Mutate(ls, model.Mutation{
+			Field:   &ls.Ports,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{lsp.UUID},
+		})
+		if err != nil {
+			return NewTransactionError("EnsureLocalnetPort", err, portName)
+		}
+		_, err = TransactAndCheck(nbClient, append(createOps, mutateOps...), o.client.GetTxnTimeout())
+		return err
+	}
+
+	if existing.Options[OptionNetworkName] == networkName && intPtrEqual(existing.TagRequest, tag) {
+		return nil
+	}
+	existing.Options = options
+	existing.TagRequest = tag
+	return o.UpdateLogicalSwitchPort(ctx, existing, &existing.Options, &existing.TagRequest)
+}
+
 // GetLogicalSwitchPort retrieves a Logical Switch Port by name
 //
 // Parameters:
@@ -536,12 +623,39 @@ func (o *LogicalSwitchPortOps) SetEnabled(ctx context.Context, portName string,
 	return o.UpdateLogicalSwitchPort(ctx, lsp, &lsp.Enabled)
 }
 
+// SetDHCPOptions sets the DHCP_Options rows referenced by a Logical Switch Port
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - portName: Name of the port
+//   - v4: UUID of the DHCPv4 options row, or nil to disable DHCPv4
+//   - v6: UUID of the DHCPv6 options row, or nil to disable DHCPv6
+//
+// Returns:
+//   - error: Update error
+func (o *LogicalSwitchPortOps) SetDHCPOptions(ctx context.Context, portName string, v4, v6 *string) error {
+	lsp, err := o.GetLogicalSwitchPort(ctx, portName)
+	if err != nil {
+		return err
+	}
+
+	lsp.Dhcpv4Options = v4
+	lsp.Dhcpv6Options = v6
+	return o.UpdateLogicalSwitchPort(ctx, lsp, &lsp.Dhcpv4Options, &lsp.Dhcpv6Options)
+}
+
 // BuildPortName builds a port name from namespace and pod name
 // Format: namespace_podName
 func BuildPortName(namespace, podName string) string {
 	return fmt.Sprintf("%s_%s", namespace, podName)
 }
 
+// BuildLocalnetPortName builds the localnet port name for a switch
+// Format: ln-<switchName>
+func BuildLocalnetPortName(switchName string) string {
+	return LocalnetPortPrefix + switchName
+}
+
 // ParsePortName parses a port name into namespace and pod name
 func ParsePortName(portName string) (namespace, podName string, err error) {
 	parts := strings.SplitN(portName, "_", 2)
@@ -563,6 +677,26 @@ func buildAddresses(mac string, ips []string) string {
 	return fmt.Sprintf("%s %s", mac, strings.Join(ips, " "))
 }
 
+// ParseAddresses parses a Logical Switch Port addresses entry into MAC and IPs
+// Format: "MAC IP1 IP2 ..."
+// Special entries such as "router", "unknown" and "dynamic" yield no MAC or IPs.
+func ParseAddresses(addresses string) (mac string, ips []string) {
+	fields := strings.Fields(addresses)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if _, err := net.ParseMAC(fields[0]); err != nil {
+		return "", nil
+	}
+	for _, field := range fields[1:] {
+		ip := strings.Split(field, "/")[0]
+		if net.ParseIP(ip) != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return fields[0], ips
+}
+
 // getLogicalSwitchPortMutableFields returns the mutable fields of a LogicalSwitchPort
 func getLogicalSwitchPortMutableFields(lsp *LogicalSwitchPort) []interface{} {
 	fields := []interface{}{}
@@ -583,3 +717,11 @@ func getLogicalSwitchPortMutableFields(lsp *LogicalSwitchPort) []interface{} {
 	}
 	return fields
 }
+
+// intPtrEqual compares two optional integers
+func intPtrEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
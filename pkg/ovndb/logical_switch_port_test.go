@@ -0,0 +1,59 @@
+// Package ovndb provides tests for Logical Switch Port helpers.
+package ovndb
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseAddresses tests parsing Logical Switch Port addresses entries.
+func TestParseAddresses(t *testing.T) {
+	tests := []struct {
+		name        string
+		addresses   string
+		expectedMAC string
+		expectedIPs []string
+	}{
+		{
+			name:        "MAC and IP",
+			addresses:   "0a:58:0a:f4:01:05 10.244.1.5",
+			expectedMAC: "0a:58:0a:f4:01:05",
+			expectedIPs: []string{"10.244.1.5"},
+		},
+		{
+			name:        "dual-stack with prefixes",
+			addresses:   "0a:58:0a:f4:01:05 10.244.1.5/24 fd00:10:244::5/64",
+			expectedMAC: "0a:58:0a:f4:01:05",
+			expectedIPs: []string{"10.244.1.5", "fd00:10:244::5"},
+		},
+		{
+			name:        "MAC only",
+			addresses:   "0a:58:0a:f4:01:05",
+			expectedMAC: "0a:58:0a:f4:01:05",
+		},
+		{
+			name:      "router port",
+			addresses: "router",
+		},
+		{
+			name:      "unknown",
+			addresses: "unknown",
+		},
+		{
+			name:      "empty",
+			addresses: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mac, ips := ParseAddresses(tt.addresses)
+			if mac != tt.expectedMAC {
+				t.Errorf("ParseAddresses(%q) MAC = %q, want %q", tt.addresses, mac, tt.expectedMAC)
+			}
+			if !reflect.DeepEqual(ips, tt.expectedIPs) {
+				t.Errorf("ParseAddresses(%q) IPs = %v, want %v", tt.addresses, ips, tt.expectedIPs)
+			}
+		})
+	}
+}
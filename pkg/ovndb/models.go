@@ -9,6 +9,8 @@
 // - Logical_Router: Virtual L3 router
 // - Logical_Router_Port: Port on a logical router
 // - Load_Balancer: L4 load balancer for services
+// - Load_Balancer_Group: Set of load balancers shared by switches and routers
+// - Chassis_Template_Var: Per-chassis values of load balancer template variables
 // - ACL: Access control list for network policies
 // - Address_Set: Set of IP addresses for ACL matching
 // - Port_Group: Group of ports for ACL matching
@@ -91,17 +93,18 @@ type LogicalSwitchPort struct {
 // A logical router provides L3 routing between logical switches.
 // In Kubernetes context, it routes traffic between different subnets and to external networks.
 type LogicalRouter struct {
-	UUID         string            `ovsdb:"_uuid"`
-	Name         string            `ovsdb:"name"`
-	Ports        []string          `ovsdb:"ports"`
-	StaticRoutes []string          `ovsdb:"static_routes"`
-	Policies     []string          `ovsdb:"policies"`
-	Nat          []string          `ovsdb:"nat"`
-	LoadBalancer []string          `ovsdb:"load_balancer"`
-	Options      map[string]string `ovsdb:"options"`
-	ExternalIDs  map[string]string `ovsdb:"external_ids"`
-	Enabled      *bool             `ovsdb:"enabled"`
-	Copp         *string           `ovsdb:"copp"`
+	UUID              string            `ovsdb:"_uuid"`
+	Name              string            `ovsdb:"name"`
+	Ports             []string          `ovsdb:"ports"`
+	StaticRoutes      []string          `ovsdb:"static_routes"`
+	Policies          []string          `ovsdb:"policies"`
+	Nat               []string          `ovsdb:"nat"`
+	LoadBalancer      []string          `ovsdb:"load_balancer"`
+	LoadBalancerGroup []string          `ovsdb:"load_balancer_group"`
+	Options           map[string]string `ovsdb:"options"`
+	ExternalIDs       map[string]string `ovsdb:"external_ids"`
+	Enabled           *bool             `ovsdb:"enabled"`
+	Copp              *string           `ovsdb:"copp"`
 }
 
 // LogicalRouterPort represents an OVN Logical Router Port
@@ -206,6 +209,49 @@ type PortGroup struct {
 	ExternalIDs map[string]string `ovsdb:"external_ids"`
 }
 
+// LoadBalancerGroup represents an OVN Load Balancer Group
+// A group is a set of load balancers that Logical Switches and Logical Routers
+// reference as a whole, so adding a load balancer to the group applies it to
+// every datapath referencing the group.
+type LoadBalancerGroup struct {
+	UUID         string   `ovsdb:"_uuid"`
+	Name         string   `ovsdb:"name"`
+	LoadBalancer []string `ovsdb:"load_balancer"`
+}
+
+// ChassisTemplateVar represents an OVN Chassis_Template_Var row
+// A row holds the template variables of one chassis. Templated load balancers
+// reference a variable as "^NAME", and ovn-controller on each chassis expands
+// it to the value from that chassis' row.
+type ChassisTemplateVar struct {
+	UUID        string            `ovsdb:"_uuid"`
+	Chassis     string            `ovsdb:"chassis"`
+	Variables   map[string]string `ovsdb:"variables"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+}
+
+// DHCPOptions represents an OVN DHCP_Options row
+// A row holds the DHCP options of one CIDR. Logical switch ports reference it
+// through dhcpv4_options or dhcpv6_options, and ovn-controller answers their
+// DHCP requests locally.
+type DHCPOptions struct {
+	UUID        string            `ovsdb:"_uuid"`
+	Cidr        string            `ovsdb:"cidr"`
+	Options     map[string]string `ovsdb:"options"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+}
+
+// LoadBalancerHealthCheck represents an OVN Load_Balancer_Health_Check row
+// A row enables health checks for one VIP of the Load Balancer referencing
+// it. ovn-controller probes the backends listed in the Load Balancer's
+// ip_port_mappings and northd removes the ones that fail from the VIP.
+type LoadBalancerHealthCheck struct {
+	UUID        string            `ovsdb:"_uuid"`
+	Vip         string            `ovsdb:"vip"`
+	Options     map[string]string `ovsdb:"options"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+}
+
 // NBGlobal represents the NB_Global table
 // Contains global configuration for the OVN Northbound database.
 type NBGlobal struct {
@@ -297,9 +343,13 @@ const (
 	LogicalRouterTable     = "Logical_Router"
 	LogicalRouterPortTable = "Logical_Router_Port"
 	LoadBalancerTable      = "Load_Balancer"
+	LBHealthCheckTable     = "Load_Balancer_Health_Check"
+	LBGroupTable           = "Load_Balancer_Group"
+	TemplateVarTable       = "Chassis_Template_Var"
 	ACLTable               = "ACL"
 	AddressSetTable        = "Address_Set"
 	PortGroupTable         = "Port_Group"
+	DHCPOptionsTable       = "DHCP_Options"
 	NBGlobalTable          = "NB_Global"
 	ChassisTable           = "Chassis"
 	EncapTable             = "Encap"
@@ -315,9 +365,13 @@ func NBDBModel() (model.ClientDBModel, error) {
 		LogicalRouterTable:     &LogicalRouter{},
 		LogicalRouterPortTable: &LogicalRouterPort{},
 		LoadBalancerTable:      &LoadBalancer{},
+		LBHealthCheckTable:     &LoadBalancerHealthCheck{},
+		LBGroupTable:           &LoadBalancerGroup{},
+		TemplateVarTable:       &ChassisTemplateVar{},
 		ACLTable:               &ACL{},
 		AddressSetTable:        &AddressSet{},
 		PortGroupTable:         &PortGroup{},
+		DHCPOptionsTable:       &DHCPOptions{},
 		NBGlobalTable:          &NBGlobal{},
 	})
 }
@@ -0,0 +1,109 @@
+// Package ovndb provides NB_Global operations.
+//
+// This file implements operations for the OVN NB_Global table.
+// NB_Global holds a single row with the northbound-wide configuration,
+// read by ovn-northd from its options column.
+//
+// In Kubernetes context:
+// - The Service hairpin SNAT IP of the cluster is recorded in options
+//
+// Key OVN NB_Global fields:
+// - options: Global options (e.g., "svc_monitor_mac", "hairpin_snat_ip")
+// - external_ids: External identifiers
+//
+// Reference: ovn-nb(5) NB_Global, OVN-Kubernetes pkg/libovsdb/ops/northbound.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// NBGlobalOptionHairpinSNATIP records the cluster-wide source IP of
+	// hairpinned Service traffic. northd applies the Load Balancer option
+	// of the same name (LBOptionHairpinSNATIP) set on each Load Balancer.
+	NBGlobalOptionHairpinSNATIP = "hairpin_snat_ip"
+)
+
+// NBGlobalOps provides operations on the OVN NB_Global table
+type NBGlobalOps struct {
+	client *Client
+}
+
+// NewNBGlobalOps creates a new NBGlobalOps
+func NewNBGlobalOps(c *Client) *NBGlobalOps {
+	return &NBGlobalOps{client: c}
+}
+
+// GetNBGlobal retrieves the NB_Global row
+//
+// Returns:
+//   - *NBGlobal: The NB_Global row
+//   - error: ObjectNotFoundError if the table is empty, or other error
+func (o *NBGlobalOps) GetNBGlobal(ctx context.Context) (*NBGlobal, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var rows []*NBGlobal
+	if err := nbClient.List(ctx, &rows); err != nil {
+		return nil, NewTransactionError("GetNBGlobal", err, "")
+	}
+	if len(rows) == 0 {
+		return nil, NewObjectNotFoundError("NBGlobal", "")
+	}
+
+	return rows[0], nil
+}
+
+// SetOptions sets options on the NB_Global row
+// Empty values will delete the corresponding keys. Nothing is written if
+// every option already has the desired value.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - options: Options to set
+//
+// Returns:
+//   - error: Operation error
+func (o *NBGlobalOps) SetOptions(ctx context.Context, options map[string]string) error {
+	nbGlobal, err := o.GetNBGlobal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if nbGlobal.Options == nil {
+		nbGlobal.Options = make(map[string]string)
+	}
+
+	changed := false
+	for k, v := range options {
+		current, ok := nbGlobal.Options[k]
+		switch {
+		case v == "" && ok:
+			delete(nbGlobal.Options, k)
+			changed = true
+		case v != "" && current != v:
+			nbGlobal.Options[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	ops, err := nbClient.Where(nbGlobal).Update(nbGlobal, &nbGlobal.Options)
+	if err != nil {
+		return NewTransactionError("SetNBGlobalOptions", err, nbGlobal.UUID)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
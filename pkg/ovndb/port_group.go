@@ -0,0 +1,388 @@
+// Package ovndb provides Port Group operations.
+//
+// This file implements CRUD operations for OVN Port Groups.
+// A Port Group is a named group of Logical Switch Ports. ACLs can be attached
+// directly to a Port Group, and match expressions can reference the group as
+// "@<name>", so a single ACL covers every member port.
+//
+// In Kubernetes context:
+// - Each NetworkPolicy maps to one Port Group holding the ports of the selected Pods
+// - The policy ACLs are attached to the Port Group instead of a Logical Switch
+// - Pod add/remove only changes the group membership, the ACLs stay untouched
+//
+// Key OVN Port Group fields:
+// - name: Unique identifier (must be a valid OVN identifier)
+// - ports: Logical Switch Port UUIDs (weak references)
+// - acls: ACL UUIDs applied to all member ports
+// - external_ids: External identifiers (owning NetworkPolicy, etc.)
+//
+// Reference: OVN-Kubernetes pkg/libovsdb/ops/portgroup.go
+package ovndb
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// External ID keys for Port Groups
+const (
+	// PortGroupExternalIDName is the key for the unhashed port group name
+	PortGroupExternalIDName = "k8s.ovn.org/name"
+)
+
+// PortGroupOps provides operations on OVN Port Groups
+type PortGroupOps struct {
+	client *Client
+}
+
+// NewPortGroupOps creates a new PortGroupOps
+func NewPortGroupOps(c *Client) *PortGroupOps {
+	return &PortGroupOps{client: c}
+}
+
+// CreatePortGroup creates a new Port Group
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Unique name for the group (see BuildPortGroupName)
+//   - ports: Logical Switch Port UUIDs
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - *PortGroup: The created group with UUID populated
+//   - error: Creation error
+//
+// Example:
+//
+//	pg, err := ops.CreatePortGroup(ctx, BuildPortGroupName("default/allow-web"),
+//	    []string{lsp.UUID}, map[string]string{"k8s.ovn.org/policy": "default/allow-web"})
+func (o *PortGroupOps) CreatePortGroup(ctx context.Context, name string, ports []string, externalIDs map[string]string) (*PortGroup, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	pg := &PortGroup{
+		UUID:        BuildNamedUUID(name),
+		Name:        name,
+		Ports:       ports,
+		ExternalIDs: externalIDs,
+	}
+
+	ops, err := nbClient.Create(pg)
+	if err != nil {
+		return nil, NewTransactionError("CreatePortGroup", err, name)
+	}
+
+	results, err := TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) > 0 {
+		pg.UUID = GetUUIDFromResult(results[0])
+	}
+
+	return pg, nil
+}
+
+// GetPortGroup retrieves a Port Group by name
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the group to retrieve
+//
+// Returns:
+//   - *PortGroup: The found group
+//   - error: ObjectNotFoundError if not found, or other error
+func (o *PortGroupOps) GetPortGroup(ctx context.Context, name string) (*PortGroup, error) {
+	if name == "" {
+		return nil, NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	pg := &PortGroup{Name: name}
+	err := nbClient.Get(ctx, pg)
+	if err != nil {
+		if err == client.ErrNotFound {
+			return nil, NewObjectNotFoundError("PortGroup", name)
+		}
+		return nil, NewTransactionError("GetPortGroup", err, name)
+	}
+
+	return pg, nil
+}
+
+// ListPortGroupsWithPredicate lists Port Groups matching a predicate
+//
+// Example:
+//
+//	groups, err := ops.ListPortGroupsWithPredicate(ctx, func(pg *PortGroup) bool {
+//	    return pg.ExternalIDs["k8s.ovn.org/namespace"] == "default"
+//	})
+func (o *PortGroupOps) ListPortGroupsWithPredicate(ctx context.Context, predicate func(*PortGroup) bool) ([]*PortGroup, error) {
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return nil, fmt.Errorf("NB client is not connected")
+	}
+
+	var groups []*PortGroup
+	err := nbClient.WhereCache(func(pg *PortGroup) bool {
+		return predicate(pg)
+	}).List(ctx, &groups)
+	if err != nil {
+		return nil, NewTransactionError("ListPortGroupsWithPredicate", err, "")
+	}
+
+	return groups, nil
+}
+
+// CreateOrUpdatePortGroup creates a Port Group or replaces its member ports
+//
+// If the group exists, its ports and external_ids are replaced when they
+// differ; the attached ACLs are left untouched. If the group doesn't exist,
+// it is created without ACLs.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the group
+//   - ports: Desired Logical Switch Port UUIDs
+//   - externalIDs: External identifiers
+//
+// Returns:
+//   - error: Operation error
+func (o *PortGroupOps) CreateOrUpdatePortGroup(ctx context.Context, name string, ports []string, externalIDs map[string]string) error {
+	existing, err := o.GetPortGroup(ctx, name)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if existing == nil {
+		_, err = o.CreatePortGroup(ctx, name, ports, externalIDs)
+		return err
+	}
+
+	if stringSetsEqual(existing.Ports, ports) && mapsEqualStr(existing.ExternalIDs, externalIDs) {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	existing.Ports = ports
+	if existing.Ports == nil {
+		existing.Ports = []string{}
+	}
+	existing.ExternalIDs = externalIDs
+	ops, err := nbClient.Where(existing).Update(existing, &existing.Ports, &existing.ExternalIDs)
+	if err != nil {
+		return NewTransactionError("CreateOrUpdatePortGroup", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// AddPorts adds Logical Switch Ports to a Port Group
+func (o *PortGroupOps) AddPorts(ctx context.Context, name string, portUUIDs ...string) error {
+	return o.mutatePortGroup(ctx, "AddPorts", name, ovsdb.MutateOperationInsert, portUUIDs, false)
+}
+
+// RemovePorts removes Logical Switch Ports from a Port Group
+func (o *PortGroupOps) RemovePorts(ctx context.Context, name string, portUUIDs ...string) error {
+	return o.mutatePortGroup(ctx, "RemovePorts", name, ovsdb.MutateOperationDelete, portUUIDs, false)
+}
+
+// AddACLs attaches existing ACLs to a Port Group
+func (o *PortGroupOps) AddACLs(ctx context.Context, name string, aclUUIDs ...string) error {
+	return o.mutatePortGroup(ctx, "AddACLs", name, ovsdb.MutateOperationInsert, aclUUIDs, true)
+}
+
+// RemoveACLs detaches ACLs from a Port Group
+// ACLs no longer referenced by any Port Group or Logical Switch are
+// garbage collected by OVSDB.
+func (o *PortGroupOps) RemoveACLs(ctx context.Context, name string, aclUUIDs ...string) error {
+	return o.mutatePortGroup(ctx, "RemoveACLs", name, ovsdb.MutateOperationDelete, aclUUIDs, true)
+}
+
+// mutatePortGroup inserts or deletes UUIDs in the ports or acls column
+func (o *PortGroupOps) mutatePortGroup(ctx context.Context, opName, name string, mutator ovsdb.Mutator, uuids []string, acls bool) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+	if len(uuids) == 0 {
+		return nil
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	pg := &PortGroup{Name: name}
+	field := interface{}(&pg.Ports)
+	if acls {
+		field = &pg.ACLs
+	}
+
+	ops, err := nbClient.Where(pg).Mutate(pg, model.Mutation{
+		Field:   field,
+		Mutator: mutator,
+		Value:   uuids,
+	})
+	if err != nil {
+		return NewTransactionError(opName, err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// SetACLs replaces the ACLs attached to a Port Group
+//
+// The desired ACLs are compared with the ACLs currently attached to the
+// group. If they are equivalent (same direction, priority, match, action
+// and name), nothing is written. Otherwise the new ACLs are created and
+// swapped in within a single transaction; the previous ACLs are garbage
+// collected by OVSDB once no longer referenced.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Name of the group
+//   - acls: Desired ACLs (built with BuildACL, UUID not set)
+//
+// Returns:
+//   - error: Operation error
+func (o *PortGroupOps) SetACLs(ctx context.Context, name string, acls []*ACL) error {
+	pg, err := o.GetPortGroup(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	// Compare with the ACLs currently attached
+	existing := make([]*ACL, 0, len(pg.ACLs))
+	for _, uuid := range pg.ACLs {
+		acl := &ACL{UUID: uuid}
+		if err := nbClient.Get(ctx, acl); err != nil {
+			if err == client.ErrNotFound {
+				continue
+			}
+			return NewTransactionError("SetACLs", err, name)
+		}
+		existing = append(existing, acl)
+	}
+	if aclSetsEqual(existing, acls) {
+		return nil
+	}
+
+	var ops []ovsdb.Operation
+	aclUUIDs := make([]string, 0, len(acls))
+	for i, acl := range acls {
+		acl.UUID = BuildNamedUUID(fmt.Sprintf("%s-acl-%d", name, i))
+		createOps, err := nbClient.Create(acl)
+		if err != nil {
+			return NewTransactionError("SetACLs", err, name)
+		}
+		ops = append(ops, createOps...)
+		aclUUIDs = append(aclUUIDs, acl.UUID)
+	}
+
+	pg.ACLs = aclUUIDs
+	updateOps, err := nbClient.Where(pg).Update(pg, &pg.ACLs)
+	if err != nil {
+		return NewTransactionError("SetACLs", err, name)
+	}
+	ops = append(ops, updateOps...)
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// DeletePortGroup deletes a Port Group by name
+// ACLs attached only to this group are garbage collected by OVSDB.
+//
+// Returns:
+//   - error: Deletion error (nil if group doesn't exist)
+func (o *PortGroupOps) DeletePortGroup(ctx context.Context, name string) error {
+	if name == "" {
+		return NewValidationError("name", name, "name is required")
+	}
+
+	nbClient := o.client.NBClient()
+	if nbClient == nil {
+		return fmt.Errorf("NB client is not connected")
+	}
+
+	pg := &PortGroup{Name: name}
+	ops, err := nbClient.Where(pg).Delete()
+	if err != nil {
+		return NewTransactionError("DeletePortGroup", err, name)
+	}
+
+	_, err = TransactAndCheck(nbClient, ops, o.client.GetTxnTimeout())
+	return err
+}
+
+// BuildPortGroupName builds a valid OVN Port Group name from an arbitrary key
+//
+// OVN identifiers may only contain letters, digits and underscores, so the
+// key is hashed. The unhashed key should be stored in external_ids under
+// PortGroupExternalIDName for debugging.
+//
+// Format: pg<fnv64 hash of key>
+func BuildPortGroupName(key string) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return fmt.Sprintf("pg%d", h.Sum64())
+}
+
+// BuildPortGroupReference builds the match expression reference to a Port Group
+// Format: @<name>
+func BuildPortGroupReference(name string) string {
+	return "@" + name
+}
+
+// aclKey returns the fields that identify an ACL's behavior
+func aclKey(acl *ACL) string {
+	name := ""
+	if acl.Name != nil {
+		name = *acl.Name
+	}
+	return fmt.Sprintf("%s|%d|%s|%s|%s", acl.Direction, acl.Priority, acl.Action, acl.Match, name)
+}
+
+// aclSetsEqual compares two ACL lists ignoring order and UUIDs
+func aclSetsEqual(a, b []*ACL) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keysA := make([]string, len(a))
+	for i, acl := range a {
+		keysA[i] = aclKey(acl)
+	}
+	keysB := make([]string, len(b))
+	for i, acl := range b {
+		keysB[i] = aclKey(acl)
+	}
+	return stringSetsEqual(keysA, keysB)
+}
@@ -15,6 +15,10 @@ const (
 	BrInt = "br-int" // Integration bridge for Pod traffic
 	BrEx  = "br-ex"  // External bridge for gateway traffic
 
+	// DefaultProvider is the physical network mapped to br-ex, used by
+	// underlay Subnets without a provider
+	DefaultProvider = "physnet1"
+
 	// Tunnel Types
 	TunnelTypeVXLAN  = "vxlan"
 	TunnelTypeGeneve = "geneve"
@@ -41,6 +41,33 @@ const (
 
 	// PodLogicalSwitchPortAnnotationKey stores the OVN Logical Switch Port name.
 	PodLogicalSwitchPortAnnotationKey = "zstack.io/logical-switch-port"
+
+	// PodStaticIPAnnotationKey requests fixed IP addresses for the Pod.
+	// Set by the user; one IP per IP family, separated by commas
+	// (e.g., "10.244.1.50" or "10.244.1.50,fd00:10:244:1::50").
+	PodStaticIPAnnotationKey = "zstack.io/ip-address"
+
+	// PodStaticMACAnnotationKey requests a fixed MAC address for the Pod.
+	// Set by the user (e.g., "00:00:00:53:12:4f"); must be outside the
+	// generated range (GeneratedMACPrefix).
+	PodStaticMACAnnotationKey = "zstack.io/mac-address"
+
+	// PodIPPoolAnnotationKey lists the IP addresses the Pods of a workload
+	// may use. Set by the user on the Pod template, separated by commas
+	// (e.g., "10.244.1.50,10.244.1.51,10.244.1.52").
+	PodIPPoolAnnotationKey = "zstack.io/ip-pool"
+
+	// PodDPDKAnnotationKey requests a DPDK vhost-user port instead of a veth
+	// pair. Set by the user to "true".
+	PodDPDKAnnotationKey = "zstack.io/dpdk"
+
+	// PodDPDKSocketAnnotationKey stores the vhost-user socket path of a DPDK
+	// Pod. Set by the node agent.
+	PodDPDKSocketAnnotationKey = "zstack.io/dpdk-socket"
+
+	// DPDKResourceName is the extended resource a Pod can request, instead
+	// of setting PodDPDKAnnotationKey, to get a DPDK vhost-user port.
+	DPDKResourceName corev1.ResourceName = "zstack.io/dpdk"
 )
 
 // PodAnnotation represents the network configuration stored in Pod annotation.
@@ -327,6 +354,18 @@ func (a *PodAnnotation) GetIP() string {
 	return strings.Split(a.IPAddresses[0], "/")[0]
 }
 
+// GetIPs returns all IP addresses without prefix.
+//
+// Returns:
+//   - []string: IP addresses without prefix, one per IP family
+func (a *PodAnnotation) GetIPs() []string {
+	ips := make([]string, 0, len(a.IPAddresses))
+	for _, ip := range a.IPAddresses {
+		ips = append(ips, strings.Split(ip, "/")[0])
+	}
+	return ips
+}
+
 // GetIPWithPrefix returns the first IP address with prefix.
 //
 // Returns:
@@ -385,6 +424,25 @@ func GetPodIP(pod *corev1.Pod) string {
 	return annotation.GetIP()
 }
 
+// GetPodIPs returns all Pod IPs from the annotation, one per IP family.
+// Returns nil if annotation is not set or invalid.
+//
+// Parameters:
+//   - pod: The Pod to get IPs from
+//
+// Returns:
+//   - []string: Pod IP addresses without prefix
+func GetPodIPs(pod *corev1.Pod) []string {
+	annotation, err := GetPodAnnotation(pod)
+	if err != nil || annotation == nil {
+		if ip := GetPodIP(pod); ip != "" {
+			return []string{ip}
+		}
+		return nil
+	}
+	return annotation.GetIPs()
+}
+
 // GetPodMAC is a convenience function to get the Pod MAC from annotation.
 // Returns empty string if annotation is not set or invalid.
 //
@@ -430,3 +488,114 @@ func GetPodSubnet(pod *corev1.Pod) string {
 	}
 	return annotation.Subnet
 }
+
+// GetPodStaticIPs parses the fixed IP addresses requested for a Pod.
+// Returns nil if the Pod does not request fixed IPs.
+//
+// Parameters:
+//   - pod: The Pod to get requested IPs from
+//
+// Returns:
+//   - []net.IP: Requested IP addresses
+//   - error: Parse error if the annotation contains an invalid IP
+func GetPodStaticIPs(pod *corev1.Pod) ([]net.IP, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	value := strings.TrimSpace(pod.Annotations[PodStaticIPAnnotationKey])
+	if value == "" {
+		return nil, nil
+	}
+	return parseIPList(value, PodStaticIPAnnotationKey)
+}
+
+// GetPodIPPool parses the IP pool a Pod may use.
+// Returns nil if the Pod has no IP pool.
+//
+// Parameters:
+//   - pod: The Pod to get the IP pool from
+//
+// Returns:
+//   - []net.IP: IP addresses of the pool, in order
+//   - error: Parse error if the annotation contains an invalid IP
+func GetPodIPPool(pod *corev1.Pod) ([]net.IP, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	value := strings.TrimSpace(pod.Annotations[PodIPPoolAnnotationKey])
+	if value == "" {
+		return nil, nil
+	}
+	return parseIPList(value, PodIPPoolAnnotationKey)
+}
+
+// parseIPList parses a comma-separated list of IP addresses from annotation key
+func parseIPList(value, key string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q in annotation %s", s, key)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// GetPodStaticMAC parses the fixed MAC address requested for a Pod.
+// Returns an empty string if the Pod does not request a fixed MAC.
+//
+// MACs starting with GeneratedMACPrefix are rejected, since they could
+// collide with the MAC generated from the IP of another Pod.
+//
+// Parameters:
+//   - pod: The Pod to get the requested MAC from
+//
+// Returns:
+//   - string: Requested MAC address in canonical form
+//   - error: Error if the MAC is invalid, not a unicast address or in the
+//     generated range
+func GetPodStaticMAC(pod *corev1.Pod) (string, error) {
+	if pod == nil || pod.Annotations == nil {
+		return "", nil
+	}
+	value := strings.TrimSpace(pod.Annotations[PodStaticMACAnnotationKey])
+	if value == "" {
+		return "", nil
+	}
+
+	mac, err := net.ParseMAC(value)
+	if err != nil || len(mac) != 6 {
+		return "", fmt.Errorf("invalid MAC address %q in annotation %s", value, PodStaticMACAnnotationKey)
+	}
+	if mac[0]&0x01 != 0 {
+		return "", fmt.Errorf("MAC address %q in annotation %s is not a unicast address", value, PodStaticMACAnnotationKey)
+	}
+	if strings.HasPrefix(mac.String(), GeneratedMACPrefix) {
+		return "", fmt.Errorf("MAC address %q in annotation %s is in the generated range %sxx:xx:xx:xx", value, PodStaticMACAnnotationKey, GeneratedMACPrefix)
+	}
+	return mac.String(), nil
+}
+
+// PodRequestsDPDK checks if a Pod requests a DPDK vhost-user port, either
+// through PodDPDKAnnotationKey or by requesting DPDKResourceName in one of
+// its containers.
+func PodRequestsDPDK(pod *corev1.Pod) bool {
+	if pod == nil {
+		return false
+	}
+	if strings.EqualFold(strings.TrimSpace(pod.Annotations[PodDPDKAnnotationKey]), "true") {
+		return true
+	}
+	containers := append(append([]corev1.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		if _, ok := c.Resources.Requests[DPDKResourceName]; ok {
+			return true
+		}
+		if _, ok := c.Resources.Limits[DPDKResourceName]; ok {
+			return true
+		}
+	}
+	return false
+}
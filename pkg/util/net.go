@@ -10,9 +10,14 @@ package util
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net"
+	"strconv"
 )
 
+// GeneratedMACPrefix is the first two bytes of the MACs built by GenerateMAC
+const GeneratedMACPrefix = "0a:58:"
+
 // ParseCIDR parses a CIDR string and returns the IP network
 //
 // Parameters:
@@ -62,7 +67,9 @@ func NextIP(ip net.IP) net.IP {
 }
 
 // GenerateMAC generates a MAC address from an IP address
-// Uses the OVN convention: 0a:58:xx:xx:xx:xx where xx is derived from IP
+// Uses the OVN convention: 0a:58:xx:xx:xx:xx where xx is derived from IP.
+// IPv4 addresses are copied; IPv6 addresses are hashed, since their last
+// 4 bytes repeat across the ranges of a subnet.
 //
 // Parameters:
 //   - ip: IP address
@@ -70,9 +77,34 @@ func NextIP(ip net.IP) net.IP {
 // Returns:
 //   - string: MAC address string
 func GenerateMAC(ip net.IP) string {
-	ip4 := ip.To4()
-	if ip4 == nil {
+	return GenerateAlternateMAC(ip, 0)
+}
+
+// GenerateAlternateMAC generates the n-th candidate MAC address of an IP,
+// used when the MACs of the previous candidates are already taken.
+// Candidate 0 is the MAC returned by GenerateMAC; the others are hashed
+// from the IP and n.
+//
+// Parameters:
+//   - ip: IP address
+//   - n: Candidate number
+//
+// Returns:
+//   - string: MAC address string
+func GenerateAlternateMAC(ip net.IP, n int) string {
+	if ip4 := ip.To4(); ip4 != nil && n == 0 {
+		return fmt.Sprintf(GeneratedMACPrefix+"%02x:%02x:%02x:%02x", ip4[0], ip4[1], ip4[2], ip4[3])
+	}
+	ip16 := ip.To16()
+	if ip16 == nil {
 		return ""
 	}
-	return fmt.Sprintf("0a:58:%02x:%02x:%02x:%02x", ip4[0], ip4[1], ip4[2], ip4[3])
+
+	h := fnv.New32a()
+	h.Write(ip16)
+	if n > 0 {
+		h.Write([]byte(strconv.Itoa(n)))
+	}
+	b := h.Sum(nil)
+	return fmt.Sprintf(GeneratedMACPrefix+"%02x:%02x:%02x:%02x", b[0], b[1], b[2], b[3])
 }
@@ -406,5 +406,58 @@ var _ = Describe("Pod Network Connectivity", func() {
 				_ = f.DeletePod(ctx, namespace, podName)
 			}
 		})
+
+		It("should allow a Pod to reach itself through its own Service", func() {
+			// The only backend is the client itself, so every connection is
+			// hairpinned back to the Pod that sent it
+
+			By("Creating server pod")
+			_, err := f.CreatePod(ctx, PodConfig{
+				Name:      serverPodName,
+				Namespace: namespace,
+				Image:     "busybox:1.36",
+				Command:   []string{"httpd", "-f", "-p", "80"},
+				Labels:    map[string]string{"app": "hairpin-test", "role": "server"},
+				Ports:     []int32{80},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Creating service selecting only the server pod")
+			_, err = f.CreateService(ctx, ServiceConfig{
+				Name:      serviceName,
+				Namespace: namespace,
+				Selector:  map[string]string{"app": "hairpin-test", "role": "server"},
+				Ports: []ServicePort{
+					{
+						Name:       "http",
+						Port:       80,
+						TargetPort: 80,
+						Protocol:   corev1.ProtocolTCP,
+					},
+				},
+				Type: corev1.ServiceTypeClusterIP,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Waiting for pod to be running")
+			_, err = f.WaitForPodRunning(ctx, namespace, serverPodName, DefaultPodTimeout)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Waiting for service endpoints")
+			err = f.WaitForServiceEndpoints(ctx, namespace, serviceName, DefaultServiceTimeout)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Getting service ClusterIP")
+			clusterIP, err := f.GetServiceClusterIP(ctx, namespace, serviceName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusterIP).NotTo(BeEmpty())
+
+			By(fmt.Sprintf("Testing TCP connectivity from %s to its own service %s (%s:80)", serverPodName, serviceName, clusterIP))
+			for i := 0; i < 3; i++ {
+				success, err := f.TestConnectivity(ctx, namespace, serverPodName, clusterIP, 80)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(success).To(BeTrue(), "Hairpin connection %d to own Service should succeed", i+1)
+			}
+		})
 	})
 })